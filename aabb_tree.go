@@ -0,0 +1,559 @@
+package maths
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	// aabbNull marks a missing node in the AABBTree
+	aabbNull = -1
+	// aabbMultiplier scales the displacement used to predict the fat bounds of moving proxies
+	aabbMultiplier = 4.0
+)
+
+// aabbNode is a node of the AABBTree, leaves hold the proxies
+type aabbNode[V any] struct {
+	bounds Rect
+	value  V
+	parent int
+	child1 int
+	child2 int
+	// height is 0 for leaves and -1 for free nodes
+	height int
+	moved  bool
+}
+
+// isLeaf reports whether the node is a leaf
+func (node *aabbNode[V]) isLeaf() bool {
+	return node.child1 == aabbNull
+}
+
+// AABBTree is a dynamic bounding volume hierarchy for broad-phase collision.
+// Proxies are stored with fat bounds so small movements do not touch the tree.
+// The tree is balanced with rotations and all operations are deterministic, the
+// same sequence of calls always produces the same proxy ids and pair orders.
+type AABBTree[V any] struct {
+	nodes      []aabbNode[V]
+	root       int
+	free       int
+	margin     float64
+	proxyCount int
+	moveBuffer []int
+}
+
+// NewAABBTree creates a new empty tree which fattens proxy bounds by margin
+func NewAABBTree[V any](margin float64) *AABBTree[V] {
+	return &AABBTree[V]{
+		root:   aabbNull,
+		free:   aabbNull,
+		margin: margin,
+	}
+}
+
+// Len returns the number of proxies in the tree
+func (tree *AABBTree[V]) Len() int {
+	return tree.proxyCount
+}
+
+// CreateProxy adds a proxy with the bounds and returns its id
+func (tree *AABBTree[V]) CreateProxy(bounds Rect, value V) int {
+	id := tree.allocateNode()
+	node := &tree.nodes[id]
+	node.bounds = bounds.Expand(tree.margin)
+	node.value = value
+	node.height = 0
+	node.moved = true
+
+	tree.insertLeaf(id)
+	tree.proxyCount++
+	tree.moveBuffer = append(tree.moveBuffer, id)
+	return id
+}
+
+// DestroyProxy removes a proxy from the tree
+func (tree *AABBTree[V]) DestroyProxy(id int) {
+	if !tree.isProxy(id) {
+		return
+	}
+
+	tree.removeLeaf(id)
+	tree.freeNode(id)
+	tree.proxyCount--
+
+	for i, moved := range tree.moveBuffer {
+		if moved == id {
+			tree.moveBuffer = append(tree.moveBuffer[:i], tree.moveBuffer[i+1:]...)
+			break
+		}
+	}
+}
+
+// MoveProxy updates the bounds of a proxy. The displacement is used to predict
+// the movement and enlarge the fat bounds in that direction. It returns true
+// when the proxy had to be reinserted into the tree.
+func (tree *AABBTree[V]) MoveProxy(id int, bounds Rect, displacement Vector2D[float64]) bool {
+	if !tree.isProxy(id) {
+		return false
+	}
+
+	fat := bounds.Expand(tree.margin)
+
+	// Predict the movement
+	d := displacement.Multiply(aabbMultiplier)
+	if d.X < 0 {
+		fat.Min.X += d.X
+	} else {
+		fat.Max.X += d.X
+	}
+	if d.Y < 0 {
+		fat.Min.Y += d.Y
+	} else {
+		fat.Max.Y += d.Y
+	}
+
+	treeBounds := tree.nodes[id].bounds
+	if treeBounds.ContainsRect(bounds) {
+		// Keep the fat bounds unless they became far too large
+		huge := fat.Expand(4 * tree.margin)
+		if huge.ContainsRect(treeBounds) {
+			return false
+		}
+	}
+
+	tree.removeLeaf(id)
+	tree.nodes[id].bounds = fat
+	tree.insertLeaf(id)
+
+	if !tree.nodes[id].moved {
+		tree.nodes[id].moved = true
+		tree.moveBuffer = append(tree.moveBuffer, id)
+	}
+	return true
+}
+
+// Value returns the value of a proxy
+func (tree *AABBTree[V]) Value(id int) V {
+	return tree.nodes[id].value
+}
+
+// FatBounds returns the enlarged bounds stored for a proxy
+func (tree *AABBTree[V]) FatBounds(id int) Rect {
+	return tree.nodes[id].bounds
+}
+
+// Query calls the callback for every proxy whose fat bounds overlap the area.
+// Returning false from the callback stops the query.
+func (tree *AABBTree[V]) Query(area Rect, callback func(id int) bool) {
+	tree.query(area, callback)
+}
+
+// RayCast calls the callback for every proxy whose fat bounds are hit by the ray
+// from origin along direction up to maxDistance. The callback returns the new
+// maximum distance of the ray: 0 stops the cast, a negative value ignores the
+// proxy and any other value clips the ray.
+func (tree *AABBTree[V]) RayCast(
+	origin Vector2D[float64],
+	direction Vector2D[float64],
+	maxDistance float64,
+	callback func(id int, maxDistance float64) float64,
+) {
+	if tree.root == aabbNull {
+		return
+	}
+
+	dir := direction.Normalize()
+	stack := []int{tree.root}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		node := &tree.nodes[id]
+		if _, ok := node.bounds.IntersectRay(origin, dir, maxDistance); !ok {
+			continue
+		}
+
+		if !node.isLeaf() {
+			stack = append(stack, node.child2, node.child1)
+			continue
+		}
+
+		distance := callback(id, maxDistance)
+		if distance == 0 {
+			return
+		}
+		if distance > 0 {
+			maxDistance = math.Min(maxDistance, distance)
+		}
+	}
+}
+
+// OverlapPairs returns all pairs of proxies with overlapping fat bounds.
+// Each pair holds the smaller id first and the pairs are sorted.
+func (tree *AABBTree[V]) OverlapPairs() [][2]int {
+	var pairs [][2]int
+	for id := range tree.nodes {
+		if !tree.isProxy(id) {
+			continue
+		}
+		tree.query(tree.nodes[id].bounds, func(other int) bool {
+			if other > id {
+				pairs = append(pairs, [2]int{id, other})
+			}
+			return true
+		})
+	}
+
+	sortPairs(pairs)
+	return pairs
+}
+
+// UpdatePairs calls the callback for every pair of overlapping proxies where at
+// least one proxy was created or reinserted since the last call. Each pair holds
+// the smaller id first and the pairs are reported sorted and only once.
+func (tree *AABBTree[V]) UpdatePairs(callback func(a, b int)) {
+	var pairs [][2]int
+	for _, id := range tree.moveBuffer {
+		tree.query(tree.nodes[id].bounds, func(other int) bool {
+			// Both proxies moved, only report the pair once
+			if other == id || (tree.nodes[other].moved && other < id) {
+				return true
+			}
+			pairs = append(pairs, [2]int{min(id, other), max(id, other)})
+			return true
+		})
+	}
+
+	for _, id := range tree.moveBuffer {
+		tree.nodes[id].moved = false
+	}
+	tree.moveBuffer = tree.moveBuffer[:0]
+
+	sortPairs(pairs)
+	for _, pair := range pairs {
+		callback(pair[0], pair[1])
+	}
+}
+
+// Height returns the height of the tree, 0 for a single leaf
+func (tree *AABBTree[V]) Height() int {
+	if tree.root == aabbNull {
+		return 0
+	}
+	return tree.nodes[tree.root].height
+}
+
+// MaxBalance returns the largest height difference between two sibling nodes
+func (tree *AABBTree[V]) MaxBalance() int {
+	maxBalance := 0
+	for i := range tree.nodes {
+		node := &tree.nodes[i]
+		if node.height <= 1 {
+			continue
+		}
+		balance := tree.nodes[node.child2].height - tree.nodes[node.child1].height
+		maxBalance = max(maxBalance, balance, -balance)
+	}
+	return maxBalance
+}
+
+// AreaRatio returns the ratio of the summed perimeters of all nodes to the root perimeter
+func (tree *AABBTree[V]) AreaRatio() float64 {
+	if tree.root == aabbNull {
+		return 0
+	}
+
+	rootArea := perimeter(tree.nodes[tree.root].bounds)
+	totalArea := 0.0
+	for i := range tree.nodes {
+		if tree.nodes[i].height >= 0 {
+			totalArea += perimeter(tree.nodes[i].bounds)
+		}
+	}
+	return totalArea / rootArea
+}
+
+// ShiftOrigin moves all bounds by -offset, used when the world origin changes
+func (tree *AABBTree[V]) ShiftOrigin(offset Vector2D[float64]) {
+	for i := range tree.nodes {
+		tree.nodes[i].bounds.Min = tree.nodes[i].bounds.Min.Subtract(offset)
+		tree.nodes[i].bounds.Max = tree.nodes[i].bounds.Max.Subtract(offset)
+	}
+}
+
+// isProxy reports whether the id refers to a live leaf
+func (tree *AABBTree[V]) isProxy(id int) bool {
+	return id >= 0 && id < len(tree.nodes) &&
+		tree.nodes[id].height == 0 && tree.nodes[id].isLeaf()
+}
+
+// query walks the tree and reports every leaf overlapping the area
+func (tree *AABBTree[V]) query(area Rect, callback func(id int) bool) {
+	if tree.root == aabbNull {
+		return
+	}
+
+	stack := []int{tree.root}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		node := &tree.nodes[id]
+		if !node.bounds.Intersects(area) {
+			continue
+		}
+
+		if !node.isLeaf() {
+			stack = append(stack, node.child2, node.child1)
+			continue
+		}
+		if !callback(id) {
+			return
+		}
+	}
+}
+
+// allocateNode returns a node from the free list or grows the node pool
+func (tree *AABBTree[V]) allocateNode() int {
+	if tree.free == aabbNull {
+		tree.nodes = append(tree.nodes, aabbNode[V]{})
+		tree.free = len(tree.nodes) - 1
+		tree.nodes[tree.free].parent = aabbNull
+		tree.nodes[tree.free].height = -1
+	}
+
+	id := tree.free
+	tree.free = tree.nodes[id].parent
+	tree.nodes[id] = aabbNode[V]{
+		parent: aabbNull,
+		child1: aabbNull,
+		child2: aabbNull,
+	}
+	return id
+}
+
+// freeNode returns a node to the free list
+func (tree *AABBTree[V]) freeNode(id int) {
+	tree.nodes[id] = aabbNode[V]{
+		parent: tree.free,
+		child1: aabbNull,
+		child2: aabbNull,
+		height: -1,
+	}
+	tree.free = id
+}
+
+// insertLeaf finds the cheapest sibling for the leaf and rebalances the path to the root
+func (tree *AABBTree[V]) insertLeaf(leaf int) {
+	if tree.root == aabbNull {
+		tree.root = leaf
+		tree.nodes[leaf].parent = aabbNull
+		return
+	}
+
+	// Find the best sibling
+	leafBounds := tree.nodes[leaf].bounds
+	index := tree.root
+	for !tree.nodes[index].isLeaf() {
+		node := &tree.nodes[index]
+		area := perimeter(node.bounds)
+		combinedArea := perimeter(node.bounds.Union(leafBounds))
+
+		// Cost of creating a new parent for this node and the new leaf
+		cost := 2 * combinedArea
+		// Minimum cost of pushing the leaf further down the tree
+		inheritanceCost := 2 * (combinedArea - area)
+
+		cost1 := tree.descendCost(node.child1, leafBounds) + inheritanceCost
+		cost2 := tree.descendCost(node.child2, leafBounds) + inheritanceCost
+
+		if cost < cost1 && cost < cost2 {
+			break
+		}
+		if cost1 < cost2 {
+			index = node.child1
+		} else {
+			index = node.child2
+		}
+	}
+	sibling := index
+
+	// Create a new parent
+	oldParent := tree.nodes[sibling].parent
+	newParent := tree.allocateNode()
+	tree.nodes[newParent].parent = oldParent
+	tree.nodes[newParent].bounds = leafBounds.Union(tree.nodes[sibling].bounds)
+	tree.nodes[newParent].height = tree.nodes[sibling].height + 1
+	tree.nodes[newParent].child1 = sibling
+	tree.nodes[newParent].child2 = leaf
+	tree.nodes[sibling].parent = newParent
+	tree.nodes[leaf].parent = newParent
+
+	if oldParent == aabbNull {
+		tree.root = newParent
+	} else if tree.nodes[oldParent].child1 == sibling {
+		tree.nodes[oldParent].child1 = newParent
+	} else {
+		tree.nodes[oldParent].child2 = newParent
+	}
+
+	tree.refit(tree.nodes[leaf].parent)
+}
+
+// descendCost returns the cost of inserting the bounds below the node
+func (tree *AABBTree[V]) descendCost(id int, bounds Rect) float64 {
+	node := &tree.nodes[id]
+	if node.isLeaf() {
+		return perimeter(bounds.Union(node.bounds))
+	}
+	return perimeter(bounds.Union(node.bounds)) - perimeter(node.bounds)
+}
+
+// removeLeaf unlinks the leaf and replaces its parent by the sibling
+func (tree *AABBTree[V]) removeLeaf(leaf int) {
+	if leaf == tree.root {
+		tree.root = aabbNull
+		return
+	}
+
+	parent := tree.nodes[leaf].parent
+	grandParent := tree.nodes[parent].parent
+	sibling := tree.nodes[parent].child1
+	if sibling == leaf {
+		sibling = tree.nodes[parent].child2
+	}
+
+	tree.freeNode(parent)
+	tree.nodes[leaf].parent = aabbNull
+
+	if grandParent == aabbNull {
+		tree.root = sibling
+		tree.nodes[sibling].parent = aabbNull
+		return
+	}
+
+	if tree.nodes[grandParent].child1 == parent {
+		tree.nodes[grandParent].child1 = sibling
+	} else {
+		tree.nodes[grandParent].child2 = sibling
+	}
+	tree.nodes[sibling].parent = grandParent
+	tree.refit(grandParent)
+}
+
+// refit balances and recomputes bounds and heights from the node up to the root
+func (tree *AABBTree[V]) refit(index int) {
+	for index != aabbNull {
+		index = tree.balance(index)
+
+		node := &tree.nodes[index]
+		child1 := &tree.nodes[node.child1]
+		child2 := &tree.nodes[node.child2]
+		node.height = 1 + max(child1.height, child2.height)
+		node.bounds = child1.bounds.Union(child2.bounds)
+
+		index = node.parent
+	}
+}
+
+// balance performs a left or right rotation if node a is imbalanced and returns the new subtree root
+func (tree *AABBTree[V]) balance(iA int) int {
+	a := &tree.nodes[iA]
+	if a.isLeaf() || a.height < 2 {
+		return iA
+	}
+
+	iB, iC := a.child1, a.child2
+	b, c := &tree.nodes[iB], &tree.nodes[iC]
+	balance := c.height - b.height
+
+	// Rotate c up
+	if balance > 1 {
+		iF, iG := c.child1, c.child2
+		f, g := &tree.nodes[iF], &tree.nodes[iG]
+
+		c.child1 = iA
+		c.parent = a.parent
+		a.parent = iC
+		tree.replaceChild(c.parent, iA, iC)
+
+		if f.height > g.height {
+			c.child2 = iF
+			a.child2 = iG
+			g.parent = iA
+			a.bounds = b.bounds.Union(g.bounds)
+			c.bounds = a.bounds.Union(f.bounds)
+			a.height = 1 + max(b.height, g.height)
+			c.height = 1 + max(a.height, f.height)
+		} else {
+			c.child2 = iG
+			a.child2 = iF
+			f.parent = iA
+			a.bounds = b.bounds.Union(f.bounds)
+			c.bounds = a.bounds.Union(g.bounds)
+			a.height = 1 + max(b.height, f.height)
+			c.height = 1 + max(a.height, g.height)
+		}
+		return iC
+	}
+
+	// Rotate b up
+	if balance < -1 {
+		iD, iE := b.child1, b.child2
+		d, e := &tree.nodes[iD], &tree.nodes[iE]
+
+		b.child1 = iA
+		b.parent = a.parent
+		a.parent = iB
+		tree.replaceChild(b.parent, iA, iB)
+
+		if d.height > e.height {
+			b.child2 = iD
+			a.child1 = iE
+			e.parent = iA
+			a.bounds = c.bounds.Union(e.bounds)
+			b.bounds = a.bounds.Union(d.bounds)
+			a.height = 1 + max(c.height, e.height)
+			b.height = 1 + max(a.height, d.height)
+		} else {
+			b.child2 = iE
+			a.child1 = iD
+			d.parent = iA
+			a.bounds = c.bounds.Union(d.bounds)
+			b.bounds = a.bounds.Union(e.bounds)
+			a.height = 1 + max(c.height, d.height)
+			b.height = 1 + max(a.height, e.height)
+		}
+		return iB
+	}
+
+	return iA
+}
+
+// replaceChild points the parent to the new child, or makes it the root
+func (tree *AABBTree[V]) replaceChild(parent, oldChild, newChild int) {
+	if parent == aabbNull {
+		tree.root = newChild
+		return
+	}
+	if tree.nodes[parent].child1 == oldChild {
+		tree.nodes[parent].child1 = newChild
+	} else {
+		tree.nodes[parent].child2 = newChild
+	}
+}
+
+// perimeter returns the perimeter of the rectangle, used as cost metric in 2D
+func perimeter(r Rect) float64 {
+	return 2 * (r.Width() + r.Height())
+}
+
+// sortPairs orders id pairs lexicographically
+func sortPairs(pairs [][2]int) {
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i][0] != pairs[j][0] {
+			return pairs[i][0] < pairs[j][0]
+		}
+		return pairs[i][1] < pairs[j][1]
+	})
+}
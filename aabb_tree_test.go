@@ -0,0 +1,218 @@
+package maths
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// validateAABBTree checks the structural invariants of the tree
+func validateAABBTree[V any](t *testing.T, tree *AABBTree[V]) {
+	t.Helper()
+
+	leaves := 0
+	var walk func(id, parent int) int
+	walk = func(id, parent int) int {
+		node := tree.nodes[id]
+		assert.Equal(t, parent, node.parent)
+		if node.isLeaf() {
+			assert.Equal(t, 0, node.height)
+			leaves++
+			return 0
+		}
+
+		h1 := walk(node.child1, id)
+		h2 := walk(node.child2, id)
+		assert.Equal(t, 1+max(h1, h2), node.height)
+		assert.Equal(t, tree.nodes[node.child1].bounds.Union(tree.nodes[node.child2].bounds), node.bounds)
+		return node.height
+	}
+
+	if tree.root != aabbNull {
+		walk(tree.root, aabbNull)
+	}
+	assert.Equal(t, tree.Len(), leaves)
+	assert.LessOrEqual(t, tree.MaxBalance(), 1)
+}
+
+// bruteOverlapPairs returns all overlapping pairs of fat bounds
+func bruteOverlapPairs[V any](tree *AABBTree[V], ids []int) [][2]int {
+	var pairs [][2]int
+	for i, a := range ids {
+		for _, b := range ids[i+1:] {
+			if tree.FatBounds(a).Intersects(tree.FatBounds(b)) {
+				pairs = append(pairs, [2]int{min(a, b), max(a, b)})
+			}
+		}
+	}
+	sortPairs(pairs)
+	return pairs
+}
+
+// buildAABBTree fills a tree with boxes and moves some of them
+func buildAABBTree() (*AABBTree[int], []int) {
+	bounds := NewRect(NewVector2D[float64](0, 0), NewVector2D[float64](200, 200))
+	tree := NewAABBTree[int](0.5)
+
+	var ids []int
+	for i, p := range testPoints(300, bounds) {
+		size := NewVector2D(1+float64(i%4), 1+float64(i%3))
+		ids = append(ids, tree.CreateProxy(NewRectFromCenter(p, size), i))
+	}
+	for i := 0; i < len(ids); i += 7 {
+		tree.DestroyProxy(ids[i])
+	}
+	var alive []int
+	for i, id := range ids {
+		if i%7 == 0 {
+			continue
+		}
+		alive = append(alive, id)
+	}
+	for step := 0; step < 5; step++ {
+		for i, id := range alive {
+			if i%3 != step%3 {
+				continue
+			}
+			displacement := NewVector2D(math.Cos(float64(i+step)), math.Sin(float64(i+step))).Multiply(3)
+			bounds := tree.FatBounds(id).Expand(-0.5)
+			tree.MoveProxy(id, NewRect(bounds.Min.Add(displacement), bounds.Max.Add(displacement)), displacement)
+		}
+	}
+	return tree, alive
+}
+
+func TestAABBTreeProxies(t *testing.T) {
+	t.Parallel()
+
+	tree := NewAABBTree[string](0.1)
+	a := tree.CreateProxy(NewRect(NewVector2D[float64](0, 0), NewVector2D[float64](1, 1)), "a")
+	b := tree.CreateProxy(NewRect(NewVector2D[float64](5, 5), NewVector2D[float64](6, 6)), "b")
+	assert.Equal(t, 2, tree.Len())
+	assert.Equal(t, "b", tree.Value(b))
+	assert.Equal(t, NewRect(NewVector2D(-0.1, -0.1), NewVector2D(1.1, 1.1)), tree.FatBounds(a))
+
+	// small movements stay inside the fat bounds
+	assert.False(t, tree.MoveProxy(a, NewRect(NewVector2D(0.05, 0), NewVector2D(1.05, 1)), NewVector2D(0.05, 0)))
+	assert.True(t, tree.MoveProxy(a, NewRect(NewVector2D[float64](4.5, 4.5), NewVector2D[float64](5.5, 5.5)), NewVector2D[float64](0.5, 0)))
+	assert.Equal(t, [][2]int{{a, b}}, tree.OverlapPairs())
+
+	tree.DestroyProxy(b)
+	assert.Equal(t, 1, tree.Len())
+	assert.Empty(t, tree.OverlapPairs())
+	validateAABBTree(t, tree)
+
+	// freed nodes are reused
+	c := tree.CreateProxy(NewRect(NewVector2D[float64](0, 0), NewVector2D[float64](1, 1)), "c")
+	assert.Less(t, c, 3)
+}
+
+func TestAABBTreeBalanceAndPairs(t *testing.T) {
+	t.Parallel()
+
+	tree, ids := buildAABBTree()
+	validateAABBTree(t, tree)
+	assert.LessOrEqual(t, tree.Height(), 20)
+	assert.Greater(t, tree.AreaRatio(), 1.0)
+	assert.Equal(t, bruteOverlapPairs(tree, ids), tree.OverlapPairs())
+
+	var area []int
+	query := NewRect(NewVector2D[float64](50, 50), NewVector2D[float64](80, 90))
+	tree.Query(query, func(id int) bool {
+		area = append(area, id)
+		return true
+	})
+	var expected []int
+	for _, id := range ids {
+		if tree.FatBounds(id).Intersects(query) {
+			expected = append(expected, id)
+		}
+	}
+	assert.ElementsMatch(t, expected, area)
+}
+
+func TestAABBTreeUpdatePairs(t *testing.T) {
+	t.Parallel()
+
+	tree := NewAABBTree[int](0)
+	a := tree.CreateProxy(NewRect(NewVector2D[float64](0, 0), NewVector2D[float64](2, 2)), 0)
+	b := tree.CreateProxy(NewRect(NewVector2D[float64](1, 1), NewVector2D[float64](3, 3)), 1)
+	c := tree.CreateProxy(NewRect(NewVector2D[float64](10, 10), NewVector2D[float64](11, 11)), 2)
+
+	var pairs [][2]int
+	collect := func(x, y int) { pairs = append(pairs, [2]int{x, y}) }
+
+	tree.UpdatePairs(collect)
+	assert.Equal(t, [][2]int{{a, b}}, pairs)
+
+	// nothing moved
+	pairs = nil
+	tree.UpdatePairs(collect)
+	assert.Empty(t, pairs)
+
+	pairs = nil
+	tree.MoveProxy(c, NewRect(NewVector2D[float64](2, 2), NewVector2D[float64](4, 4)), NewVector2D[float64](0, 0))
+	tree.UpdatePairs(collect)
+	assert.Equal(t, [][2]int{{a, c}, {b, c}}, pairs)
+}
+
+func TestAABBTreeDeterministic(t *testing.T) {
+	t.Parallel()
+
+	tree1, _ := buildAABBTree()
+	tree2, _ := buildAABBTree()
+
+	var pairs1, pairs2 [][2]int
+	tree1.UpdatePairs(func(a, b int) { pairs1 = append(pairs1, [2]int{a, b}) })
+	tree2.UpdatePairs(func(a, b int) { pairs2 = append(pairs2, [2]int{a, b}) })
+	assert.NotEmpty(t, pairs1)
+	assert.Equal(t, pairs1, pairs2)
+	assert.Equal(t, tree1.nodes, tree2.nodes)
+}
+
+func TestAABBTreeRayCast(t *testing.T) {
+	t.Parallel()
+
+	tree, ids := buildAABBTree()
+	origin := NewVector2D[float64](0, 100)
+	direction := NewVector2D[float64](1, 0.2)
+
+	var hits []int
+	tree.RayCast(origin, direction, 300, func(id int, _ float64) float64 {
+		hits = append(hits, id)
+		return -1
+	})
+	var expected []int
+	closest, closestDistance := -1, math.Inf(1)
+	for _, id := range ids {
+		if d, ok := tree.FatBounds(id).IntersectRay(origin, direction.Normalize(), 300); ok {
+			expected = append(expected, id)
+			if d < closestDistance {
+				closest, closestDistance = id, d
+			}
+		}
+	}
+	assert.NotEmpty(t, expected)
+	assert.ElementsMatch(t, expected, hits)
+
+	// clipping the ray finds the closest proxy
+	found := -1
+	tree.RayCast(origin, direction, 300, func(id int, maxDistance float64) float64 {
+		d, ok := tree.FatBounds(id).IntersectRay(origin, direction.Normalize(), maxDistance)
+		if !ok {
+			return -1
+		}
+		found = id
+		return d
+	})
+	assert.Equal(t, closest, found)
+
+	// stop on first hit
+	count := 0
+	tree.RayCast(origin, direction, 300, func(int, float64) float64 {
+		count++
+		return 0
+	})
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,32 @@
+package maths
+
+import (
+	"math"
+)
+
+// HexAStar finds the cheapest path from start to goal with A*. Entering a hex
+// costs what the cost function returns for it, see HexCostFunc. MinCost is the
+// lowest cost of any passable hex, which keeps the hex distance heuristic
+// admissible. The path includes start and goal. The cost function has to
+// mark the outside of the map impassable, otherwise searching for an
+// unreachable goal never ends.
+func HexAStar(start, goal Hex[int64], cost HexCostFunc, minCost float64) ([]Hex[int64], float64, bool) {
+	// The layout only places hexes in the world, the search does not need it
+	return GridAStar[Hex[int64]](HexTopology{}, start, goal, cost, minCost)
+}
+
+// hexAStar is HexAStar limited to the hexes accepted by allowed, nil allows every hex
+func hexAStar(
+	start, goal Hex[int64],
+	cost HexCostFunc,
+	minCost float64,
+	allowed func(h Hex[int64]) bool,
+) ([]Hex[int64], float64, bool) {
+	return gridAStar[Hex[int64]](HexTopology{}, start, goal, cost, minCost, allowed)
+}
+
+// passable reports whether the cost function allows entering the cell
+func passable[C comparable](cost func(cell C) float64, cell C) bool {
+	c := cost(cell)
+	return c >= 0 && !math.IsInf(c, 1) && !math.IsNaN(c)
+}
@@ -0,0 +1,82 @@
+package maths
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// assertPath checks that the path connects start and goal over neighbouring passable hexes and costs the given amount
+func assertPath(t *testing.T, path []Hex[int64], start, goal Hex[int64], cost HexCostFunc, expected float64) {
+	t.Helper()
+
+	if !assert.NotEmpty(t, path) {
+		return
+	}
+	assert.Equal(t, start, path[0])
+	assert.Equal(t, goal, path[len(path)-1])
+
+	paid := 0.0
+	for i := 1; i < len(path); i++ {
+		assert.Equal(t, 1.0, path[i].Distance(path[i-1]))
+		assert.True(t, passable(cost, path[i]))
+		paid += cost(path[i])
+	}
+	assert.InDelta(t, expected, paid, 1e-9)
+}
+
+func TestHexAStar(t *testing.T) {
+	t.Parallel()
+
+	costs := make(HexMap[float64])
+	for _, h := range (Hex[int64]{Q: 0, R: -4}).LineTo(Hex[int64]{Q: 0, R: 4}) {
+		costs[h] = -1
+	}
+	costs[Hex[int64]{Q: 0, R: 3}] = 3 // expensive gap
+	costs[Hex[int64]{Q: 3, R: 0}] = 0.5
+	cost := testCosts(6, costs)
+
+	tests := []struct {
+		name       string
+		start      Hex[int64]
+		goal       Hex[int64]
+		reachable  bool
+		costFromBF bool
+	}{
+		{name: "Around the wall", start: Hex[int64]{Q: -3, R: 0}, goal: Hex[int64]{Q: 3, R: 0}, reachable: true},
+		{name: "Same hex", start: Hex[int64]{Q: 2, R: 1}, goal: Hex[int64]{Q: 2, R: 1}, reachable: true},
+		{name: "Neighbour", start: Hex[int64]{Q: 2, R: 1}, goal: Hex[int64]{Q: 3, R: 1}, reachable: true},
+		{name: "Goal blocked", start: Hex[int64]{Q: -3, R: 0}, goal: Hex[int64]{Q: 0, R: 0}},
+		{name: "Goal outside", start: Hex[int64]{Q: -3, R: 0}, goal: Hex[int64]{Q: 10, R: 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path, pathCost, ok := HexAStar(tt.start, tt.goal, cost, 0.5)
+			assert.Equal(t, tt.reachable, ok)
+			if !tt.reachable {
+				assert.Empty(t, path)
+				return
+			}
+
+			expected := bruteDistances(6, []Hex[int64]{tt.goal}, cost)[tt.start]
+			assert.InDelta(t, expected, pathCost, 1e-9)
+			assertPath(t, path, tt.start, tt.goal, cost, expected)
+		})
+	}
+}
+
+func TestHexAStarUnreachable(t *testing.T) {
+	t.Parallel()
+
+	// Goal enclosed by walls
+	costs := make(HexMap[float64])
+	for _, h := range (Hex[int64]{Q: 2, R: 2}).SpiralRing(1) {
+		costs[h] = math.Inf(1)
+	}
+	_, _, ok := HexAStar(Hex[int64]{}, Hex[int64]{Q: 2, R: 2}, testCosts(5, costs), 1)
+	assert.False(t, ok)
+}
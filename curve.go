@@ -0,0 +1,431 @@
+package maths
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	// curveLengthSteps is the number of intervals integrated separately to measure curve lengths
+	curveLengthSteps = 8
+	// curveClosestSamples is the number of samples taken before refining a closest point
+	curveClosestSamples = 32
+	// catmullRomAlpha is the knot exponent of centripetal Catmull-Rom splines
+	catmullRomAlpha = 0.5
+)
+
+// gaussLegendre5 are the nodes and weights of the five point Gauss-Legendre quadrature on [-1, 1]
+var gaussLegendre5 = [5][2]float64{
+	{0, 0.5688888888888889},
+	{-0.5384693101056831, 0.4786286704993665},
+	{0.5384693101056831, 0.4786286704993665},
+	{-0.9061798459386640, 0.2369268850561891},
+	{0.9061798459386640, 0.2369268850561891},
+}
+
+// Curve is a parametric curve over t in [0, 1]
+type Curve interface {
+	// Point returns the position at t
+	Point(t float64) Vector2D[float64]
+	// Derivative returns the first derivative at t
+	Derivative(t float64) Vector2D[float64]
+}
+
+// QuadraticBezier is a Bezier curve with one control point
+type QuadraticBezier struct {
+	P0, P1, P2 Vector2D[float64]
+}
+
+// Point returns the position at t
+func (b QuadraticBezier) Point(t float64) Vector2D[float64] {
+	u := 1 - t
+	return b.P0.Multiply(u * u).Add(b.P1.Multiply(2 * u * t)).Add(b.P2.Multiply(t * t))
+}
+
+// Derivative returns the first derivative at t
+func (b QuadraticBezier) Derivative(t float64) Vector2D[float64] {
+	return b.P1.Subtract(b.P0).Multiply(2 * (1 - t)).Add(b.P2.Subtract(b.P1).Multiply(2 * t))
+}
+
+// Tangent returns the normalized direction at t
+func (b QuadraticBezier) Tangent(t float64) Vector2D[float64] {
+	return curveTangent(b, t)
+}
+
+// Length returns the arc length of the curve
+func (b QuadraticBezier) Length() float64 {
+	return curveLength(b, 0, 1)
+}
+
+// Split divides the curve at t into two curves covering [0, t] and [t, 1]
+func (b QuadraticBezier) Split(t float64) (QuadraticBezier, QuadraticBezier) {
+	p01 := lerpVector(b.P0, b.P1, t)
+	p12 := lerpVector(b.P1, b.P2, t)
+	mid := lerpVector(p01, p12, t)
+	return QuadraticBezier{P0: b.P0, P1: p01, P2: mid}, QuadraticBezier{P0: mid, P1: p12, P2: b.P2}
+}
+
+// Bounds returns the tight bounding rectangle of the curve
+func (b QuadraticBezier) Bounds() Rect {
+	bounds := NewRect(b.P0, b.P2)
+	denominator := b.P0.Subtract(b.P1.Multiply(2)).Add(b.P2)
+	for _, t := range []float64{
+		(b.P0.X - b.P1.X) / denominator.X,
+		(b.P0.Y - b.P1.Y) / denominator.Y,
+	} {
+		if t > 0 && t < 1 {
+			p := b.Point(t)
+			bounds = bounds.Union(NewRect(p, p))
+		}
+	}
+	return bounds
+}
+
+// ClosestPoint returns the parameter and position of the point on the curve closest to p
+func (b QuadraticBezier) ClosestPoint(p Vector2D[float64]) (float64, Vector2D[float64]) {
+	return closestOnCurve(b, p, 0, 1, curveClosestSamples)
+}
+
+// CubicBezier is a Bezier curve with two control points
+type CubicBezier struct {
+	P0, P1, P2, P3 Vector2D[float64]
+}
+
+// Point returns the position at t
+func (b CubicBezier) Point(t float64) Vector2D[float64] {
+	u := 1 - t
+	return b.P0.Multiply(u * u * u).
+		Add(b.P1.Multiply(3 * u * u * t)).
+		Add(b.P2.Multiply(3 * u * t * t)).
+		Add(b.P3.Multiply(t * t * t))
+}
+
+// Derivative returns the first derivative at t
+func (b CubicBezier) Derivative(t float64) Vector2D[float64] {
+	u := 1 - t
+	return b.P1.Subtract(b.P0).Multiply(3 * u * u).
+		Add(b.P2.Subtract(b.P1).Multiply(6 * u * t)).
+		Add(b.P3.Subtract(b.P2).Multiply(3 * t * t))
+}
+
+// Tangent returns the normalized direction at t
+func (b CubicBezier) Tangent(t float64) Vector2D[float64] {
+	return curveTangent(b, t)
+}
+
+// Length returns the arc length of the curve
+func (b CubicBezier) Length() float64 {
+	return curveLength(b, 0, 1)
+}
+
+// Split divides the curve at t into two curves covering [0, t] and [t, 1]
+func (b CubicBezier) Split(t float64) (CubicBezier, CubicBezier) {
+	p01 := lerpVector(b.P0, b.P1, t)
+	p12 := lerpVector(b.P1, b.P2, t)
+	p23 := lerpVector(b.P2, b.P3, t)
+	p012 := lerpVector(p01, p12, t)
+	p123 := lerpVector(p12, p23, t)
+	mid := lerpVector(p012, p123, t)
+	return CubicBezier{P0: b.P0, P1: p01, P2: p012, P3: mid}, CubicBezier{P0: mid, P1: p123, P2: p23, P3: b.P3}
+}
+
+// Bounds returns the tight bounding rectangle of the curve
+func (b CubicBezier) Bounds() Rect {
+	bounds := NewRect(b.P0, b.P3)
+
+	// Extremes are where the derivative of an axis is zero
+	axis := func(p0, p1, p2, p3 float64) {
+		a := 3 * (-p0 + 3*p1 - 3*p2 + p3)
+		c := 3 * (p1 - p0)
+		for _, t := range quadraticRoots(a, 6*(p0-2*p1+p2), c) {
+			if t > 0 && t < 1 {
+				p := b.Point(t)
+				bounds = bounds.Union(NewRect(p, p))
+			}
+		}
+	}
+	axis(b.P0.X, b.P1.X, b.P2.X, b.P3.X)
+	axis(b.P0.Y, b.P1.Y, b.P2.Y, b.P3.Y)
+	return bounds
+}
+
+// ClosestPoint returns the parameter and position of the point on the curve closest to p
+func (b CubicBezier) ClosestPoint(p Vector2D[float64]) (float64, Vector2D[float64]) {
+	return closestOnCurve(b, p, 0, 1, curveClosestSamples)
+}
+
+// Spline is a chain of cubic Bezier segments. The parameter t in [0, 1] covers
+// all segments, each one taking an equal share.
+type Spline struct {
+	Segments []CubicBezier
+}
+
+// NewCatmullRom creates a centripetal Catmull-Rom spline passing through all
+// points. Centripetal splines neither form cusps nor loops within a segment.
+// The end tangents point along the first and last segment.
+func NewCatmullRom(points []Vector2D[float64]) *Spline {
+	switch len(points) {
+	case 0:
+		return &Spline{}
+	case 1:
+		p := points[0]
+		return &Spline{Segments: []CubicBezier{{P0: p, P1: p, P2: p, P3: p}}}
+	}
+
+	// Mirror the neighbours of the end points
+	n := len(points)
+	extended := make([]Vector2D[float64], 0, n+2)
+	extended = append(extended, points[0].Multiply(2).Subtract(points[1]))
+	extended = append(extended, points...)
+	extended = append(extended, points[n-1].Multiply(2).Subtract(points[n-2]))
+
+	spline := &Spline{Segments: make([]CubicBezier, n-1)}
+	for i := range spline.Segments {
+		p0, p1, p2, p3 := extended[i], extended[i+1], extended[i+2], extended[i+3]
+		spline.Segments[i] = CubicBezier{
+			P0: p1,
+			P1: catmullRomControl(p0, p1, p2),
+			P2: catmullRomControl(p3, p2, p1),
+			P3: p2,
+		}
+	}
+	return spline
+}
+
+// HexPathSpline creates a smooth spline through the centers of the hexes of a path
+func HexPathSpline(layout HexLayout, path []Hex[int64]) *Spline {
+	points := make([]Vector2D[float64], len(path))
+	for i, h := range path {
+		points[i] = layout.HexToVector2D(h.ToFloat())
+	}
+	return NewCatmullRom(points)
+}
+
+// Locate returns the segment index at the spline parameter t and the parameter within the segment
+func (s *Spline) Locate(t float64) (int, float64) {
+	n := len(s.Segments)
+	if n == 0 {
+		return -1, 0
+	}
+
+	scaled := math.Max(0, math.Min(1, t)) * float64(n)
+	index := min(int(scaled), n-1)
+	return index, scaled - float64(index)
+}
+
+// Point returns the position at t
+func (s *Spline) Point(t float64) Vector2D[float64] {
+	index, local := s.Locate(t)
+	if index < 0 {
+		return Vector2D[float64]{}
+	}
+	return s.Segments[index].Point(local)
+}
+
+// Derivative returns the first derivative at t
+func (s *Spline) Derivative(t float64) Vector2D[float64] {
+	index, local := s.Locate(t)
+	if index < 0 {
+		return Vector2D[float64]{}
+	}
+	return s.Segments[index].Derivative(local).Multiply(float64(len(s.Segments)))
+}
+
+// Tangent returns the normalized direction at t
+func (s *Spline) Tangent(t float64) Vector2D[float64] {
+	return curveTangent(s, t)
+}
+
+// Length returns the arc length of the spline
+func (s *Spline) Length() float64 {
+	length := 0.0
+	for _, segment := range s.Segments {
+		length += segment.Length()
+	}
+	return length
+}
+
+// Bounds returns the tight bounding rectangle of the spline
+func (s *Spline) Bounds() Rect {
+	var bounds Rect
+	for i, segment := range s.Segments {
+		if i == 0 {
+			bounds = segment.Bounds()
+		} else {
+			bounds = bounds.Union(segment.Bounds())
+		}
+	}
+	return bounds
+}
+
+// ClosestPoint returns the spline parameter and position of the point on the spline closest to p
+func (s *Spline) ClosestPoint(p Vector2D[float64]) (float64, Vector2D[float64]) {
+	bestT, best := 0.0, Vector2D[float64]{}
+	bestDistance := math.Inf(1)
+	for i, segment := range s.Segments {
+		t, point := segment.ClosestPoint(p)
+		if distance := point.Distance(p); distance < bestDistance {
+			bestT, best, bestDistance = (float64(i)+t)/float64(len(s.Segments)), point, distance
+		}
+	}
+	return bestT, best
+}
+
+// ArcLength maps distances along a curve to curve parameters, so the curve can
+// be traversed at constant speed
+type ArcLength struct {
+	curve      Curve
+	parameters []float64
+	lengths    []float64
+}
+
+// NewArcLength creates a lookup table for the curve with the number of intervals.
+// More intervals make Parameter faster for curves with strongly varying speed.
+func NewArcLength(curve Curve, intervals int) *ArcLength {
+	intervals = max(intervals, 1)
+
+	// Spline speeds have kinks at the joints, so intervals must not cross them
+	if spline, ok := curve.(*Spline); ok && len(spline.Segments) > 0 {
+		n := len(spline.Segments)
+		intervals = (intervals + n - 1) / n * n
+	}
+
+	table := &ArcLength{
+		curve:      curve,
+		parameters: make([]float64, intervals+1),
+		lengths:    make([]float64, intervals+1),
+	}
+	for i := 1; i <= intervals; i++ {
+		t0, t1 := float64(i-1)/float64(intervals), float64(i)/float64(intervals)
+		table.parameters[i] = t1
+		table.lengths[i] = table.lengths[i-1] + integrateSpeed(curve, t0, t1)
+	}
+	return table
+}
+
+// Length returns the total length of the curve
+func (table *ArcLength) Length() float64 {
+	return table.lengths[len(table.lengths)-1]
+}
+
+// Parameter returns the curve parameter at the distance from the start of the curve
+func (table *ArcLength) Parameter(distance float64) float64 {
+	if distance <= 0 {
+		return 0
+	}
+	if distance >= table.Length() {
+		return 1
+	}
+
+	i := sort.SearchFloat64s(table.lengths, distance)
+	t0, t1 := table.parameters[i-1], table.parameters[i]
+	l0, l1 := table.lengths[i-1], table.lengths[i]
+	if l1 == l0 {
+		return t0
+	}
+
+	// Refine the linear estimate with Newton steps on the length within the interval
+	t := t0 + (t1-t0)*(distance-l0)/(l1-l0)
+	for step := 0; step < 3; step++ {
+		speed := table.curve.Derivative(t).Length()
+		if speed == 0 {
+			break
+		}
+		t -= (l0 + integrateSpeed(table.curve, t0, t) - distance) / speed
+		t = math.Max(t0, math.Min(t1, t))
+	}
+	return t
+}
+
+// Point returns the position at the distance from the start of the curve
+func (table *ArcLength) Point(distance float64) Vector2D[float64] {
+	return table.curve.Point(table.Parameter(distance))
+}
+
+// catmullRomControl returns the Bezier control point next to p1 of the
+// centripetal Catmull-Rom segment from p1 to p2 with the outer neighbour p0
+func catmullRomControl(p0, p1, p2 Vector2D[float64]) Vector2D[float64] {
+	d1 := math.Pow(p1.Distance(p0), catmullRomAlpha)
+	d2 := math.Pow(p2.Distance(p1), catmullRomAlpha)
+	if d1 == 0 || d2 == 0 {
+		return p1
+	}
+
+	numerator := p2.Multiply(d1 * d1).
+		Subtract(p0.Multiply(d2 * d2)).
+		Add(p1.Multiply(2*d1*d1 + 3*d1*d2 + d2*d2))
+	return numerator.Divide(3 * d1 * (d1 + d2))
+}
+
+// curveTangent returns the normalized derivative, looking a bit further where the curve stands still
+func curveTangent(c Curve, t float64) Vector2D[float64] {
+	derivative := c.Derivative(t)
+	if derivative.Length() == 0 {
+		// Cusps at the ends of degenerated curves
+		derivative = c.Point(math.Min(1, t+1e-6)).Subtract(c.Point(math.Max(0, t-1e-6)))
+	}
+	return derivative.Normalize()
+}
+
+// curveLength returns the arc length between the parameters a and b
+func curveLength(c Curve, a, b float64) float64 {
+	length := 0.0
+	for i := 0; i < curveLengthSteps; i++ {
+		t0 := a + (b-a)*float64(i)/curveLengthSteps
+		t1 := a + (b-a)*float64(i+1)/curveLengthSteps
+		length += integrateSpeed(c, t0, t1)
+	}
+	return length
+}
+
+// integrateSpeed integrates the speed of the curve between a and b with Gauss-Legendre quadrature
+func integrateSpeed(c Curve, a, b float64) float64 {
+	half, mid := (b-a)/2, (a+b)/2
+	sum := 0.0
+	for _, node := range gaussLegendre5 {
+		sum += node[1] * c.Derivative(mid+half*node[0]).Length()
+	}
+	return sum * half
+}
+
+// closestOnCurve samples the curve between lo and hi and refines the closest sample by ternary search
+func closestOnCurve(c Curve, p Vector2D[float64], lo, hi float64, samples int) (float64, Vector2D[float64]) {
+	step := (hi - lo) / float64(samples)
+	best, bestDistance := lo, math.Inf(1)
+	for i := 0; i <= samples; i++ {
+		t := lo + step*float64(i)
+		if distance := c.Point(t).Distance(p); distance < bestDistance {
+			best, bestDistance = t, distance
+		}
+	}
+
+	a, b := math.Max(lo, best-step), math.Min(hi, best+step)
+	for i := 0; i < 60; i++ {
+		m1, m2 := a+(b-a)/3, b-(b-a)/3
+		if c.Point(m1).Distance(p) < c.Point(m2).Distance(p) {
+			b = m2
+		} else {
+			a = m1
+		}
+	}
+
+	t := (a + b) / 2
+	return t, c.Point(t)
+}
+
+// quadraticRoots returns the real roots of a*x^2 + b*x + c
+func quadraticRoots(a, b, c float64) []float64 {
+	if math.Abs(a) < 1e-12 {
+		if b == 0 {
+			return nil
+		}
+		return []float64{-c / b}
+	}
+
+	discriminant := b*b - 4*a*c
+	if discriminant < 0 {
+		return nil
+	}
+	root := math.Sqrt(discriminant)
+	return []float64{(-b + root) / (2 * a), (-b - root) / (2 * a)}
+}
@@ -0,0 +1,166 @@
+package maths
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testCurve is the common API of Bezier curves and splines
+type testCurve interface {
+	Curve
+	Tangent(t float64) Vector2D[float64]
+	Length() float64
+	Bounds() Rect
+	ClosestPoint(p Vector2D[float64]) (float64, Vector2D[float64])
+}
+
+// testCurves returns curves with different shapes for generic checks
+func testCurves() map[string]testCurve {
+	return map[string]testCurve{
+		"Quadratic": QuadraticBezier{P0: Vector2D[float64]{X: 0, Y: 0}, P1: Vector2D[float64]{X: 5, Y: 10}, P2: Vector2D[float64]{X: 10, Y: 0}},
+		"Cubic": CubicBezier{
+			P0: Vector2D[float64]{X: 0, Y: 0}, P1: Vector2D[float64]{X: -5, Y: 10},
+			P2: Vector2D[float64]{X: 15, Y: 10}, P3: Vector2D[float64]{X: 10, Y: -3},
+		},
+		"Catmull-Rom": NewCatmullRom([]Vector2D[float64]{{X: 0, Y: 0}, {X: 4, Y: 1}, {X: 5, Y: 6}, {X: 20, Y: 6}, {X: 21, Y: 0}}),
+	}
+}
+
+func TestCurves(t *testing.T) {
+	t.Parallel()
+
+	for name, curve := range testCurves() {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Derivative matches the finite difference away from spline joints
+			for i := 0; i < 20; i++ {
+				x := (float64(i) + 0.5) / 20
+				numeric := curve.Point(x + 1e-6).Subtract(curve.Point(x - 1e-6)).Divide(2e-6)
+				assert.InDelta(t, numeric.X, curve.Derivative(x).X, 1e-3)
+				assert.InDelta(t, numeric.Y, curve.Derivative(x).Y, 1e-3)
+				assert.InDelta(t, 1, curve.Tangent(x).Length(), 1e-9)
+			}
+
+			// Length matches a fine polyline
+			polyline, bounds := 0.0, NewRect(curve.Point(0), curve.Point(0))
+			for i := 1; i <= 10000; i++ {
+				p := curve.Point(float64(i) / 10000)
+				polyline += p.Distance(curve.Point(float64(i-1) / 10000))
+				bounds = bounds.Union(NewRect(p, p))
+			}
+			assert.InDelta(t, polyline, curve.Length(), 1e-4)
+
+			// Bounds are tight
+			exact := curve.Bounds()
+			assert.InDelta(t, bounds.Min.X, exact.Min.X, 1e-6)
+			assert.InDelta(t, bounds.Min.Y, exact.Min.Y, 1e-6)
+			assert.InDelta(t, bounds.Max.X, exact.Max.X, 1e-6)
+			assert.InDelta(t, bounds.Max.Y, exact.Max.Y, 1e-6)
+
+			// Closest point matches dense sampling
+			for _, p := range testPoints(20, exact.Expand(5)) {
+				best := math.Inf(1)
+				for i := 0; i <= 2000; i++ {
+					best = math.Min(best, curve.Point(float64(i)/2000).Distance(p))
+				}
+				param, closest := curve.ClosestPoint(p)
+				assert.InDelta(t, 0, curve.Point(param).Distance(closest), 1e-9)
+				assert.InDelta(t, best, closest.Distance(p), 1e-3)
+			}
+		})
+	}
+}
+
+func TestBezierSplit(t *testing.T) {
+	t.Parallel()
+
+	quadratic := testCurves()["Quadratic"].(QuadraticBezier)
+	cubic := testCurves()["Cubic"].(CubicBezier)
+
+	q1, q2 := quadratic.Split(0.3)
+	c1, c2 := cubic.Split(0.3)
+	for i := 0; i <= 10; i++ {
+		x := float64(i) / 10
+		assert.InDelta(t, 0, q1.Point(x).Distance(quadratic.Point(0.3*x)), 1e-9)
+		assert.InDelta(t, 0, q2.Point(x).Distance(quadratic.Point(0.3+0.7*x)), 1e-9)
+		assert.InDelta(t, 0, c1.Point(x).Distance(cubic.Point(0.3*x)), 1e-9)
+		assert.InDelta(t, 0, c2.Point(x).Distance(cubic.Point(0.3+0.7*x)), 1e-9)
+	}
+	assert.InDelta(t, cubic.Length(), c1.Length()+c2.Length(), 1e-6)
+}
+
+func TestCatmullRom(t *testing.T) {
+	t.Parallel()
+
+	points := []Vector2D[float64]{{X: 0, Y: 0}, {X: 4, Y: 1}, {X: 4.1, Y: 1.2}, {X: 20, Y: 6}, {X: 21, Y: 0}}
+	spline := NewCatmullRom(points)
+	assert.Len(t, spline.Segments, len(points)-1)
+
+	for i, p := range points {
+		assert.InDelta(t, 0, spline.Point(float64(i)/float64(len(points)-1)).Distance(p), 1e-9)
+	}
+
+	// Tangents are continuous at the joints
+	for i := 1; i < len(spline.Segments); i++ {
+		a := spline.Segments[i-1].Tangent(1)
+		b := spline.Segments[i].Tangent(0)
+		assert.InDelta(t, 0, a.Distance(b), 1e-9)
+	}
+
+	// Points on a line give a straight spline
+	line := NewCatmullRom([]Vector2D[float64]{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 3, Y: 3}})
+	assert.InDelta(t, math.Sqrt(18), line.Length(), 1e-9)
+
+	index, local := spline.Locate(0.6)
+	assert.Equal(t, 2, index)
+	assert.InDelta(t, 0.4, local, 1e-9)
+
+	assert.Equal(t, Vector2D[float64]{X: 3, Y: 4}, NewCatmullRom([]Vector2D[float64]{{X: 3, Y: 4}}).Point(0.5))
+	assert.Equal(t, Vector2D[float64]{}, NewCatmullRom(nil).Point(0.5))
+}
+
+func TestArcLength(t *testing.T) {
+	t.Parallel()
+
+	for name, curve := range testCurves() {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			table := NewArcLength(curve, 10)
+			assert.InDelta(t, curve.Length(), table.Length(), 1e-6)
+			assert.Equal(t, curve.Point(0), table.Point(-1))
+			assert.Equal(t, curve.Point(1), table.Point(table.Length()+1))
+
+			for i := 1; i < 20; i++ {
+				distance := table.Length() * float64(i) / 20
+				assert.InDelta(t, distance, polylineLength(curve, table.Parameter(distance)), 1e-4)
+			}
+		})
+	}
+}
+
+func TestHexPathSpline(t *testing.T) {
+	t.Parallel()
+
+	layout := NewHexLayout(LayoutPointy, Vector2D[float64]{X: 10, Y: 10}, Vector2D[float64]{X: 5, Y: 5}, 1)
+	path := Hex[int64]{}.LineTo(Hex[int64]{Q: 3, R: -1})
+	path = append(path, Hex[int64]{Q: 3, R: 0}, Hex[int64]{Q: 3, R: 1})
+	spline := HexPathSpline(layout, path)
+
+	for i, h := range path {
+		center := layout.HexToVector2D(h.ToFloat())
+		assert.InDelta(t, 0, spline.Point(float64(i)/float64(len(path)-1)).Distance(center), 1e-9)
+	}
+}
+
+// polylineLength measures the curve from 0 to end with a fine polyline
+func polylineLength(curve Curve, end float64) float64 {
+	length := 0.0
+	for i := 1; i <= 20000; i++ {
+		length += curve.Point(end * float64(i) / 20000).Distance(curve.Point(end * float64(i-1) / 20000))
+	}
+	return length
+}
@@ -0,0 +1,635 @@
+package maths
+
+import (
+	"cmp"
+	"errors"
+	"math"
+	"slices"
+)
+
+var (
+	// ErrInvalidConstraint is returned for constrained edges which cannot be inserted
+	ErrInvalidConstraint = errors.New("maths: invalid constrained edge")
+)
+
+const (
+	// delaunaySuperScale is the size of the super triangle relative to the input extent
+	delaunaySuperScale = 1e4
+	// delaunayInCircleTolerance is the rounding error allowed in the in-circle test relative to its operands
+	delaunayInCircleTolerance = 1e-12
+)
+
+// delaunayTriangle is a counter-clockwise triangle of the mesh. Neighbour i is
+// the triangle across the edge opposite of vertex i.
+type delaunayTriangle struct {
+	v     [3]int
+	n     [3]int
+	alive bool
+}
+
+// delaunayMesh is a triangle mesh with adjacency used to build Delaunay triangulations
+type delaunayMesh struct {
+	points      []Vector2D[float64]
+	triangles   []delaunayTriangle
+	constrained map[[2]int]bool
+	last        int
+}
+
+// Delaunay triangulates the points so no point lies inside the circumcircle of
+// any triangle. Constrained edges given as pairs of point indices are forced
+// into the triangulation, which then is only Delaunay away from them. The
+// result holds three indices per counter-clockwise triangle. Duplicated points
+// are ignored and collinear inputs produce no triangles.
+func Delaunay(points []Vector2D[float64], constraints ...[2]int) ([]int, error) {
+	for _, edge := range constraints {
+		if edge[0] < 0 || edge[1] < 0 || edge[0] >= len(points) || edge[1] >= len(points) {
+			return nil, ErrInvalidConstraint
+		}
+	}
+	if len(points) < 3 {
+		return nil, nil
+	}
+
+	constraints = slices.Clone(constraints)
+	mesh := newDelaunayMesh(points)
+	inserted := make(map[Vector2D[float64]]int, len(points))
+	for i, p := range points {
+		if first, ok := inserted[p]; ok {
+			// Constraints on duplicated points use the first occurrence
+			for j := range constraints {
+				for k := range constraints[j] {
+					if constraints[j][k] == i {
+						constraints[j][k] = first
+					}
+				}
+			}
+			continue
+		}
+		inserted[p] = i
+		mesh.insertPoint(i)
+	}
+	mesh.removeSuperTriangle()
+
+	for _, edge := range constraints {
+		if err := mesh.insertConstraint(edge[0], edge[1]); err != nil {
+			return nil, err
+		}
+	}
+
+	return mesh.result(), nil
+}
+
+// newDelaunayMesh creates a mesh with a super triangle containing all points
+func newDelaunayMesh(points []Vector2D[float64]) *delaunayMesh {
+	bounds := NewRect(points[0], points[0])
+	for _, p := range points {
+		bounds = bounds.Union(NewRect(p, p))
+	}
+	center := bounds.Center()
+	size := math.Max(math.Max(bounds.Width(), bounds.Height()), 1) * delaunaySuperScale
+
+	n := len(points)
+	mesh := &delaunayMesh{
+		points:      append(append([]Vector2D[float64]{}, points...), center.Add(NewVector2D(-size, -size)), center.Add(NewVector2D(size, -size)), center.Add(NewVector2D(0, size))),
+		constrained: make(map[[2]int]bool),
+	}
+	mesh.triangles = append(mesh.triangles, delaunayTriangle{
+		v:     [3]int{n, n + 1, n + 2},
+		n:     [3]int{-1, -1, -1},
+		alive: true,
+	})
+	return mesh
+}
+
+// result returns the indices of all triangles
+func (mesh *delaunayMesh) result() []int {
+	var indices []int
+	for _, t := range mesh.triangles {
+		if t.alive {
+			indices = append(indices, t.v[0], t.v[1], t.v[2])
+		}
+	}
+	return indices
+}
+
+// removeSuperTriangle drops all triangles touching the super triangle. A finite
+// super triangle can leave thin gaps along the convex hull, which are filled
+// and flipped until the triangulation is Delaunay again.
+func (mesh *delaunayMesh) removeSuperTriangle() {
+	n := len(mesh.points) - 3
+	for t := range mesh.triangles {
+		tri := &mesh.triangles[t]
+		if tri.v[0] >= n || tri.v[1] >= n || tri.v[2] >= n {
+			tri.alive = false
+		}
+	}
+	mesh.points = mesh.points[:n]
+
+	loop := mesh.boundaryLoop()
+	if len(loop) < 3 {
+		return
+	}
+
+	positions := make([]Vector2D[float64], len(loop))
+	for i, v := range loop {
+		positions[i] = mesh.points[v]
+	}
+	hull := make(map[Vector2D[float64]]bool)
+	for _, p := range ConvexHull(positions) {
+		hull[p] = true
+	}
+
+	// Rotate the loop to start at a hull vertex
+	start := slices.IndexFunc(loop, func(v int) bool { return hull[mesh.points[v]] })
+	loop = append(loop[start:], loop[:start+1]...)
+
+	// Every part of the loop between two hull vertices encloses a gap with the hull edge
+	first := 0
+	for i := 1; i < len(loop); i++ {
+		if !hull[mesh.points[loop[i]]] {
+			continue
+		}
+		if i-first > 1 {
+			mesh.fillGap(loop[first : i+1])
+		}
+		first = i
+	}
+
+	mesh.rebuildAdjacency()
+	mesh.legalizeAll()
+}
+
+// boundaryLoop returns the vertices along the outer boundary of the mesh with the inside to the left
+func (mesh *delaunayMesh) boundaryLoop() []int {
+	next := make(map[int][]int)
+	start := -1
+	for _, tri := range mesh.triangles {
+		if !tri.alive {
+			continue
+		}
+		for i := 0; i < 3; i++ {
+			if tri.n[i] >= 0 && mesh.triangles[tri.n[i]].alive {
+				continue
+			}
+			q, r := tri.v[(i+1)%3], tri.v[(i+2)%3]
+			next[q] = append(next[q], r)
+			if start < 0 || compareVector(mesh.points[q], mesh.points[start]) < 0 {
+				start = q
+			}
+		}
+	}
+	if start < 0 {
+		return nil
+	}
+
+	loop := []int{start}
+	prev, current := -1, start
+	for len(loop) <= len(next) {
+		candidates := next[current]
+		following := candidates[0]
+
+		// Where the boundary touches itself follow the edge turning most to the right
+		if len(candidates) > 1 && prev >= 0 {
+			incoming := mesh.points[current].Subtract(mesh.points[prev])
+			angle := func(v int) float64 {
+				outgoing := mesh.points[v].Subtract(mesh.points[current])
+				return math.Atan2(incoming.Cross(outgoing), incoming.Dot(outgoing))
+			}
+			following = slices.MinFunc(candidates, func(a, b int) int {
+				return cmp.Compare(angle(a), angle(b))
+			})
+		}
+
+		if following == start {
+			break
+		}
+		loop = append(loop, following)
+		prev, current = current, following
+	}
+	return loop
+}
+
+// fillGap triangulates the polygon between a boundary path and the hull edge joining its ends
+func (mesh *delaunayMesh) fillGap(path []int) {
+	polygon := make([]Vector2D[float64], len(path))
+	for i, v := range path {
+		polygon[i] = mesh.points[v]
+	}
+
+	indices, err := EarClip(polygon)
+	if err != nil {
+		return
+	}
+	for i := 0; i < len(indices); i += 3 {
+		mesh.addTriangle([3]int{path[indices[i]], path[indices[i+1]], path[indices[i+2]]}, [3]int{-1, -1, -1})
+	}
+}
+
+// rebuildAdjacency recomputes the neighbours of all alive triangles
+func (mesh *delaunayMesh) rebuildAdjacency() {
+	edges := make(map[[2]int]int)
+	for t, tri := range mesh.triangles {
+		if !tri.alive {
+			continue
+		}
+		for i := 0; i < 3; i++ {
+			edges[[2]int{tri.v[(i+1)%3], tri.v[(i+2)%3]}] = t
+		}
+	}
+
+	for t := range mesh.triangles {
+		tri := &mesh.triangles[t]
+		if !tri.alive {
+			continue
+		}
+		for i := 0; i < 3; i++ {
+			tri.n[i] = -1
+			if u, ok := edges[[2]int{tri.v[(i+2)%3], tri.v[(i+1)%3]}]; ok {
+				tri.n[i] = u
+			}
+		}
+	}
+}
+
+// legalizeAll flips edges anywhere in the mesh until it is Delaunay
+func (mesh *delaunayMesh) legalizeAll() {
+	type pending struct{ t, i int }
+	var stack []pending
+	for t, tri := range mesh.triangles {
+		if tri.alive {
+			stack = append(stack, pending{t, 0}, pending{t, 1}, pending{t, 2})
+		}
+	}
+
+	// Flips always terminate with the tolerant in-circle test, the limit only guards against cycles
+	flips := len(mesh.triangles) * len(mesh.triangles)
+	for len(stack) > 0 && flips > 0 {
+		item := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		tri := mesh.triangles[item.t]
+		u := tri.n[item.i]
+		if u < 0 || mesh.isConstrained(tri.v[(item.i+1)%3], tri.v[(item.i+2)%3]) {
+			continue
+		}
+		if !mesh.inCircle(tri.v, mesh.points[mesh.oppositeVertex(u, item.t)]) {
+			continue
+		}
+
+		mesh.flip(item.t, item.i)
+		flips--
+		for i := 0; i < 3; i++ {
+			stack = append(stack, pending{item.t, i}, pending{u, i})
+		}
+	}
+}
+
+// insertPoint adds a point and restores the Delaunay property with edge flips
+func (mesh *delaunayMesh) insertPoint(p int) {
+	t, edge := mesh.locate(mesh.points[p])
+	if edge < 0 {
+		mesh.splitTriangle(t, p)
+	} else {
+		mesh.splitEdge(t, edge, p)
+	}
+}
+
+// locate returns the triangle containing the point and the edge it lies on, or -1
+func (mesh *delaunayMesh) locate(p Vector2D[float64]) (int, int) {
+	t := mesh.last
+	for steps := 0; steps < len(mesh.triangles); steps++ {
+		found, edge, next := mesh.walk(t, p)
+		if found {
+			return t, edge
+		}
+		t = next
+	}
+
+	// The walk may cycle on degenerated meshes, fall back to a full search
+	for t := range mesh.triangles {
+		if !mesh.triangles[t].alive {
+			continue
+		}
+		if found, edge, _ := mesh.walk(t, p); found {
+			return t, edge
+		}
+	}
+	return mesh.last, -1
+}
+
+// walk reports whether the triangle contains the point, otherwise it returns the next triangle towards it
+func (mesh *delaunayMesh) walk(t int, p Vector2D[float64]) (bool, int, int) {
+	tri := &mesh.triangles[t]
+	edge := -1
+	for i := 0; i < 3; i++ {
+		a := mesh.points[tri.v[(i+1)%3]]
+		b := mesh.points[tri.v[(i+2)%3]]
+		o := orientation(a, b, p)
+		if o < 0 && tri.n[i] >= 0 {
+			return false, -1, tri.n[i]
+		}
+		if o == 0 {
+			edge = i
+		}
+	}
+	return true, edge, t
+}
+
+// addTriangle appends a new triangle and returns its index
+func (mesh *delaunayMesh) addTriangle(v, n [3]int) int {
+	mesh.triangles = append(mesh.triangles, delaunayTriangle{v: v, n: n, alive: true})
+	return len(mesh.triangles) - 1
+}
+
+// replaceNeighbour changes the neighbour reference of t from old to new
+func (mesh *delaunayMesh) replaceNeighbour(t, old, new int) {
+	if t < 0 {
+		return
+	}
+	for i, n := range mesh.triangles[t].n {
+		if n == old {
+			mesh.triangles[t].n[i] = new
+			return
+		}
+	}
+}
+
+// splitTriangle splits the triangle into three triangles meeting at p
+func (mesh *delaunayMesh) splitTriangle(t, p int) {
+	old := mesh.triangles[t]
+	a, b, c := old.v[0], old.v[1], old.v[2]
+
+	t1 := mesh.addTriangle([3]int{b, c, p}, [3]int{-1, t, old.n[0]})
+	t2 := mesh.addTriangle([3]int{c, a, p}, [3]int{t, t1, old.n[1]})
+	mesh.triangles[t1].n[0] = t2
+	mesh.triangles[t] = delaunayTriangle{v: [3]int{a, b, p}, n: [3]int{t1, t2, old.n[2]}, alive: true}
+
+	mesh.replaceNeighbour(old.n[0], t, t1)
+	mesh.replaceNeighbour(old.n[1], t, t2)
+	mesh.last = t
+
+	mesh.legalize(t, 2)
+	mesh.legalize(t1, 2)
+	mesh.legalize(t2, 2)
+}
+
+// splitEdge splits the edge opposite of vertex i of triangle t at p
+func (mesh *delaunayMesh) splitEdge(t, i, p int) {
+	old := mesh.triangles[t]
+	a, b, c := old.v[i], old.v[(i+1)%3], old.v[(i+2)%3]
+	nOppB, nOppC := old.n[(i+1)%3], old.n[(i+2)%3]
+	u := old.n[i]
+
+	// t becomes (a, b, p) and t2 is (a, p, c)
+	t2 := mesh.addTriangle([3]int{a, p, c}, [3]int{-1, nOppB, t})
+	mesh.triangles[t] = delaunayTriangle{v: [3]int{a, b, p}, n: [3]int{-1, t2, nOppC}, alive: true}
+	mesh.replaceNeighbour(nOppB, t, t2)
+	mesh.last = t
+
+	if u >= 0 {
+		oldU := mesh.triangles[u]
+		j := 0
+		for oldU.n[j] != t {
+			j++
+		}
+		d := oldU.v[j]
+		nOppUC, nOppUB := oldU.n[(j+1)%3], oldU.n[(j+2)%3]
+
+		// u is (d, c, b) and becomes (d, c, p), u2 is (d, p, b)
+		u2 := mesh.addTriangle([3]int{d, p, b}, [3]int{t, nOppUC, u})
+		mesh.triangles[u] = delaunayTriangle{v: [3]int{d, c, p}, n: [3]int{t2, u2, nOppUB}, alive: true}
+		mesh.replaceNeighbour(nOppUC, u, u2)
+		mesh.triangles[t].n[0] = u2
+		mesh.triangles[t2].n[0] = u
+
+		mesh.legalize(u, 2)
+		mesh.legalize(u2, 1)
+	}
+
+	mesh.legalize(t, 2)
+	mesh.legalize(t2, 1)
+}
+
+// legalize flips the edge opposite of vertex i of triangle t while it violates the Delaunay property
+func (mesh *delaunayMesh) legalize(t, i int) {
+	type pending struct{ t, i int }
+	stack := []pending{{t, i}}
+
+	for flips := len(mesh.triangles); len(stack) > 0 && flips > 0; {
+		item := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		tri := mesh.triangles[item.t]
+		u := tri.n[item.i]
+		if u < 0 || mesh.isConstrained(tri.v[(item.i+1)%3], tri.v[(item.i+2)%3]) {
+			continue
+		}
+
+		opposite := mesh.oppositeVertex(u, item.t)
+		if !mesh.inCircle(tri.v, mesh.points[opposite]) {
+			continue
+		}
+
+		mesh.flip(item.t, item.i)
+		flips--
+		stack = append(stack, pending{item.t, 0}, pending{u, 0})
+	}
+}
+
+// flip replaces the edge opposite of vertex i of triangle t by the other diagonal.
+// Afterwards t is (p, q, s) and its neighbour is (p, s, r) where p was vertex i
+// of t, q and r the end points of the edge and s the opposite vertex.
+func (mesh *delaunayMesh) flip(t, i int) {
+	old := mesh.triangles[t]
+	u := old.n[i]
+	oldU := mesh.triangles[u]
+	j := 0
+	for oldU.n[j] != t {
+		j++
+	}
+
+	p, q, r := old.v[i], old.v[(i+1)%3], old.v[(i+2)%3]
+	s := oldU.v[j]
+	tOppR, tOppQ := old.n[(i+2)%3], old.n[(i+1)%3]
+	uOppR, uOppQ := oldU.n[(j+1)%3], oldU.n[(j+2)%3]
+
+	mesh.triangles[t] = delaunayTriangle{v: [3]int{p, q, s}, n: [3]int{uOppR, u, tOppR}, alive: true}
+	mesh.triangles[u] = delaunayTriangle{v: [3]int{p, s, r}, n: [3]int{uOppQ, tOppQ, t}, alive: true}
+	mesh.replaceNeighbour(uOppR, u, t)
+	mesh.replaceNeighbour(tOppQ, t, u)
+}
+
+// oppositeVertex returns the vertex of triangle u which is not shared with its neighbour t
+func (mesh *delaunayMesh) oppositeVertex(u, t int) int {
+	for j, n := range mesh.triangles[u].n {
+		if n == t {
+			return mesh.triangles[u].v[j]
+		}
+	}
+	return -1
+}
+
+// inCircle reports whether p lies strictly inside the circumcircle of the counter-clockwise
+// triangle. Points within the rounding error of the circle count as outside, so only one
+// diagonal of four points on a circle is flipped to.
+func (mesh *delaunayMesh) inCircle(v [3]int, p Vector2D[float64]) bool {
+	a := mesh.points[v[0]].Subtract(p)
+	b := mesh.points[v[1]].Subtract(p)
+	c := mesh.points[v[2]].Subtract(p)
+	la, lb, lc := a.X*a.X+a.Y*a.Y, b.X*b.X+b.Y*b.Y, c.X*c.X+c.Y*c.Y
+
+	det := la*b.Cross(c) - lb*a.Cross(c) + lc*a.Cross(b)
+	permanent := la*(math.Abs(b.X*c.Y)+math.Abs(b.Y*c.X)) +
+		lb*(math.Abs(a.X*c.Y)+math.Abs(a.Y*c.X)) +
+		lc*(math.Abs(a.X*b.Y)+math.Abs(a.Y*b.X))
+	return det > permanent*delaunayInCircleTolerance
+}
+
+// isConstrained reports whether the edge between a and b is a constrained edge
+func (mesh *delaunayMesh) isConstrained(a, b int) bool {
+	return mesh.constrained[[2]int{min(a, b), max(a, b)}]
+}
+
+// findEdge returns the triangle and vertex index opposite of the edge from a to b
+func (mesh *delaunayMesh) findEdge(a, b int) (int, int, bool) {
+	for t, tri := range mesh.triangles {
+		if !tri.alive {
+			continue
+		}
+		for i := 0; i < 3; i++ {
+			if tri.v[(i+1)%3] == a && tri.v[(i+2)%3] == b {
+				return t, i, true
+			}
+		}
+	}
+	return -1, -1, false
+}
+
+// insertConstraint forces the edge between a and b into the triangulation
+func (mesh *delaunayMesh) insertConstraint(a, b int) error {
+	if a == b {
+		return nil
+	}
+
+	// Split the constraint at points lying on it
+	pa, pb := mesh.points[a], mesh.points[b]
+	for c, pc := range mesh.points {
+		if c == a || c == b || pc == pa || pc == pb || orientation(pa, pb, pc) != 0 {
+			continue
+		}
+		if pc.Subtract(pa).Dot(pb.Subtract(pa)) > 0 && pc.Subtract(pb).Dot(pa.Subtract(pb)) > 0 {
+			if mesh.hasVertex(c) {
+				if err := mesh.insertConstraint(a, c); err != nil {
+					return err
+				}
+				return mesh.insertConstraint(c, b)
+			}
+		}
+	}
+
+	key := [2]int{min(a, b), max(a, b)}
+	if _, _, ok := mesh.findEdge(a, b); ok {
+		mesh.constrained[key] = true
+		return nil
+	}
+
+	// Flip all edges crossing the constraint
+	crossing := mesh.crossingEdges(a, b)
+	var created [][2]int
+	for attempts := 0; len(crossing) > 0; attempts++ {
+		if attempts > 100*len(mesh.triangles) {
+			return ErrInvalidConstraint
+		}
+
+		edge := crossing[0]
+		crossing = crossing[1:]
+		if mesh.isConstrained(edge[0], edge[1]) {
+			return ErrInvalidConstraint
+		}
+
+		t, i, ok := mesh.findEdge(edge[0], edge[1])
+		if !ok || mesh.triangles[t].n[i] < 0 {
+			return ErrInvalidConstraint
+		}
+		p := mesh.triangles[t].v[i]
+		s := mesh.oppositeVertex(mesh.triangles[t].n[i], t)
+
+		// Only strictly convex quads can be flipped, try again later
+		pp, pq, pr, ps := mesh.points[p], mesh.points[edge[0]], mesh.points[edge[1]], mesh.points[s]
+		if orientation(pp, pq, ps) <= 0 || orientation(pp, ps, pr) <= 0 {
+			crossing = append(crossing, edge)
+			continue
+		}
+
+		mesh.flip(t, i)
+		if segmentsCross(mesh.points[a], mesh.points[b], pp, ps) {
+			crossing = append(crossing, [2]int{s, p})
+		} else {
+			created = append(created, [2]int{p, s})
+		}
+	}
+	mesh.constrained[key] = true
+
+	// Restore the Delaunay property for the new edges
+	for changed := true; changed; {
+		changed = false
+		for k, edge := range created {
+			if edge[0] == key[0] && edge[1] == key[1] || edge[0] == key[1] && edge[1] == key[0] {
+				continue
+			}
+			t, i, ok := mesh.findEdge(edge[0], edge[1])
+			if !ok || mesh.triangles[t].n[i] < 0 {
+				continue
+			}
+			u := mesh.triangles[t].n[i]
+			s := mesh.oppositeVertex(u, t)
+			if mesh.inCircle(mesh.triangles[t].v, mesh.points[s]) {
+				p := mesh.triangles[t].v[i]
+				mesh.flip(t, i)
+				created[k] = [2]int{p, s}
+				changed = true
+			}
+		}
+	}
+	return nil
+}
+
+// hasVertex reports whether the point is part of the mesh
+func (mesh *delaunayMesh) hasVertex(v int) bool {
+	for _, tri := range mesh.triangles {
+		if tri.alive && (tri.v[0] == v || tri.v[1] == v || tri.v[2] == v) {
+			return true
+		}
+	}
+	return false
+}
+
+// crossingEdges returns all edges which properly cross the segment between a and b
+func (mesh *delaunayMesh) crossingEdges(a, b int) [][2]int {
+	var edges [][2]int
+	for t, tri := range mesh.triangles {
+		if !tri.alive {
+			continue
+		}
+		for i := 0; i < 3; i++ {
+			// Every inner edge is shared by two triangles, report it once
+			if tri.n[i] >= 0 && tri.n[i] < t {
+				continue
+			}
+			q, r := tri.v[(i+1)%3], tri.v[(i+2)%3]
+			if segmentsCross(mesh.points[a], mesh.points[b], mesh.points[q], mesh.points[r]) {
+				edges = append(edges, [2]int{q, r})
+			}
+		}
+	}
+	return edges
+}
+
+// segmentsCross reports whether the segments ab and cd cross in a single inner point
+func segmentsCross(a, b, c, d Vector2D[float64]) bool {
+	o1 := orientation(a, b, c)
+	o2 := orientation(a, b, d)
+	o3 := orientation(c, d, a)
+	o4 := orientation(c, d, b)
+	return (o1 > 0 && o2 < 0 || o1 < 0 && o2 > 0) && (o3 > 0 && o4 < 0 || o3 < 0 && o4 > 0)
+}
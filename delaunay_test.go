@@ -0,0 +1,160 @@
+package maths
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// assertDelaunay checks that no point lies inside the circumcircle of a triangle,
+// ignoring triangles next to constrained edges
+func assertDelaunay(t *testing.T, points []Vector2D[float64], indices []int) {
+	t.Helper()
+
+	mesh := &delaunayMesh{points: points}
+	for i := 0; i < len(indices); i += 3 {
+		triangle := [3]int{indices[i], indices[i+1], indices[i+2]}
+		for j, p := range points {
+			if j == triangle[0] || j == triangle[1] || j == triangle[2] {
+				continue
+			}
+			assert.False(t, mesh.inCircle(triangle, p), "point %v inside circumcircle of %v", p, triangle)
+		}
+	}
+}
+
+// hasEdge reports whether any triangle uses the edge between a and b
+func hasEdge(indices []int, a, b int) bool {
+	for i := 0; i < len(indices); i += 3 {
+		for k := 0; k < 3; k++ {
+			u, v := indices[i+k], indices[i+(k+1)%3]
+			if u == a && v == b || u == b && v == a {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestDelaunay(t *testing.T) {
+	t.Parallel()
+
+	points := testPoints(200, NewRect(NewVector2D[float64](0, 0), NewVector2D[float64](100, 100)))
+	indices, err := Delaunay(points)
+	assert.NoError(t, err)
+
+	hull := ConvexHull(points)
+	assert.Len(t, indices, 3*(2*len(points)-2-len(hull)))
+	assert.InDelta(t, Area(hull), trianglesArea(t, points, indices), 1e-6)
+	assertDelaunay(t, points, indices)
+}
+
+func TestDelaunayGrid(t *testing.T) {
+	t.Parallel()
+
+	// regular grids have many cocircular points and points on edges
+	var points []Vector2D[float64]
+	for y := 0; y < 6; y++ {
+		for x := 0; x < 6; x++ {
+			points = append(points, NewVector2D(float64(x), float64(y)))
+		}
+	}
+	points = append(points, NewVector2D[float64](2, 2))
+
+	indices, err := Delaunay(points)
+	assert.NoError(t, err)
+	assert.Len(t, indices, 3*50)
+	assert.InDelta(t, 25, trianglesArea(t, points, indices), 1e-9)
+}
+
+func TestDelaunayCocircular(t *testing.T) {
+	t.Parallel()
+
+	layout := NewHexLayout(LayoutPointy, NewVector2D[float64](10, 10), NewVector2D[float64](3, -7), 1)
+	polygons := map[string][]Vector2D[float64]{"Hex corners": layout.HexCorners(Hex[float64]{})}
+	for _, n := range []int{4, 6, 12, 16, 32, 100} {
+		var polygon []Vector2D[float64]
+		for i := range n {
+			angle := 2 * math.Pi * float64(i) / float64(n)
+			polygon = append(polygon, NewVector2D(50+20*math.Cos(angle), 50+20*math.Sin(angle)))
+		}
+		polygons[fmt.Sprintf("Regular %d-gon", n)] = polygon
+	}
+
+	for name, points := range polygons {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// All points lie on one circle, any fan of n-2 triangles is a valid result
+			indices, err := Delaunay(points)
+			assert.NoError(t, err)
+			assert.Len(t, indices, 3*(len(points)-2))
+			assert.InDelta(t, Area(points), trianglesArea(t, points, indices), 1e-6)
+			assertDelaunay(t, points, indices)
+		})
+	}
+
+	// Hex centers of a spiral are cocircular in many groups
+	var centers []Vector2D[float64]
+	for _, h := range (Hex[int64]{}).Spiral(4) {
+		centers = append(centers, layout.HexToVector2D(h.ToFloat()))
+	}
+	indices, err := Delaunay(centers)
+	assert.NoError(t, err)
+	assert.InDelta(t, Area(ConvexHull(centers)), trianglesArea(t, centers, indices), 1e-6)
+	assertDelaunay(t, centers, indices)
+}
+
+func TestDelaunayDegenerated(t *testing.T) {
+	t.Parallel()
+
+	indices, err := Delaunay([]Vector2D[float64]{{X: 0, Y: 0}, {X: 1, Y: 1}})
+	assert.NoError(t, err)
+	assert.Empty(t, indices)
+
+	indices, err = Delaunay([]Vector2D[float64]{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 2}})
+	assert.NoError(t, err)
+	assert.Empty(t, indices)
+
+	_, err = Delaunay([]Vector2D[float64]{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 0}}, [2]int{0, 3})
+	assert.ErrorIs(t, err, ErrInvalidConstraint)
+}
+
+func TestDelaunayConstrained(t *testing.T) {
+	t.Parallel()
+
+	// two rows of points where the Delaunay triangulation avoids the long diagonal
+	points := []Vector2D[float64]{
+		{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 3, Y: 0}, {X: 4, Y: 0}, {X: 5, Y: 0},
+		{X: 0, Y: 1}, {X: 1, Y: 1}, {X: 2, Y: 1}, {X: 3, Y: 1}, {X: 4, Y: 1}, {X: 5, Y: 1},
+		{X: 2.5, Y: -3}, {X: 2.5, Y: 4},
+	}
+	unconstrained, err := Delaunay(points)
+	assert.NoError(t, err)
+	assert.False(t, hasEdge(unconstrained, 0, 11))
+
+	indices, err := Delaunay(points, [2]int{0, 11}, [2]int{12, 13})
+	assert.Error(t, err, "crossing constraints cannot be inserted")
+	assert.Nil(t, indices)
+
+	indices, err = Delaunay(points, [2]int{0, 11})
+	assert.NoError(t, err)
+	assert.True(t, hasEdge(indices, 0, 11))
+	assert.Len(t, indices, len(unconstrained))
+	assert.InDelta(t, trianglesArea(t, points, unconstrained), trianglesArea(t, points, indices), 1e-9)
+
+	// constraints through other points are split
+	indices, err = Delaunay(points, [2]int{12, 13})
+	assert.NoError(t, err)
+	assert.InDelta(t, trianglesArea(t, points, unconstrained), trianglesArea(t, points, indices), 1e-9)
+
+	// a constraint on a larger random set
+	random := testPoints(100, NewRect(NewVector2D[float64](0, 0), NewVector2D[float64](50, 50)))
+	indices, err = Delaunay(random, [2]int{3, 77}, [2]int{10, 20})
+	assert.NoError(t, err)
+	assert.True(t, hasEdge(indices, 3, 77))
+	assert.True(t, hasEdge(indices, 10, 20))
+	assert.InDelta(t, Area(ConvexHull(random)), trianglesArea(t, random, indices), 1e-6)
+}
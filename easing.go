@@ -0,0 +1,177 @@
+package maths
+
+import (
+	"math"
+)
+
+const (
+	// easeBackOvershoot is the overshoot of the back easing
+	easeBackOvershoot = 1.70158
+	// easeElasticPeriod is the angular frequency of the elastic easing
+	easeElasticPeriod = 2 * math.Pi / 3
+)
+
+// EasingFunc maps the linear progress in [0, 1] to an eased progress.
+// Every easing returns 0 for 0 and 1 for 1, back and elastic easings leave
+// the range in between.
+type EasingFunc func(t float64) float64
+
+// EaseLinear returns the progress unchanged
+func EaseLinear(t float64) float64 {
+	return t
+}
+
+// EaseInQuad accelerates with a quadratic curve
+func EaseInQuad(t float64) float64 {
+	return t * t
+}
+
+// EaseOutQuad decelerates with a quadratic curve
+func EaseOutQuad(t float64) float64 {
+	return easeOut(EaseInQuad, t)
+}
+
+// EaseInOutQuad accelerates and decelerates with a quadratic curve
+func EaseInOutQuad(t float64) float64 {
+	return easeInOut(EaseInQuad, t)
+}
+
+// EaseInCubic accelerates with a cubic curve
+func EaseInCubic(t float64) float64 {
+	return t * t * t
+}
+
+// EaseOutCubic decelerates with a cubic curve
+func EaseOutCubic(t float64) float64 {
+	return easeOut(EaseInCubic, t)
+}
+
+// EaseInOutCubic accelerates and decelerates with a cubic curve
+func EaseInOutCubic(t float64) float64 {
+	return easeInOut(EaseInCubic, t)
+}
+
+// EaseInQuart accelerates with a quartic curve
+func EaseInQuart(t float64) float64 {
+	return t * t * t * t
+}
+
+// EaseOutQuart decelerates with a quartic curve
+func EaseOutQuart(t float64) float64 {
+	return easeOut(EaseInQuart, t)
+}
+
+// EaseInOutQuart accelerates and decelerates with a quartic curve
+func EaseInOutQuart(t float64) float64 {
+	return easeInOut(EaseInQuart, t)
+}
+
+// EaseInSine accelerates along a quarter sine wave
+func EaseInSine(t float64) float64 {
+	return 1 - math.Cos(t*math.Pi/2)
+}
+
+// EaseOutSine decelerates along a quarter sine wave
+func EaseOutSine(t float64) float64 {
+	return easeOut(EaseInSine, t)
+}
+
+// EaseInOutSine accelerates and decelerates along a half sine wave
+func EaseInOutSine(t float64) float64 {
+	return easeInOut(EaseInSine, t)
+}
+
+// EaseInExpo accelerates exponentially
+func EaseInExpo(t float64) float64 {
+	if t <= 0 {
+		return 0
+	}
+	return math.Pow(2, 10*t-10)
+}
+
+// EaseOutExpo decelerates exponentially
+func EaseOutExpo(t float64) float64 {
+	return easeOut(EaseInExpo, t)
+}
+
+// EaseInOutExpo accelerates and decelerates exponentially
+func EaseInOutExpo(t float64) float64 {
+	return easeInOut(EaseInExpo, t)
+}
+
+// EaseInBack pulls back slightly before accelerating
+func EaseInBack(t float64) float64 {
+	return (easeBackOvershoot+1)*t*t*t - easeBackOvershoot*t*t
+}
+
+// EaseOutBack overshoots the target slightly before settling
+func EaseOutBack(t float64) float64 {
+	return easeOut(EaseInBack, t)
+}
+
+// EaseInOutBack pulls back at the start and overshoots at the end
+func EaseInOutBack(t float64) float64 {
+	return easeInOut(EaseInBack, t)
+}
+
+// EaseInElastic oscillates with growing amplitude before reaching the target
+func EaseInElastic(t float64) float64 {
+	if t <= 0 || t >= 1 {
+		return math.Max(0, math.Min(1, t))
+	}
+	return -math.Pow(2, 10*t-10) * math.Sin((10*t-10.75)*easeElasticPeriod)
+}
+
+// EaseOutElastic oscillates around the target with shrinking amplitude
+func EaseOutElastic(t float64) float64 {
+	return easeOut(EaseInElastic, t)
+}
+
+// EaseInOutElastic oscillates at the start and at the end
+func EaseInOutElastic(t float64) float64 {
+	return easeInOut(EaseInElastic, t)
+}
+
+// EaseInBounce bounces with growing height before reaching the target
+func EaseInBounce(t float64) float64 {
+	return easeOut(EaseOutBounce, t)
+}
+
+// EaseOutBounce bounces on the target with shrinking height
+func EaseOutBounce(t float64) float64 {
+	const (
+		n = 7.5625
+		d = 2.75
+	)
+	switch {
+	case t < 1/d:
+		return n * t * t
+	case t < 2/d:
+		t -= 1.5 / d
+		return n*t*t + 0.75
+	case t < 2.5/d:
+		t -= 2.25 / d
+		return n*t*t + 0.9375
+	default:
+		t -= 2.625 / d
+		return n*t*t + 0.984375
+	}
+}
+
+// EaseInOutBounce bounces at the start and at the end
+func EaseInOutBounce(t float64) float64 {
+	return easeInOut(EaseInBounce, t)
+}
+
+// easeOut mirrors an ease in function to an ease out function and vice versa
+func easeOut(in EasingFunc, t float64) float64 {
+	return 1 - in(1-t)
+}
+
+// easeInOut combines an ease in function for the first half with its mirror for the second half
+func easeInOut(in EasingFunc, t float64) float64 {
+	if t < 0.5 {
+		return in(2*t) / 2
+	}
+	return 1 - in(2-2*t)/2
+}
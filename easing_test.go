@@ -0,0 +1,55 @@
+package maths
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEasing(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		in, out, both EasingFunc
+	}{
+		{name: "Quad", in: EaseInQuad, out: EaseOutQuad, both: EaseInOutQuad},
+		{name: "Cubic", in: EaseInCubic, out: EaseOutCubic, both: EaseInOutCubic},
+		{name: "Quart", in: EaseInQuart, out: EaseOutQuart, both: EaseInOutQuart},
+		{name: "Sine", in: EaseInSine, out: EaseOutSine, both: EaseInOutSine},
+		{name: "Expo", in: EaseInExpo, out: EaseOutExpo, both: EaseInOutExpo},
+		{name: "Back", in: EaseInBack, out: EaseOutBack, both: EaseInOutBack},
+		{name: "Elastic", in: EaseInElastic, out: EaseOutElastic, both: EaseInOutElastic},
+		{name: "Bounce", in: EaseInBounce, out: EaseOutBounce, both: EaseInOutBounce},
+		{name: "Linear", in: EaseLinear, out: EaseLinear, both: EaseLinear},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			for _, f := range []EasingFunc{tt.in, tt.out, tt.both} {
+				assert.InDelta(t, 0, f(0), 1e-9)
+				assert.InDelta(t, 1, f(1), 1e-9)
+			}
+			assert.InDelta(t, 0.5, tt.both(0.5), 1e-9)
+
+			for i := 0; i <= 20; i++ {
+				x := float64(i) / 20
+				assert.InDelta(t, 1-tt.in(1-x), tt.out(x), 1e-9)
+				assert.InDelta(t, 1-tt.both(1-x), tt.both(x), 1e-9)
+			}
+		})
+	}
+}
+
+func TestEasingShape(t *testing.T) {
+	t.Parallel()
+
+	assert.InDelta(t, 0.25, EaseInQuad(0.5), 1e-12)
+	assert.InDelta(t, 0.875, EaseOutCubic(0.5), 1e-12)
+	assert.Less(t, EaseInBack(0.2), 0.0, "back pulls back")
+	assert.Greater(t, EaseOutBack(0.8), 1.0, "back overshoots")
+	assert.Greater(t, EaseOutElastic(0.1), 1.0, "elastic overshoots")
+	assert.InDelta(t, 0.75, EaseOutBounce(1/2.75+0.5/2.75), 1e-12)
+}
@@ -0,0 +1,320 @@
+package maths
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidEncoding is returned when text, JSON or binary data cannot be decoded
+var ErrInvalidEncoding = errors.New("maths: invalid encoding")
+
+// ErrUnsupportedHexMapValue is returned when the values of a HexMap have no binary encoding
+var ErrUnsupportedHexMapValue = errors.New("maths: unsupported hex map value")
+
+// hexMapMagic starts every binary encoded HexMap
+const hexMapMagic = "HXMP"
+
+// hexMapVersion is the version of the binary HexMap format written by MarshalBinary
+const hexMapVersion = 1
+
+// MarshalText encodes the hex as `q:r`, so hexes can be used as JSON map keys
+func (h Hex[T]) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+// UnmarshalText decodes a hex encoded as `q:r`
+func (h *Hex[T]) UnmarshalText(text []byte) error {
+	q, r, err := parsePair[T](text)
+	if err != nil {
+		return err
+	}
+	*h = Hex[T]{Q: q, R: r}
+	return nil
+}
+
+// MarshalJSON encodes the hex as an object `{"q":1,"r":2}`
+func (h Hex[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Q T `json:"q"`
+		R T `json:"r"`
+	}{h.Q, h.R})
+}
+
+// UnmarshalJSON decodes a hex encoded as an object or as a `q:r` string
+func (h *Hex[T]) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(data, []byte(`"`)) {
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+		return h.UnmarshalText([]byte(text))
+	}
+
+	var object struct {
+		Q T `json:"q"`
+		R T `json:"r"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	*h = Hex[T]{Q: object.Q, R: object.R}
+	return nil
+}
+
+// AppendBinary appends the binary encoding of the hex, varints for int64 and 8 bytes per coordinate for float64
+func (h Hex[T]) AppendBinary(b []byte) ([]byte, error) {
+	return appendPair(b, h.Q, h.R), nil
+}
+
+// MarshalBinary encodes the hex like AppendBinary
+func (h Hex[T]) MarshalBinary() ([]byte, error) {
+	return h.AppendBinary(nil)
+}
+
+// UnmarshalBinary decodes a hex encoded by MarshalBinary
+func (h *Hex[T]) UnmarshalBinary(data []byte) error {
+	q, r, n := readPair[T](data)
+	if n == 0 || n != len(data) {
+		return ErrInvalidEncoding
+	}
+	*h = Hex[T]{Q: q, R: r}
+	return nil
+}
+
+// MarshalText encodes the vector as `x:y`, so vectors can be used as JSON map keys
+func (v Vector2D[T]) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText decodes a vector encoded as `x:y`
+func (v *Vector2D[T]) UnmarshalText(text []byte) error {
+	x, y, err := parsePair[T](text)
+	if err != nil {
+		return err
+	}
+	*v = Vector2D[T]{X: x, Y: y}
+	return nil
+}
+
+// MarshalJSON encodes the vector as an object `{"x":1,"y":2}`
+func (v Vector2D[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		X T `json:"x"`
+		Y T `json:"y"`
+	}{v.X, v.Y})
+}
+
+// UnmarshalJSON decodes a vector encoded as an object or as a `x:y` string
+func (v *Vector2D[T]) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(data, []byte(`"`)) {
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+		return v.UnmarshalText([]byte(text))
+	}
+
+	var object struct {
+		X T `json:"x"`
+		Y T `json:"y"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	*v = Vector2D[T]{X: object.X, Y: object.Y}
+	return nil
+}
+
+// AppendBinary appends the binary encoding of the vector, varints for int64 and 8 bytes per coordinate for float64
+func (v Vector2D[T]) AppendBinary(b []byte) ([]byte, error) {
+	return appendPair(b, v.X, v.Y), nil
+}
+
+// MarshalBinary encodes the vector like AppendBinary
+func (v Vector2D[T]) MarshalBinary() ([]byte, error) {
+	return v.AppendBinary(nil)
+}
+
+// UnmarshalBinary decodes a vector encoded by MarshalBinary
+func (v *Vector2D[T]) UnmarshalBinary(data []byte) error {
+	x, y, n := readPair[T](data)
+	if n == 0 || n != len(data) {
+		return ErrInvalidEncoding
+	}
+	*v = Vector2D[T]{X: x, Y: y}
+	return nil
+}
+
+// MarshalBinary encodes the map in a versioned binary format. The hexes are
+// sorted and stored as varint deltas to the previous hex. Values implementing
+// encoding.BinaryMarshaler are stored with their length, other values need a
+// fixed size for encoding/binary like bool, numbers and structs of them.
+func (m HexMap[V]) MarshalBinary() ([]byte, error) {
+	data := append([]byte(hexMapMagic), hexMapVersion)
+	data = binary.AppendUvarint(data, uint64(len(m)))
+
+	var previous Hex[int64]
+	for _, h := range m.Hexes() {
+		data = appendPair(data, h.Q-previous.Q, h.R-previous.R)
+		previous = h
+
+		value := m[h]
+		if !hasBinaryUnmarshaler[V]() {
+			var err error
+			if data, err = binary.Append(data, binary.BigEndian, value); err != nil {
+				return nil, errors.Join(ErrUnsupportedHexMapValue, err)
+			}
+			continue
+		}
+
+		marshaler, ok := any(&value).(encoding.BinaryMarshaler)
+		if !ok {
+			return nil, ErrUnsupportedHexMapValue
+		}
+		encoded, err := marshaler.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		data = binary.AppendUvarint(data, uint64(len(encoded)))
+		data = append(data, encoded...)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decodes a map encoded by MarshalBinary and replaces the content of the map
+func (m *HexMap[V]) UnmarshalBinary(data []byte) error {
+	if !bytes.HasPrefix(data, []byte(hexMapMagic)) || len(data) < len(hexMapMagic)+1 || data[len(hexMapMagic)] != hexMapVersion {
+		return ErrInvalidEncoding
+	}
+	data = data[len(hexMapMagic)+1:]
+
+	count, n := binary.Uvarint(data)
+	if n <= 0 || count > uint64(len(data)) {
+		return ErrInvalidEncoding
+	}
+	data = data[n:]
+
+	decoded := make(HexMap[V], count)
+	var previous Hex[int64]
+	for i := range count {
+		dq, dr, n := readPair[int64](data)
+		if n <= 0 {
+			return ErrInvalidEncoding
+		}
+		data = data[n:]
+
+		// Hexes are strictly sorted, which also rules out duplicates
+		h := previous.Add(Hex[int64]{Q: dq, R: dr})
+		if i > 0 && compareHex(previous, h) >= 0 {
+			return ErrInvalidEncoding
+		}
+		previous = h
+
+		var value V
+		if unmarshaler, ok := any(&value).(encoding.BinaryUnmarshaler); ok {
+			length, n := binary.Uvarint(data)
+			if n <= 0 || length > uint64(len(data)-n) {
+				return ErrInvalidEncoding
+			}
+			if err := unmarshaler.UnmarshalBinary(data[n : n+int(length)]); err != nil {
+				return errors.Join(ErrInvalidEncoding, err)
+			}
+			data = data[n+int(length):]
+		} else {
+			n, err := binary.Decode(data, binary.BigEndian, &value)
+			if err != nil {
+				return errors.Join(ErrInvalidEncoding, err)
+			}
+			data = data[n:]
+		}
+		decoded[h] = value
+	}
+
+	if len(data) > 0 {
+		return ErrInvalidEncoding
+	}
+	*m = decoded
+	return nil
+}
+
+// hasBinaryUnmarshaler reports whether values of type V decode themselves
+func hasBinaryUnmarshaler[V any]() bool {
+	_, ok := any(new(V)).(encoding.BinaryUnmarshaler)
+	return ok
+}
+
+// parsePair parses two numbers separated by a colon
+func parsePair[T interface {
+	int64 | float64
+}](text []byte) (T, T, error) {
+	first, second, ok := strings.Cut(string(text), ":")
+	if !ok {
+		return 0, 0, ErrInvalidEncoding
+	}
+	a, errA := parseNumber[T](first)
+	b, errB := parseNumber[T](second)
+	if errA != nil || errB != nil {
+		return 0, 0, errors.Join(ErrInvalidEncoding, errA, errB)
+	}
+	return a, b, nil
+}
+
+// parseNumber parses an int64 or float64
+func parseNumber[T interface {
+	int64 | float64
+}](s string) (T, error) {
+	var zero T
+	if _, ok := any(zero).(int64); ok {
+		n, err := strconv.ParseInt(s, 10, 64)
+		return T(n), err
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	return T(f), err
+}
+
+// appendPair appends two numbers as varints for int64 and big endian bits for float64
+func appendPair[T interface {
+	int64 | float64
+}](b []byte, x, y T) []byte {
+	for _, value := range []T{x, y} {
+		switch value := any(value).(type) {
+		case int64:
+			b = binary.AppendVarint(b, value)
+		case float64:
+			b = binary.BigEndian.AppendUint64(b, math.Float64bits(value))
+		}
+	}
+	return b
+}
+
+// readPair reads two numbers written by appendPair and returns the number of bytes read, 0 on errors
+func readPair[T interface {
+	int64 | float64
+}](data []byte) (T, T, int) {
+	var values [2]T
+	read := 0
+	for i := range values {
+		switch value := any(&values[i]).(type) {
+		case *int64:
+			n := 0
+			*value, n = binary.Varint(data[read:])
+			if n <= 0 {
+				return 0, 0, 0
+			}
+			read += n
+		case *float64:
+			if len(data[read:]) < 8 {
+				return 0, 0, 0
+			}
+			*value = math.Float64frombits(binary.BigEndian.Uint64(data[read:]))
+			read += 8
+		}
+	}
+	return values[0], values[1], read
+}
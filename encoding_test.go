@@ -0,0 +1,302 @@
+package maths
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHexText(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		text string
+		hex  Hex[int64]
+	}{
+		{"0:0", Hex[int64]{}},
+		{"3:-7", Hex[int64]{Q: 3, R: -7}},
+		{"-9223372036854775808:9223372036854775807", Hex[int64]{Q: math.MinInt64, R: math.MaxInt64}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			t.Parallel()
+
+			text, err := tt.hex.MarshalText()
+			require.NoError(t, err)
+			assert.Equal(t, tt.text, string(text))
+
+			var h Hex[int64]
+			require.NoError(t, h.UnmarshalText(text))
+			assert.Equal(t, tt.hex, h)
+		})
+	}
+
+	var f Hex[float64]
+	require.NoError(t, f.UnmarshalText([]byte("1.5:-2e3")))
+	assert.Equal(t, Hex[float64]{Q: 1.5, R: -2000}, f)
+
+	for _, invalid := range []string{"", "1", "1:", ":1", "1:2:3", "a:b", "1.5:2"} {
+		var h Hex[int64]
+		assert.ErrorIs(t, h.UnmarshalText([]byte(invalid)), ErrInvalidEncoding, invalid)
+	}
+}
+
+func TestVector2DText(t *testing.T) {
+	t.Parallel()
+
+	v := Vector2D[float64]{X: 0.1, Y: -math.MaxFloat64}
+	text, err := v.MarshalText()
+	require.NoError(t, err)
+
+	var decoded Vector2D[float64]
+	require.NoError(t, decoded.UnmarshalText(text))
+	assert.Equal(t, v, decoded)
+
+	var i Vector2D[int64]
+	require.NoError(t, i.UnmarshalText([]byte("4:-2")))
+	assert.Equal(t, Vector2D[int64]{X: 4, Y: -2}, i)
+	assert.ErrorIs(t, i.UnmarshalText([]byte("4;-2")), ErrInvalidEncoding)
+}
+
+func TestHexJSON(t *testing.T) {
+	t.Parallel()
+
+	encoded, err := json.Marshal(Hex[int64]{Q: 1, R: -2})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"q":1,"r":-2}`, string(encoded))
+
+	// Hexes are map keys in their text form
+	costs := map[Hex[int64]]float64{{Q: 1, R: -2}: 1.5, {Q: 0, R: 3}: 2}
+	encoded, err = json.Marshal(costs)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"1:-2":1.5,"0:3":2}`, string(encoded))
+
+	var decodedCosts map[Hex[int64]]float64
+	require.NoError(t, json.Unmarshal(encoded, &decodedCosts))
+	assert.Equal(t, costs, decodedCosts)
+
+	// Objects and strings are accepted as values
+	var hexes []Hex[int64]
+	require.NoError(t, json.Unmarshal([]byte(`[{"q":4,"r":5},"6:-7"]`), &hexes))
+	assert.Equal(t, []Hex[int64]{{Q: 4, R: 5}, {Q: 6, R: -7}}, hexes)
+
+	var h Hex[int64]
+	assert.Error(t, json.Unmarshal([]byte(`{"q":"a"}`), &h))
+	assert.ErrorIs(t, json.Unmarshal([]byte(`"a:b"`), &h), ErrInvalidEncoding)
+}
+
+func TestVector2DJSON(t *testing.T) {
+	t.Parallel()
+
+	type waypoint struct {
+		Position Vector2D[float64] `json:"position"`
+	}
+
+	encoded, err := json.Marshal(waypoint{Position: Vector2D[float64]{X: 1.5, Y: -2}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"position":{"x":1.5,"y":-2}}`, string(encoded))
+
+	var decoded waypoint
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, Vector2D[float64]{X: 1.5, Y: -2}, decoded.Position)
+
+	encoded, err = json.Marshal(map[Vector2D[int64]]string{{X: 1, Y: 2}: "chest"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"1:2":"chest"}`, string(encoded))
+}
+
+func TestHexBinary(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		hex  Hex[int64]
+		size int
+	}{
+		{"Zero", Hex[int64]{}, 2},
+		{"Small", Hex[int64]{Q: -3, R: 50}, 2},
+		{"Large", Hex[int64]{Q: 1000, R: -100000}, 5},
+		{"Extreme", Hex[int64]{Q: math.MinInt64, R: math.MaxInt64}, 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := tt.hex.MarshalBinary()
+			require.NoError(t, err)
+			assert.Len(t, data, tt.size)
+
+			var h Hex[int64]
+			require.NoError(t, h.UnmarshalBinary(data))
+			assert.Equal(t, tt.hex, h)
+
+			assert.ErrorIs(t, h.UnmarshalBinary(data[:len(data)-1]), ErrInvalidEncoding)
+			assert.ErrorIs(t, h.UnmarshalBinary(append(data, 0)), ErrInvalidEncoding)
+		})
+	}
+
+	f := Hex[float64]{Q: 0.5, R: math.Inf(-1)}
+	data, err := f.AppendBinary([]byte{42})
+	require.NoError(t, err)
+	assert.Len(t, data, 17)
+
+	var decoded Hex[float64]
+	require.NoError(t, decoded.UnmarshalBinary(data[1:]))
+	assert.Equal(t, f, decoded)
+}
+
+func TestVector2DBinary(t *testing.T) {
+	t.Parallel()
+
+	v := Vector2D[int64]{X: -64, Y: 63}
+	data, err := v.MarshalBinary()
+	require.NoError(t, err)
+	assert.Len(t, data, 2)
+
+	var decoded Vector2D[int64]
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, v, decoded)
+
+	var f Vector2D[float64]
+	assert.ErrorIs(t, f.UnmarshalBinary(data), ErrInvalidEncoding)
+	assert.ErrorIs(t, f.UnmarshalBinary(nil), ErrInvalidEncoding)
+}
+
+func TestHexMapBinary(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Fixed size values", func(t *testing.T) {
+		t.Parallel()
+
+		heights := HexMap[int32]{}
+		for i, h := range (Hex[int64]{Q: 5, R: -3}).Spiral(3) {
+			heights[h] = int32(i * 7)
+		}
+
+		data, err := heights.MarshalBinary()
+		require.NoError(t, err)
+		assert.Equal(t, "HXMP\x01", string(data[:5]))
+
+		var decoded HexMap[int32]
+		require.NoError(t, decoded.UnmarshalBinary(data))
+		assert.Equal(t, heights, decoded)
+
+		// The encoding does not depend on the map iteration order
+		again, err := heights.MarshalBinary()
+		require.NoError(t, err)
+		assert.Equal(t, data, again)
+	})
+
+	t.Run("Marshaler values", func(t *testing.T) {
+		t.Parallel()
+
+		targets := HexMap[Hex[int64]]{{Q: 1, R: 1}: {Q: -4, R: 2}, {Q: -1, R: 0}: {}}
+		data, err := targets.MarshalBinary()
+		require.NoError(t, err)
+
+		var decoded HexMap[Hex[int64]]
+		require.NoError(t, decoded.UnmarshalBinary(data))
+		assert.Equal(t, targets, decoded)
+	})
+
+	t.Run("Empty values", func(t *testing.T) {
+		t.Parallel()
+
+		blocked := NewHexMap((Hex[int64]{}).Spiral(2), struct{}{})
+		data, err := blocked.MarshalBinary()
+		require.NoError(t, err)
+
+		var decoded HexMap[struct{}]
+		require.NoError(t, decoded.UnmarshalBinary(data))
+		assert.Equal(t, blocked, decoded)
+	})
+
+	t.Run("Unsupported values", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := HexMap[string]{{}: "grass"}.MarshalBinary()
+		assert.ErrorIs(t, err, ErrUnsupportedHexMapValue)
+	})
+
+	t.Run("Invalid data", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := HexMap[bool]{{}: true, {Q: 1}: false}.MarshalBinary()
+		require.NoError(t, err)
+
+		invalid := map[string][]byte{
+			"Empty":     nil,
+			"Magic":     append([]byte("HXMQ"), data[4:]...),
+			"Version":   append([]byte("HXMP\x02"), data[5:]...),
+			"Truncated": data[:len(data)-1],
+			"Trailing":  append(data, 0),
+			"Duplicate": []byte("HXMP\x01\x02\x00\x00\x01\x00\x00\x01"),
+			"Count":     []byte("HXMP\x01\x7f\x00\x00\x01"),
+		}
+		for name, data := range invalid {
+			var decoded HexMap[bool]
+			assert.ErrorIs(t, decoded.UnmarshalBinary(data), ErrInvalidEncoding, name)
+		}
+	})
+}
+
+func FuzzHexMapBinary(f *testing.F) {
+	for _, radius := range []int{0, 1, 3} {
+		m := HexMap[uint16]{}
+		for i, h := range (Hex[int64]{Q: -2, R: 7}).Spiral(radius) {
+			m[h] = uint16(i)
+		}
+		data, err := m.MarshalBinary()
+		require.NoError(f, err)
+		f.Add(data)
+	}
+	f.Add([]byte("HXMP\x01\x00"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var decoded HexMap[uint16]
+		if decoded.UnmarshalBinary(data) != nil {
+			return
+		}
+
+		// Everything decoded encodes and decodes to the same map
+		encoded, err := decoded.MarshalBinary()
+		require.NoError(t, err)
+
+		var again HexMap[uint16]
+		require.NoError(t, again.UnmarshalBinary(encoded))
+		assert.Equal(t, decoded, again)
+	})
+}
+
+func FuzzHexText(f *testing.F) {
+	f.Add(int64(0), int64(0))
+	f.Add(int64(-5), int64(12))
+	f.Add(int64(math.MinInt64), int64(math.MaxInt64))
+
+	f.Fuzz(func(t *testing.T, q, r int64) {
+		h := Hex[int64]{Q: q, R: r}
+
+		text, err := h.MarshalText()
+		require.NoError(t, err)
+		var fromText Hex[int64]
+		require.NoError(t, fromText.UnmarshalText(text))
+		assert.Equal(t, h, fromText)
+
+		data, err := h.MarshalBinary()
+		require.NoError(t, err)
+		var fromBinary Hex[int64]
+		require.NoError(t, fromBinary.UnmarshalBinary(data))
+		assert.Equal(t, h, fromBinary)
+
+		encoded, err := json.Marshal(h)
+		require.NoError(t, err)
+		var fromJSON Hex[int64]
+		require.NoError(t, json.Unmarshal(encoded, &fromJSON))
+		assert.Equal(t, h, fromJSON)
+	})
+}
@@ -0,0 +1,200 @@
+package maths
+
+import (
+	"container/heap"
+	"math"
+)
+
+// HexCostFunc returns the cost of entering a hex. Negative or infinite costs mark impassable hexes.
+type HexCostFunc func(h Hex[int64]) float64
+
+// DijkstraMap holds the cheapest travel cost from every reachable hex to the
+// nearest goal and the flow field towards it. Moving from a hex to a neighbour
+// costs the cost of the neighbour.
+type DijkstraMap struct {
+	goals       HexMap[struct{}]
+	cost        HexCostFunc
+	maxDistance float64
+	distances   HexMap[float64]
+	flow        HexMap[int]
+}
+
+// NewDijkstraMap computes the costs to the goals for all hexes reachable within
+// maxDistance. The cost function has to bound the search by marking the outside
+// of the map impassable unless maxDistance is finite.
+func NewDijkstraMap(goals []Hex[int64], cost HexCostFunc, maxDistance float64) *DijkstraMap {
+	m := &DijkstraMap{
+		goals:       NewHexMap(goals, struct{}{}),
+		cost:        cost,
+		maxDistance: maxDistance,
+		distances:   make(HexMap[float64]),
+		flow:        make(HexMap[int]),
+	}
+
+	queue := &gridQueue[Hex[int64]]{}
+	for _, goal := range m.goals.Hexes() {
+		if m.passable(goal) {
+			m.distances[goal] = 0
+			heap.Push(queue, gridQueueItem[Hex[int64]]{cell: goal})
+		}
+	}
+
+	touched := m.relax(queue)
+	m.updateFlow(touched)
+	return m
+}
+
+// Distance returns the cost from the hex to the nearest goal, false if the goal cannot be reached
+func (m *DijkstraMap) Distance(h Hex[int64]) (float64, bool) {
+	distance, ok := m.distances[h]
+	return distance, ok
+}
+
+// Direction returns the index of the direction towards the nearest goal as
+// used by HexDirection. Goals have the direction -1 and unreachable hexes false.
+func (m *DijkstraMap) Direction(h Hex[int64]) (int, bool) {
+	direction, ok := m.flow[h]
+	return direction, ok
+}
+
+// Next returns the neighbour to move to from the hex, false for goals and unreachable hexes
+func (m *DijkstraMap) Next(h Hex[int64]) (Hex[int64], bool) {
+	direction, ok := m.flow[h]
+	if !ok || direction < 0 {
+		return Hex[int64]{}, false
+	}
+	return h.Add(directions[direction]), true
+}
+
+// Distances returns the costs of all reachable hexes. The map must not be modified.
+func (m *DijkstraMap) Distances() HexMap[float64] {
+	return m.distances
+}
+
+// FlowField returns the direction index of all reachable hexes. The map must not be modified.
+func (m *DijkstraMap) FlowField() HexMap[int] {
+	return m.flow
+}
+
+// Update recomputes the map after the costs of the changed hexes were modified.
+// Only hexes whose path to a goal passes the changed hexes and hexes which get
+// cheaper are visited again.
+func (m *DijkstraMap) Update(changed ...Hex[int64]) {
+	// Hexes whose flow leads over a changed hex lose their distance
+	invalid := make(HexMap[struct{}])
+	stack := append([]Hex[int64](nil), changed...)
+	for len(stack) > 0 {
+		h := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if invalid.Has(h) {
+			continue
+		}
+		invalid[h] = struct{}{}
+
+		for i, dir := range directions {
+			child := h.Add(dir)
+			if direction, ok := m.flow[child]; ok && direction == (i+3)%6 {
+				stack = append(stack, child)
+			}
+		}
+	}
+	for h := range invalid {
+		delete(m.distances, h)
+		delete(m.flow, h)
+	}
+
+	// Restart from the valid border of the invalidated hexes
+	queue := &gridQueue[Hex[int64]]{}
+	for _, h := range invalid.Hexes() {
+		if !m.passable(h) {
+			continue
+		}
+		if m.goals.Has(h) {
+			m.distances[h] = 0
+			heap.Push(queue, gridQueueItem[Hex[int64]]{cell: h})
+			continue
+		}
+
+		best := math.Inf(1)
+		for _, dir := range directions {
+			n := h.Add(dir)
+			if distance, ok := m.distances[n]; ok {
+				best = math.Min(best, distance+m.cost(n))
+			}
+		}
+		if best <= m.maxDistance {
+			m.distances[h] = best
+			heap.Push(queue, gridQueueItem[Hex[int64]]{cell: h, priority: best})
+		}
+	}
+
+	touched := m.relax(queue)
+	for h := range invalid {
+		touched[h] = struct{}{}
+	}
+	m.updateFlow(touched)
+}
+
+// relax runs Dijkstra's algorithm from the queued hexes and returns all hexes whose distance changed
+func (m *DijkstraMap) relax(queue *gridQueue[Hex[int64]]) HexMap[struct{}] {
+	touched := make(HexMap[struct{}])
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(gridQueueItem[Hex[int64]])
+		if item.priority > m.distances[item.cell] {
+			continue
+		}
+		touched[item.cell] = struct{}{}
+
+		// Every neighbour moves onto this hex at its cost
+		distance := item.priority + m.cost(item.cell)
+		if distance > m.maxDistance {
+			continue
+		}
+		for _, dir := range directions {
+			n := item.cell.Add(dir)
+			if current, ok := m.distances[n]; (ok && current <= distance) || !m.passable(n) {
+				continue
+			}
+			m.distances[n] = distance
+			heap.Push(queue, gridQueueItem[Hex[int64]]{cell: n, priority: distance})
+		}
+	}
+	return touched
+}
+
+// updateFlow recomputes the direction of the hexes and their neighbours.
+// Ties go to the lowest direction index so the field does not depend on the update order.
+func (m *DijkstraMap) updateFlow(touched HexMap[struct{}]) {
+	update := make(HexMap[struct{}], len(touched))
+	for h := range touched {
+		update[h] = struct{}{}
+		for _, dir := range directions {
+			update[h.Add(dir)] = struct{}{}
+		}
+	}
+
+	for h := range update {
+		if _, ok := m.distances[h]; !ok {
+			delete(m.flow, h)
+			continue
+		}
+		if m.goals.Has(h) {
+			m.flow[h] = -1
+			continue
+		}
+
+		best, bestDistance := -1, math.Inf(1)
+		for i, dir := range directions {
+			n := h.Add(dir)
+			if distance, ok := m.distances[n]; ok && distance+m.cost(n) < bestDistance {
+				best, bestDistance = i, distance+m.cost(n)
+			}
+		}
+		m.flow[h] = best
+	}
+}
+
+// passable reports whether the hex can be entered
+func (m *DijkstraMap) passable(h Hex[int64]) bool {
+	return passable(m.cost, h)
+}
@@ -0,0 +1,163 @@
+package maths
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testCosts returns a cost function over a hexagonal map with the given costs, hexes missing in costs cost 1
+func testCosts(radius int, costs HexMap[float64]) HexCostFunc {
+	return func(h Hex[int64]) float64 {
+		if h.Distance(Hex[int64]{}) > float64(radius) {
+			return math.Inf(1)
+		}
+		if cost, ok := costs[h]; ok {
+			return cost
+		}
+		return 1
+	}
+}
+
+// bruteDistances computes the Dijkstra map by relaxing all hexes until nothing changes
+func bruteDistances(radius int, goals []Hex[int64], cost HexCostFunc) HexMap[float64] {
+	distances := make(HexMap[float64])
+	for _, goal := range goals {
+		if c := cost(goal); c >= 0 && !math.IsInf(c, 1) {
+			distances[goal] = 0
+		}
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for _, h := range (Hex[int64]{}).Spiral(radius) {
+			if c := cost(h); c < 0 || math.IsInf(c, 1) {
+				continue
+			}
+			for _, n := range h.Neighbours() {
+				d, ok := distances[n]
+				if !ok {
+					continue
+				}
+				if current, ok := distances[h]; !ok || d+cost(n) < current {
+					distances[h] = d + cost(n)
+					changed = true
+				}
+			}
+		}
+	}
+	return distances
+}
+
+func TestDijkstraMap(t *testing.T) {
+	t.Parallel()
+
+	costs := make(HexMap[float64])
+	for _, h := range (Hex[int64]{Q: 2, R: 0}).LineTo(Hex[int64]{Q: 2, R: -5}) {
+		costs[h] = -1 // wall
+	}
+	costs[Hex[int64]{Q: -2, R: 1}] = 5
+	costs[Hex[int64]{Q: -3, R: 4}] = 0.5
+	cost := testCosts(6, costs)
+	goals := []Hex[int64]{{Q: 4, R: -2}, {Q: -4, R: 0}}
+
+	m := NewDijkstraMap(goals, cost, math.Inf(1))
+	assert.Equal(t, bruteDistances(6, goals, cost), m.Distances())
+
+	for h, distance := range m.Distances() {
+		direction, ok := m.Direction(h)
+		assert.True(t, ok)
+		if distance == 0 {
+			assert.Equal(t, -1, direction)
+			_, ok = m.Next(h)
+			assert.False(t, ok)
+			continue
+		}
+
+		// Following the flow reaches a goal and pays exactly the distance
+		paid := 0.0
+		for current := h; ; {
+			next, ok := m.Next(current)
+			if !ok {
+				break
+			}
+			assert.Equal(t, current.Add(HexDirection(m.FlowField()[current])), next)
+			paid += cost(next)
+			current = next
+		}
+		assert.InDelta(t, distance, paid, 1e-9)
+	}
+
+	_, ok := m.Distance(Hex[int64]{Q: 2, R: -1})
+	assert.False(t, ok, "walls are not reachable")
+	_, ok = m.Distance(Hex[int64]{Q: 20, R: 0})
+	assert.False(t, ok, "outside of the map")
+}
+
+func TestDijkstraMapMaxDistance(t *testing.T) {
+	t.Parallel()
+
+	// Unbounded costs are limited by the maximum distance
+	m := NewDijkstraMap([]Hex[int64]{{}}, func(Hex[int64]) float64 { return 1 }, 3)
+	assert.Len(t, m.Distances(), len(Hex[int64]{}.Spiral(3)))
+	for h, distance := range m.Distances() {
+		assert.Equal(t, h.Distance(Hex[int64]{}), distance)
+	}
+}
+
+func TestDijkstraMapUpdate(t *testing.T) {
+	t.Parallel()
+
+	const radius = 8
+	costs := make(HexMap[float64])
+	cost := testCosts(radius, costs)
+	goals := []Hex[int64]{{Q: 0, R: 0}, {Q: 5, R: -5}}
+	m := NewDijkstraMap(goals, cost, math.Inf(1))
+
+	rng := NewRNG(9)
+	hexes := Hex[int64]{}.Spiral(radius)
+	values := []float64{-1, 1, 2, 4, math.Inf(1), 0}
+	for round := 0; round < 50; round++ {
+		var changed []Hex[int64]
+		for i := 0; i < 1+rng.IntN(4); i++ {
+			h := hexes[rng.IntN(len(hexes))]
+			costs[h] = values[rng.IntN(len(values))]
+			changed = append(changed, h)
+		}
+		m.Update(changed...)
+
+		// Same result as building the map from scratch
+		full := NewDijkstraMap(goals, cost, math.Inf(1))
+		assert.Equal(t, full.Distances(), m.Distances(), "round %d", round)
+		assert.Equal(t, full.FlowField(), m.FlowField(), "round %d", round)
+	}
+}
+
+func BenchmarkDijkstraMap(b *testing.B) {
+	costs := make(HexMap[float64])
+	for _, h := range (Hex[int64]{Q: 5, R: -20}).LineTo(Hex[int64]{Q: 5, R: 10}) {
+		costs[h] = -1
+	}
+	cost := testCosts(30, costs)
+	goals := []Hex[int64]{{Q: 20, R: -10}}
+
+	b.Run("Full", func(b *testing.B) {
+		for b.Loop() {
+			NewDijkstraMap(goals, cost, math.Inf(1))
+		}
+	})
+
+	b.Run("Update", func(b *testing.B) {
+		m := NewDijkstraMap(goals, cost, math.Inf(1))
+		h := Hex[int64]{Q: -20, R: 5}
+		for b.Loop() {
+			if costs[h] == 3 {
+				costs[h] = 1
+			} else {
+				costs[h] = 3
+			}
+			m.Update(h)
+		}
+	})
+}
@@ -0,0 +1,279 @@
+package maths
+
+import (
+	"container/heap"
+	"math"
+	"slices"
+)
+
+// Grid is the topology of a tiled map with cells of type C, so algorithms can be
+// written once for hex, square, isometric and triangle grids
+type Grid[C comparable] interface {
+	// Neighbours returns the cells reachable with a single step from the cell
+	Neighbours(cell C) []C
+	// Distance returns the lowest number of steps between two cells
+	Distance(a, b C) float64
+	// CellToWorld returns the world position of the cell center
+	CellToWorld(cell C) Vector2D[float64]
+	// WorldToCell returns the cell containing the world position
+	WorldToCell(p Vector2D[float64]) C
+}
+
+// HexTopology is the Grid of hexes placed by a layout
+type HexTopology struct {
+	Layout HexLayout
+}
+
+// NewHexTopology creates a new hex topology for the layout
+func NewHexTopology(layout HexLayout) HexTopology {
+	return HexTopology{Layout: layout}
+}
+
+// Neighbours returns the six adjacent hexes
+func (g HexTopology) Neighbours(cell Hex[int64]) []Hex[int64] {
+	return cell.Neighbours()
+}
+
+// Distance returns the hex distance
+func (g HexTopology) Distance(a, b Hex[int64]) float64 {
+	return a.Distance(b)
+}
+
+// CellToWorld returns the world position of the hex center
+func (g HexTopology) CellToWorld(cell Hex[int64]) Vector2D[float64] {
+	return g.Layout.HexToVector2D(cell.ToFloat())
+}
+
+// WorldToCell returns the hex containing the world position
+func (g HexTopology) WorldToCell(p Vector2D[float64]) Hex[int64] {
+	return hexRound(g.Layout.Vector2DToHex(p))
+}
+
+// SquareTopology is the Grid of a square grid, Diagonal allows steps to the corner neighbours
+type SquareTopology struct {
+	Grid     *SquareGrid
+	Diagonal bool
+}
+
+// NewSquareTopology creates a new topology for the square grid
+func NewSquareTopology(grid *SquareGrid, diagonal bool) SquareTopology {
+	return SquareTopology{Grid: grid, Diagonal: diagonal}
+}
+
+// Neighbours returns the four edge neighbours, or all eight neighbours with diagonal steps
+func (g SquareTopology) Neighbours(cell Vector2D[int64]) []Vector2D[int64] {
+	if g.Diagonal {
+		return g.Grid.Neighbours8(cell)
+	}
+	return g.Grid.Neighbours4(cell)
+}
+
+// Distance returns the Manhattan distance, or the Chebyshev distance with diagonal steps
+func (g SquareTopology) Distance(a, b Vector2D[int64]) float64 {
+	return stepDistance(a, b, g.Diagonal)
+}
+
+// CellToWorld returns the world position of the cell center
+func (g SquareTopology) CellToWorld(cell Vector2D[int64]) Vector2D[float64] {
+	return g.Grid.CellToWorld(cell)
+}
+
+// WorldToCell returns the cell containing the world position
+func (g SquareTopology) WorldToCell(p Vector2D[float64]) Vector2D[int64] {
+	return g.Grid.WorldToCell(p)
+}
+
+// IsoTopology is the Grid of an isometric grid, Diagonal allows steps to the corner neighbours
+type IsoTopology struct {
+	Grid     *IsoGrid
+	Diagonal bool
+}
+
+// NewIsoTopology creates a new topology for the isometric grid
+func NewIsoTopology(grid *IsoGrid, diagonal bool) IsoTopology {
+	return IsoTopology{Grid: grid, Diagonal: diagonal}
+}
+
+// Neighbours returns the four edge neighbours, or all eight neighbours with diagonal steps
+func (g IsoTopology) Neighbours(cell Vector2D[int64]) []Vector2D[int64] {
+	if g.Diagonal {
+		return g.Grid.Neighbours8(cell)
+	}
+	return g.Grid.Neighbours4(cell)
+}
+
+// Distance returns the number of steps in diamond coordinates
+func (g IsoTopology) Distance(a, b Vector2D[int64]) float64 {
+	return stepDistance(g.Grid.toDiamond(a), g.Grid.toDiamond(b), g.Diagonal)
+}
+
+// CellToWorld returns the world position of the tile center
+func (g IsoTopology) CellToWorld(cell Vector2D[int64]) Vector2D[float64] {
+	return g.Grid.CellToWorld(cell)
+}
+
+// WorldToCell returns the tile containing the world position
+func (g IsoTopology) WorldToCell(p Vector2D[float64]) Vector2D[int64] {
+	return g.Grid.WorldToCell(p)
+}
+
+// TriangleTopology is the Grid of triangles placed by a layout
+type TriangleTopology struct {
+	Layout TriangleLayout
+}
+
+// NewTriangleTopology creates a new triangle topology for the layout
+func NewTriangleTopology(layout TriangleLayout) TriangleTopology {
+	return TriangleTopology{Layout: layout}
+}
+
+// Neighbours returns the three triangles sharing an edge
+func (g TriangleTopology) Neighbours(cell Triangle) []Triangle {
+	return cell.Neighbours()
+}
+
+// Distance returns the number of edges to cross
+func (g TriangleTopology) Distance(a, b Triangle) float64 {
+	return a.Distance(b)
+}
+
+// CellToWorld returns the world position of the triangle center
+func (g TriangleTopology) CellToWorld(cell Triangle) Vector2D[float64] {
+	return g.Layout.TriangleToVector2D(cell)
+}
+
+// WorldToCell returns the triangle containing the world position
+func (g TriangleTopology) WorldToCell(p Vector2D[float64]) Triangle {
+	return g.Layout.Vector2DToTriangle(p)
+}
+
+// GridAStar finds the cheapest path from start to goal on any grid. Entering a
+// cell costs what the cost function returns for it, negative, infinite and NaN
+// costs mark impassable cells like HexCostFunc. MinCost is the lowest cost of
+// any passable cell. The path includes start and goal. The cost function has to
+// mark the outside of the map impassable.
+func GridAStar[C comparable](
+	grid Grid[C],
+	start, goal C,
+	cost func(cell C) float64,
+	minCost float64,
+) ([]C, float64, bool) {
+	return gridAStar(grid, start, goal, cost, minCost, nil)
+}
+
+// gridAStar is GridAStar limited to the cells accepted by allowed, nil allows every cell
+func gridAStar[C comparable](
+	grid Grid[C],
+	start, goal C,
+	cost func(cell C) float64,
+	minCost float64,
+	allowed func(cell C) bool,
+) ([]C, float64, bool) {
+	if !passable(cost, start) || !passable(cost, goal) {
+		return nil, 0, false
+	}
+
+	distances := map[C]float64{start: 0}
+	previous := make(map[C]C)
+	queue := &gridQueue[C]{{cell: start, priority: grid.Distance(start, goal) * minCost}}
+	closed := make(map[C]bool)
+	order := 0
+
+	for queue.Len() > 0 {
+		current := heap.Pop(queue).(gridQueueItem[C]).cell
+		if current == goal {
+			return gridPath(previous, start, goal), distances[goal], true
+		}
+		if closed[current] {
+			continue
+		}
+		closed[current] = true
+
+		for _, next := range grid.Neighbours(current) {
+			if closed[next] || !passable(cost, next) || (allowed != nil && !allowed(next)) {
+				continue
+			}
+
+			distance := distances[current] + cost(next)
+			if known, ok := distances[next]; ok && known <= distance {
+				continue
+			}
+			distances[next] = distance
+			previous[next] = current
+			order++
+			heap.Push(queue, gridQueueItem[C]{cell: next, priority: distance + grid.Distance(next, goal)*minCost, order: order})
+		}
+	}
+	return nil, 0, false
+}
+
+// gridPath follows the previous cells back from goal to start and returns the path from start to goal
+func gridPath[C comparable](previous map[C]C, start, goal C) []C {
+	path := []C{goal}
+	for cell := goal; cell != start; {
+		cell = previous[cell]
+		path = append(path, cell)
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// GridFloodFill returns the number of steps from start to every cell reachable
+// within maxSteps over cells accepted by passable
+func GridFloodFill[C comparable](grid Grid[C], start C, maxSteps int, passable func(cell C) bool) map[C]int {
+	if !passable(start) {
+		return map[C]int{}
+	}
+	steps := map[C]int{start: 0}
+
+	frontier := []C{start}
+	for step := 1; step <= maxSteps && len(frontier) > 0; step++ {
+		var next []C
+		for _, cell := range frontier {
+			for _, n := range grid.Neighbours(cell) {
+				if _, ok := steps[n]; ok || !passable(n) {
+					continue
+				}
+				steps[n] = step
+				next = append(next, n)
+			}
+		}
+		frontier = next
+	}
+	return steps
+}
+
+// gridQueueItem is a cell waiting in the open list of a search
+type gridQueueItem[C comparable] struct {
+	cell     C
+	priority float64
+	order    int
+}
+
+// gridQueue is a min heap of cells ordered by priority and insertion order
+type gridQueue[C comparable] []gridQueueItem[C]
+
+func (q gridQueue[C]) Len() int { return len(q) }
+func (q gridQueue[C]) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority < q[j].priority
+	}
+	return q[i].order < q[j].order
+}
+func (q gridQueue[C]) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *gridQueue[C]) Push(x any)   { *q = append(*q, x.(gridQueueItem[C])) }
+func (q *gridQueue[C]) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// stepDistance returns the Manhattan distance of two cells, or the Chebyshev distance with diagonal steps
+func stepDistance(a, b Vector2D[int64], diagonal bool) float64 {
+	dx, dy := math.Abs(float64(a.X-b.X)), math.Abs(float64(a.Y-b.Y))
+	if diagonal {
+		return math.Max(dx, dy)
+	}
+	return dx + dy
+}
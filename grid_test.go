@@ -0,0 +1,136 @@
+package maths
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ Grid[Hex[int64]]      = HexTopology{}
+	_ Grid[Vector2D[int64]] = SquareTopology{}
+	_ Grid[Vector2D[int64]] = IsoTopology{}
+	_ Grid[Triangle]        = TriangleTopology{}
+)
+
+// assertTopology checks that neighbours are a single step away and cells survive a round trip through the world
+func assertTopology[C comparable](t *testing.T, grid Grid[C], cells []C) {
+	t.Helper()
+
+	for _, cell := range cells {
+		assert.Equal(t, cell, grid.WorldToCell(grid.CellToWorld(cell)))
+		assert.Equal(t, 0.0, grid.Distance(cell, cell))
+
+		seen := make(map[C]bool)
+		for _, n := range grid.Neighbours(cell) {
+			assert.Equal(t, 1.0, grid.Distance(cell, n))
+			assert.False(t, seen[n])
+			seen[n] = true
+		}
+	}
+}
+
+func TestGridTopologies(t *testing.T) {
+	t.Parallel()
+
+	var vectors []Vector2D[int64]
+	for x := int64(-3); x <= 3; x++ {
+		for y := int64(-3); y <= 3; y++ {
+			vectors = append(vectors, NewVector2D(x, y))
+		}
+	}
+	var triangles []Triangle
+	for _, v := range vectors {
+		triangles = append(triangles, NewTriangle(v.X, v.Y, 1-v.X-v.Y), NewTriangle(v.X, v.Y, 2-v.X-v.Y))
+	}
+
+	assertTopology[Hex[int64]](t, NewHexTopology(NewHexLayout(LayoutPointy, NewVector2D[float64](10, 10), NewVector2D[float64](3, 4), 1)),
+		(Hex[int64]{}).Spiral(3))
+	assertTopology[Vector2D[int64]](t, NewSquareTopology(NewSquareGrid(NewVector2D[float64](16, 16)), false), vectors)
+	assertTopology[Vector2D[int64]](t, NewSquareTopology(NewSquareGrid(NewVector2D[float64](16, 16)), true), vectors)
+	assertTopology[Vector2D[int64]](t, NewIsoTopology(NewIsoGrid(IsoDiamond, NewVector2D[float64](64, 32)), false), vectors)
+	assertTopology[Vector2D[int64]](t, NewIsoTopology(NewIsoGrid(IsoStaggered, NewVector2D[float64](64, 32)), true), vectors)
+	assertTopology[Triangle](t, NewTriangleTopology(unitTriangleLayout), triangles)
+}
+
+func TestGridAStar(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Hex", func(t *testing.T) {
+		t.Parallel()
+
+		costs := make(HexMap[float64])
+		for _, h := range (Hex[int64]{Q: 0, R: -4}).LineTo(Hex[int64]{Q: 0, R: 4}) {
+			costs[h] = -1
+		}
+		costs[Hex[int64]{Q: 2, R: 0}] = 3
+		cost := testCosts(6, costs)
+		grid := NewHexTopology(NewHexLayout(LayoutFlat, NewVector2D[float64](1, 1), NewVector2D[float64](0, 0), 1))
+		start, goal := Hex[int64]{Q: -3, R: 0}, Hex[int64]{Q: 3, R: 0}
+
+		path, pathCost, ok := GridAStar[Hex[int64]](grid, start, goal, cost, 1)
+		_, expected, _ := HexAStar(start, goal, cost, 1)
+		assert.True(t, ok)
+		assertPath(t, path, start, goal, cost, expected)
+		assert.Equal(t, expected, pathCost)
+	})
+
+	t.Run("Square", func(t *testing.T) {
+		t.Parallel()
+
+		grid := NewSquareTopology(NewSquareGrid(NewVector2D[float64](1, 1)), false)
+		cost := func(cell Vector2D[int64]) float64 {
+			if cell.X < 0 || cell.Y < 0 || cell.X > 9 || cell.Y > 9 || (cell.X == 5 && cell.Y < 9) {
+				return math.Inf(1)
+			}
+			return 1
+		}
+
+		path, pathCost, ok := GridAStar[Vector2D[int64]](grid, NewVector2D[int64](0, 0), NewVector2D[int64](9, 0), cost, 1)
+		assert.True(t, ok)
+		assert.Equal(t, 27.0, pathCost) // down 9, right 9 and up 9 around the wall
+		assert.Len(t, path, 28)
+
+		_, _, ok = GridAStar[Vector2D[int64]](grid, NewVector2D[int64](0, 0), NewVector2D[int64](5, 0), cost, 1)
+		assert.False(t, ok)
+	})
+
+	t.Run("Triangle", func(t *testing.T) {
+		t.Parallel()
+
+		grid := NewTriangleTopology(unitTriangleLayout)
+		start, goal := NewTriangle(1, 1, 0), NewTriangle(-3, 4, 0)
+		cost := func(cell Triangle) float64 {
+			if cell.Distance(start) > 20 {
+				return math.Inf(1)
+			}
+			return 1
+		}
+
+		path, pathCost, ok := GridAStar[Triangle](grid, start, goal, cost, 1)
+		assert.True(t, ok)
+		assert.Equal(t, start.Distance(goal), pathCost)
+		assert.Equal(t, goal, path[len(path)-1])
+	})
+}
+
+func TestGridFloodFill(t *testing.T) {
+	t.Parallel()
+
+	grid := NewSquareTopology(NewSquareGrid(NewVector2D[float64](1, 1)), false)
+	start := NewVector2D[int64](0, 0)
+	open := func(cell Vector2D[int64]) bool { return cell.X != 2 }
+
+	steps := GridFloodFill[Vector2D[int64]](grid, start, 3, open)
+	for cell, n := range steps {
+		assert.NotEqual(t, int64(2), cell.X)
+		assert.Equal(t, int(grid.Distance(start, cell)), n)
+		assert.LessOrEqual(t, n, 3)
+	}
+	assert.Equal(t, 3, steps[NewVector2D[int64](1, 2)])
+	assert.NotContains(t, steps, NewVector2D[int64](3, 0))
+	assert.Len(t, steps, 1+4+(8-1)+(12-3)) // the wall hides one cell at distance two and three at distance three
+
+	assert.Empty(t, GridFloodFill[Vector2D[int64]](grid, start, 3, func(Vector2D[int64]) bool { return false }))
+}
@@ -64,7 +64,7 @@ func (h Hex[T]) Clone() Hex[T] {
 	}
 }
 
-// Distance returns the distance between two hexes
+// Distance returns the number of steps between two hexes, (|dq| + |dr| + |ds|) / 2
 func (h Hex[T]) Distance(other Hex[T]) float64 {
 	// Convert to float64 for calculations
 	q1, r1 := float64(h.Q), float64(h.R)
@@ -75,7 +75,7 @@ func (h Hex[T]) Distance(other Hex[T]) float64 {
 	s2 := -q2 - r2
 
 	// Use manhattan distance formula for hex grids
-	return math.Abs(q1-q2) + math.Abs(r1-r2) + math.Abs(s1-s2)/2
+	return (math.Abs(q1-q2) + math.Abs(r1-r2) + math.Abs(s1-s2)) / 2
 }
 
 // directions represents the six directions in a hexagonal grid
@@ -85,6 +85,12 @@ var directions = []Hex[int64]{
 	{Q: 0, R: -1}, {Q: +1, R: -1},
 }
 
+// HexDirection returns the offset to the neighbour in one of the six directions,
+// indices outside 0 to 5 wrap around so -1 is direction 5
+func HexDirection(index int) Hex[int64] {
+	return directions[mod(index, len(directions))]
+}
+
 // Neighbours returns all adjacent hexes
 func (h Hex[T]) Neighbours() []Hex[T] {
 	result := make([]Hex[T], 6)
@@ -195,3 +201,18 @@ func (h Hex[T]) ToFloat() Hex[float64] {
 		R: float64(h.R),
 	}
 }
+
+// hexRound returns the hex containing a fractional hex by rounding its cube coordinates
+func hexRound(h Hex[float64]) Hex[int64] {
+	s := -h.Q - h.R
+	q, r, rs := math.Round(h.Q), math.Round(h.R), math.Round(s)
+	dq, dr, ds := math.Abs(q-h.Q), math.Abs(r-h.R), math.Abs(rs-s)
+
+	// Fix the component with the largest rounding error so q+r+s stays zero
+	if dq > dr && dq > ds {
+		q = -r - rs
+	} else if dr > ds {
+		r = -q - rs
+	}
+	return Hex[int64]{Q: int64(q), R: int64(r)}
+}
@@ -0,0 +1,210 @@
+package maths
+
+import (
+	"math"
+)
+
+// HexChunkShape is the shape of the chunks of a HexChunking
+type HexChunkShape int
+
+const (
+	// HexChunkParallelogram chunks cover Size x Size hexes along the Q and R axis
+	HexChunkParallelogram HexChunkShape = iota
+	// HexChunkHexagon chunks are hexagons of hexes with a radius of Size
+	HexChunkHexagon
+)
+
+// HexChunking splits the infinite hex plane into chunks of equal size. Chunks form
+// a hex lattice themselves, so chunk coordinates are hexes and the neighbours of a
+// chunk coordinate are the adjacent chunks.
+type HexChunking struct {
+	Shape HexChunkShape
+	Size  int64
+
+	// offsets are the hexes of the chunk at the origin in index order
+	offsets []Hex[int64]
+	// indices maps the offsets back to their index
+	indices HexMap[int]
+}
+
+// NewHexChunking creates a new chunking, size is the side length of parallelograms or the radius of hexagons
+func NewHexChunking(shape HexChunkShape, size int) *HexChunking {
+	c := &HexChunking{Shape: shape, Size: int64(size), indices: make(HexMap[int])}
+	if shape == HexChunkHexagon {
+		c.offsets = (Hex[int64]{}).Spiral(size)
+	} else {
+		for r := range c.Size {
+			for q := range c.Size {
+				c.offsets = append(c.offsets, Hex[int64]{Q: q, R: r})
+			}
+		}
+	}
+	for i, offset := range c.offsets {
+		c.indices[offset] = i
+	}
+	return c
+}
+
+// ChunkLen returns the number of hexes in a chunk
+func (c *HexChunking) ChunkLen() int {
+	return len(c.offsets)
+}
+
+// Chunk returns the coordinate of the chunk containing the hex
+func (c *HexChunking) Chunk(h Hex[int64]) Hex[int64] {
+	if c.Shape != HexChunkHexagon {
+		return Hex[int64]{Q: floorDiv(h.Q, c.Size), R: floorDiv(h.R, c.Size)}
+	}
+
+	// Solve h = i*A + j*B for the lattice vectors and search the closest chunk centers
+	a, b := c.basis()
+	det := float64(a.Q*b.R - b.Q*a.R)
+	i := (float64(h.Q)*float64(b.R) - float64(h.R)*float64(b.Q)) / det
+	j := (float64(h.R)*float64(a.Q) - float64(h.Q)*float64(a.R)) / det
+
+	base := Hex[int64]{Q: int64(math.Floor(i)), R: int64(math.Floor(j))}
+	for _, chunk := range base.Spiral(2) {
+		if h.Distance(c.origin(chunk)) <= float64(c.Size) {
+			return chunk
+		}
+	}
+	return base
+}
+
+// Local returns the chunk containing the hex and the index of the hex within the chunk
+func (c *HexChunking) Local(h Hex[int64]) (Hex[int64], int) {
+	chunk := c.Chunk(h)
+	return chunk, c.indices[h.Subtract(c.origin(chunk))]
+}
+
+// Hex returns the hex at the index of the chunk, the inverse of Local
+func (c *HexChunking) Hex(chunk Hex[int64], index int) Hex[int64] {
+	return c.origin(chunk).Add(c.offsets[index])
+}
+
+// ChunkHexes returns all hexes of the chunk in index order
+func (c *HexChunking) ChunkHexes(chunk Hex[int64]) []Hex[int64] {
+	origin := c.origin(chunk)
+	hexes := make([]Hex[int64], len(c.offsets))
+	for i, offset := range c.offsets {
+		hexes[i] = origin.Add(offset)
+	}
+	return hexes
+}
+
+// origin returns the hex at index zero of the chunk, the center of hexagon chunks
+func (c *HexChunking) origin(chunk Hex[int64]) Hex[int64] {
+	if c.Shape != HexChunkHexagon {
+		return chunk.Multiply(c.Size)
+	}
+	a, b := c.basis()
+	return a.Multiply(chunk.Q).Add(b.Multiply(chunk.R))
+}
+
+// basis returns the offsets between the centers of neighbouring hexagon chunks
+func (c *HexChunking) basis() (Hex[int64], Hex[int64]) {
+	return Hex[int64]{Q: 2*c.Size + 1, R: -c.Size}, Hex[int64]{Q: c.Size, R: c.Size + 1}
+}
+
+// HexChunkManager keeps the chunks around the camera loaded. Chunks are loaded
+// when they intersect the camera view or lie within Radius chunks of it and are
+// unloaded when they leave that area.
+type HexChunkManager struct {
+	Chunking *HexChunking
+	Grid     *HexGrid
+	Radius   int
+	OnLoad   func(chunk Hex[int64])
+	OnUnload func(chunk Hex[int64])
+
+	loaded HexMap[struct{}]
+}
+
+// NewHexChunkManager creates a new manager without loaded chunks, the callbacks may be nil
+func NewHexChunkManager(
+	chunking *HexChunking,
+	grid *HexGrid,
+	radius int,
+	onLoad, onUnload func(chunk Hex[int64]),
+) *HexChunkManager {
+	return &HexChunkManager{
+		Chunking: chunking,
+		Grid:     grid,
+		Radius:   radius,
+		OnLoad:   onLoad,
+		OnUnload: onUnload,
+		loaded:   make(HexMap[struct{}]),
+	}
+}
+
+// VisibleChunks returns the chunks intersecting the camera view grown by Radius chunks in a stable order
+func (m *HexChunkManager) VisibleChunks(camera Camera) []Hex[int64] {
+	// Grow the view by a hex, so chunks of hexes reaching into the view are included
+	size := m.Grid.Layout.Size.Multiply(m.Grid.Layout.Zoom)
+	view := cameraView(camera).Expand(math.Max(size.X, size.Y))
+
+	visible := make(HexMap[struct{}])
+	for _, h := range hexesInRect(m.Grid.Layout, view) {
+		chunk := m.Chunking.Chunk(h)
+		if visible.Has(chunk) {
+			continue
+		}
+		for _, near := range chunk.Spiral(m.Radius) {
+			visible[near] = struct{}{}
+		}
+	}
+	return visible.Hexes()
+}
+
+// Update loads the chunks which became visible and unloads the chunks which left
+// the view. Unloads are reported before loads, each in a stable order.
+func (m *HexChunkManager) Update(camera Camera) (loaded, unloaded []Hex[int64]) {
+	visible := m.VisibleChunks(camera)
+	wanted := NewHexMap(visible, struct{}{})
+
+	for _, chunk := range m.loaded.Hexes() {
+		if !wanted.Has(chunk) {
+			unloaded = append(unloaded, chunk)
+		}
+	}
+	for _, chunk := range visible {
+		if !m.loaded.Has(chunk) {
+			loaded = append(loaded, chunk)
+		}
+	}
+
+	for _, chunk := range unloaded {
+		delete(m.loaded, chunk)
+		if m.OnUnload != nil {
+			m.OnUnload(chunk)
+		}
+	}
+	for _, chunk := range loaded {
+		m.loaded[chunk] = struct{}{}
+		if m.OnLoad != nil {
+			m.OnLoad(chunk)
+		}
+	}
+	return loaded, unloaded
+}
+
+// UnloadAll unloads every loaded chunk and returns them
+func (m *HexChunkManager) UnloadAll() []Hex[int64] {
+	unloaded := m.loaded.Hexes()
+	for _, chunk := range unloaded {
+		delete(m.loaded, chunk)
+		if m.OnUnload != nil {
+			m.OnUnload(chunk)
+		}
+	}
+	return unloaded
+}
+
+// IsLoaded reports whether the chunk is loaded
+func (m *HexChunkManager) IsLoaded(chunk Hex[int64]) bool {
+	return m.loaded.Has(chunk)
+}
+
+// Loaded returns the loaded chunks in a stable order
+func (m *HexChunkManager) Loaded() []Hex[int64] {
+	return m.loaded.Hexes()
+}
@@ -0,0 +1,142 @@
+package maths
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHexChunking(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		shape  HexChunkShape
+		size   int
+		length int
+	}{
+		{"Parallelogram 1", HexChunkParallelogram, 1, 1},
+		{"Parallelogram 4", HexChunkParallelogram, 4, 16},
+		{"Hexagon 0", HexChunkHexagon, 0, 1},
+		{"Hexagon 1", HexChunkHexagon, 1, 7},
+		{"Hexagon 3", HexChunkHexagon, 3, 37},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			chunking := NewHexChunking(tt.shape, tt.size)
+			assert.Equal(t, tt.length, chunking.ChunkLen())
+
+			// Every hex belongs to exactly one chunk and round trips through its local index
+			counts := make(map[Hex[int64]]int)
+			for _, h := range (Hex[int64]{Q: 3, R: -5}).Spiral(20) {
+				chunk, index := chunking.Local(h)
+				assert.Equal(t, chunk, chunking.Chunk(h))
+				assert.GreaterOrEqual(t, index, 0)
+				assert.Less(t, index, chunking.ChunkLen())
+				assert.Equal(t, h, chunking.Hex(chunk, index))
+				counts[chunk]++
+			}
+
+			// The hexes of a chunk are listed in index order and belong to the chunk
+			for _, chunk := range (Hex[int64]{Q: -1, R: 2}).Spiral(2) {
+				hexes := chunking.ChunkHexes(chunk)
+				assert.Len(t, hexes, chunking.ChunkLen())
+				for i, h := range hexes {
+					c, index := chunking.Local(h)
+					assert.Equal(t, chunk, c)
+					assert.Equal(t, i, index)
+				}
+			}
+
+			// Chunks fully inside the sampled area hold all their hexes
+			center := chunking.Chunk(Hex[int64]{Q: 3, R: -5})
+			assert.Equal(t, chunking.ChunkLen(), counts[center])
+		})
+	}
+}
+
+func TestHexChunkingHexagonNeighbours(t *testing.T) {
+	t.Parallel()
+
+	// Neighbouring chunk coordinates are chunks sharing an edge
+	chunking := NewHexChunking(HexChunkHexagon, 2)
+	chunk := Hex[int64]{Q: 1, R: -1}
+	hexes := NewHexMap(chunking.ChunkHexes(chunk), struct{}{})
+
+	for _, neighbour := range chunk.Neighbours() {
+		touching := false
+		for _, h := range chunking.ChunkHexes(neighbour) {
+			for _, n := range h.Neighbours() {
+				touching = touching || hexes.Has(n)
+			}
+		}
+		assert.True(t, touching, neighbour)
+	}
+}
+
+func TestHexChunkManager(t *testing.T) {
+	t.Parallel()
+
+	grid := NewHexGrid(LayoutPointy, NewVector2D[float64](10, 10))
+	chunking := NewHexChunking(HexChunkParallelogram, 4)
+
+	var events []string
+	manager := NewHexChunkManager(chunking, grid, 0,
+		func(chunk Hex[int64]) { events = append(events, "load "+chunk.String()) },
+		func(chunk Hex[int64]) { events = append(events, "unload "+chunk.String()) },
+	)
+	camera := testCamera{position: NewVector2D[float64](0, 0), zoom: 1, size: NewVector2D[float64](40, 40)}
+
+	loaded, unloaded := manager.Update(camera)
+	assert.Empty(t, unloaded)
+	assert.Equal(t, manager.VisibleChunks(camera), loaded)
+	assert.Equal(t, loaded, manager.Loaded())
+	assert.Len(t, events, len(loaded))
+	assert.True(t, manager.IsLoaded(Hex[int64]{}))
+
+	// Every hex overlapping the view is in a loaded chunk
+	for _, h := range grid.GetVisibleHexes(camera) {
+		assert.True(t, manager.IsLoaded(chunking.Chunk(h.ToInt())), h)
+	}
+
+	// Nothing changes while the camera stands still
+	events = nil
+	loaded, unloaded = manager.Update(camera)
+	assert.Empty(t, loaded)
+	assert.Empty(t, unloaded)
+	assert.Empty(t, events)
+
+	// Moving far away swaps all chunks, unloading first
+	before := manager.Loaded()
+	camera.position = NewVector2D[float64](1000, 0)
+	loaded, unloaded = manager.Update(camera)
+	assert.Equal(t, before, unloaded)
+	assert.NotEmpty(t, loaded)
+	assert.Equal(t, "unload "+before[0].String(), events[0])
+	assert.Equal(t, "load "+loaded[len(loaded)-1].String(), events[len(events)-1])
+	assert.False(t, manager.IsLoaded(Hex[int64]{}))
+
+	events = nil
+	assert.Equal(t, loaded, manager.UnloadAll())
+	assert.Len(t, events, len(loaded))
+	assert.Empty(t, manager.Loaded())
+}
+
+func TestHexChunkManagerRadius(t *testing.T) {
+	t.Parallel()
+
+	grid := NewHexGrid(LayoutFlat, NewVector2D[float64](10, 10))
+	chunking := NewHexChunking(HexChunkHexagon, 3)
+	camera := testCamera{position: NewVector2D[float64](0, 0), zoom: 1, size: NewVector2D[float64](10, 10)}
+
+	// A small view sees a single chunk, the radius adds the rings around it
+	assert.Equal(t, []Hex[int64]{{}}, NewHexChunkManager(chunking, grid, 0, nil, nil).VisibleChunks(camera))
+	manager := NewHexChunkManager(chunking, grid, 1, nil, nil)
+	assert.ElementsMatch(t, (Hex[int64]{}).Spiral(1), manager.VisibleChunks(camera))
+
+	loaded, _ := manager.Update(camera)
+	assert.Len(t, loaded, 7)
+}
@@ -0,0 +1,158 @@
+package maths
+
+import (
+	"fmt"
+)
+
+// HexEdge is the edge between a hex and its neighbour in Direction as used by
+// HexDirection. Every edge has a single representation with a direction of 0, 1 or 2,
+// see NewHexEdge.
+type HexEdge struct {
+	Hex       Hex[int64]
+	Direction int
+}
+
+// NewHexEdge returns the edge between the hex and its neighbour in the direction, any direction is allowed
+func NewHexEdge(h Hex[int64], direction int) HexEdge {
+	direction = mod(direction, 6)
+	if direction >= 3 {
+		return HexEdge{Hex: h.Add(directions[direction]), Direction: direction - 3}
+	}
+	return HexEdge{Hex: h, Direction: direction}
+}
+
+// HexEdges returns the six edges of the hex in the order of the directions
+func HexEdges(h Hex[int64]) []HexEdge {
+	edges := make([]HexEdge, 6)
+	for d := range edges {
+		edges[d] = NewHexEdge(h, d)
+	}
+	return edges
+}
+
+// String returns the edge as `q:r/direction`
+func (e HexEdge) String() string {
+	return fmt.Sprintf("%v/%d", e.Hex, e.Direction)
+}
+
+// Hexes returns the two hexes separated by the edge
+func (e HexEdge) Hexes() []Hex[int64] {
+	return []Hex[int64]{e.Hex, e.Hex.Add(directions[e.Direction])}
+}
+
+// Vertices returns the two end points of the edge
+func (e HexEdge) Vertices() []HexVertex {
+	return []HexVertex{NewHexVertex(e.Hex, e.Direction-1), NewHexVertex(e.Hex, e.Direction)}
+}
+
+// Neighbours returns the four edges sharing an end point with the edge
+func (e HexEdge) Neighbours() []HexEdge {
+	var edges []HexEdge
+	for _, v := range e.Vertices() {
+		for _, other := range v.Edges() {
+			if other != e {
+				edges = append(edges, other)
+			}
+		}
+	}
+	return edges
+}
+
+// HexVertex is the corner of a hex between the neighbours in the directions Corner
+// and Corner + 1. Every vertex is shared by three hexes and has a single
+// representation with a corner of 0 or 1, see NewHexVertex.
+type HexVertex struct {
+	Hex    Hex[int64]
+	Corner int
+}
+
+// NewHexVertex returns the corner of the hex between the directions corner and corner + 1, any corner is allowed
+func NewHexVertex(h Hex[int64], corner int) HexVertex {
+	corner = mod(corner, 6)
+
+	// The same vertex is corner+2 of the neighbour in direction corner
+	for corner >= 2 {
+		h = h.Add(directions[(corner+1)%6])
+		corner -= 2
+	}
+	return HexVertex{Hex: h, Corner: corner}
+}
+
+// HexVertices returns the six corners of the hex, corner i lies between the directions i and i + 1
+func HexVertices(h Hex[int64]) []HexVertex {
+	vertices := make([]HexVertex, 6)
+	for i := range vertices {
+		vertices[i] = NewHexVertex(h, i)
+	}
+	return vertices
+}
+
+// String returns the vertex as `q:r^corner`
+func (v HexVertex) String() string {
+	return fmt.Sprintf("%v^%d", v.Hex, v.Corner)
+}
+
+// Hexes returns the three hexes meeting at the vertex
+func (v HexVertex) Hexes() []Hex[int64] {
+	return []Hex[int64]{v.Hex, v.Hex.Add(directions[v.Corner]), v.Hex.Add(directions[v.Corner+1])}
+}
+
+// Edges returns the three edges meeting at the vertex
+func (v HexVertex) Edges() []HexEdge {
+	return []HexEdge{
+		NewHexEdge(v.Hex, v.Corner),
+		NewHexEdge(v.Hex, v.Corner+1),
+		NewHexEdge(v.Hex.Add(directions[v.Corner]), v.Corner+2),
+	}
+}
+
+// Neighbours returns the three vertices connected to the vertex by an edge
+func (v HexVertex) Neighbours() []HexVertex {
+	vertices := make([]HexVertex, 0, 3)
+	for _, e := range v.Edges() {
+		for _, other := range e.Vertices() {
+			if other != v {
+				vertices = append(vertices, other)
+			}
+		}
+	}
+	return vertices
+}
+
+// HexVertexToVector2D returns the world position of the vertex
+func (layout HexLayout) HexVertexToVector2D(v HexVertex) Vector2D[float64] {
+	// The vertex is the corner of the hex closest to the center of the three hexes
+	var center Vector2D[float64]
+	for _, h := range v.Hexes() {
+		center = center.Add(layout.HexToVector2D(h.ToFloat()))
+	}
+	center = center.Divide(3)
+
+	corners := layout.HexCorners(v.Hex.ToFloat())
+	closest := corners[0]
+	for _, corner := range corners[1:] {
+		if corner.Distance(center) < closest.Distance(center) {
+			closest = corner
+		}
+	}
+	return closest
+}
+
+// HexEdgeToVector2D returns the world position of the center of the edge
+func (layout HexLayout) HexEdgeToVector2D(e HexEdge) Vector2D[float64] {
+	a, b := layout.HexEdgeEndpoints(e)
+	return a.Add(b).Divide(2)
+}
+
+// HexEdgeEndpoints returns the world positions of the two vertices of the edge
+func (layout HexLayout) HexEdgeEndpoints(e HexEdge) (Vector2D[float64], Vector2D[float64]) {
+	vertices := e.Vertices()
+	return layout.HexVertexToVector2D(vertices[0]), layout.HexVertexToVector2D(vertices[1])
+}
+
+// mod returns the non-negative remainder of a divided by b
+func mod[T interface {
+	int | int64
+}](a, b T) T {
+	return ((a % b) + b) % b
+}
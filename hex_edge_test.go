@@ -0,0 +1,122 @@
+package maths
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHexEdgeCanonical(t *testing.T) {
+	t.Parallel()
+
+	for _, h := range (Hex[int64]{Q: 2, R: -1}).Spiral(2) {
+		for d := -6; d < 12; d++ {
+			edge := NewHexEdge(h, d)
+			assert.GreaterOrEqual(t, edge.Direction, 0)
+			assert.Less(t, edge.Direction, 3)
+
+			// The neighbour sees the same edge in the opposite direction
+			assert.Equal(t, edge, NewHexEdge(h.Add(directions[mod(d, 6)]), d+3))
+			assert.Contains(t, edge.Hexes(), h)
+			assert.Contains(t, edge.Hexes(), h.Add(directions[mod(d, 6)]))
+		}
+	}
+
+	// Every hex owns three of its edges
+	edges := make(map[HexEdge]bool)
+	for _, h := range (Hex[int64]{}).Spiral(5) {
+		for _, e := range HexEdges(h) {
+			edges[e] = true
+		}
+	}
+	assert.Len(t, edges, 3*91+6*5+3) // 91 hexes plus the outer edges of the ring
+}
+
+func TestHexVertexCanonical(t *testing.T) {
+	t.Parallel()
+
+	for _, h := range (Hex[int64]{Q: -1, R: 3}).Spiral(2) {
+		for corner := -6; corner < 12; corner++ {
+			v := NewHexVertex(h, corner)
+			assert.GreaterOrEqual(t, v.Corner, 0)
+			assert.Less(t, v.Corner, 2)
+
+			// The three hexes around the vertex see the same vertex
+			c := mod(corner, 6)
+			assert.Equal(t, v, NewHexVertex(h.Add(directions[c]), c+2))
+			assert.Equal(t, v, NewHexVertex(h.Add(directions[(c+1)%6]), c+4))
+			assert.ElementsMatch(t, []Hex[int64]{h, h.Add(directions[c]), h.Add(directions[(c+1)%6])}, v.Hexes())
+		}
+	}
+
+	assert.Equal(t, "1:2^1", NewHexVertex(NewHex[int64](1, 2), 1).String())
+	assert.Equal(t, "1:2/0", NewHexEdge(NewHex[int64](1, 2), 0).String())
+}
+
+func TestHexEdgeAdjacency(t *testing.T) {
+	t.Parallel()
+
+	h := NewHex[int64](3, -2)
+	vertices := HexVertices(h)
+	for d, e := range HexEdges(h) {
+		// Edge d lies between the corners d-1 and d
+		assert.ElementsMatch(t, []HexVertex{vertices[mod(d-1, 6)], vertices[d]}, e.Vertices())
+
+		neighbours := e.Neighbours()
+		assert.Len(t, neighbours, 4)
+		for _, n := range neighbours {
+			shared := 0
+			for _, v := range n.Vertices() {
+				if v == e.Vertices()[0] || v == e.Vertices()[1] {
+					shared++
+				}
+			}
+			assert.Equal(t, 1, shared)
+		}
+	}
+
+	for _, v := range vertices {
+		edges := v.Edges()
+		assert.Len(t, edges, 3)
+		for _, e := range edges {
+			assert.Contains(t, e.Vertices(), v)
+		}
+
+		neighbours := v.Neighbours()
+		assert.Len(t, neighbours, 3)
+		for _, n := range neighbours {
+			assert.NotEqual(t, v, n)
+			assert.NotEqual(t, v.Corner, n.Corner)
+		}
+	}
+}
+
+func TestHexEdgeWorld(t *testing.T) {
+	t.Parallel()
+
+	for _, orientation := range []HexOrientation{LayoutPointy, LayoutFlat} {
+		layout := NewHexLayout(orientation, NewVector2D[float64](10, 12), NewVector2D[float64](5, -3), 1)
+
+		for _, h := range (Hex[int64]{}).Spiral(2) {
+			for _, v := range HexVertices(h) {
+				// The vertex is a corner of all three hexes
+				position := layout.HexVertexToVector2D(v)
+				for _, other := range v.Hexes() {
+					found := false
+					for _, corner := range layout.HexCorners(other.ToFloat()) {
+						found = found || corner.Distance(position) < 1e-9
+					}
+					assert.True(t, found)
+				}
+			}
+
+			for d, e := range HexEdges(h) {
+				// The edge center lies halfway between the two hex centers
+				centers := e.Hexes()
+				midpoint := layout.HexToVector2D(centers[0].ToFloat()).Add(layout.HexToVector2D(centers[1].ToFloat())).Divide(2)
+				assert.InDelta(t, midpoint.X, layout.HexEdgeToVector2D(e).X, 1e-9, "edge %d", d)
+				assert.InDelta(t, midpoint.Y, layout.HexEdgeToVector2D(e).Y, 1e-9, "edge %d", d)
+			}
+		}
+	}
+}
@@ -38,6 +38,13 @@ var (
 	}
 )
 
+// isFlat reports whether the orientation has flat tops. The entries are compared
+// by size rather than the struct for equality, so computed or decoded orientations
+// with rounding errors are recognized as well.
+func (o HexOrientation) isFlat() bool {
+	return math.Abs(o.F1) < math.Abs(o.F2)
+}
+
 // NewHexLayout creates a new layout with specified parameters
 func NewHexLayout(
 	orientation HexOrientation,
@@ -114,15 +121,7 @@ func (grid *HexGrid) WorldToScreen(
 	worldPos Vector2D[float64],
 	camera Camera,
 ) Vector2D[float64] {
-	cameraPos := camera.GetPosition()
-	cameraZoom := camera.GetZoom()
-	cameraSize := camera.GetSize()
-
-	// Convert to screen space considering camera position and zoom
-	screenX := float64(worldPos.X-cameraPos.X)*cameraZoom + float64(cameraSize.X)/2
-	screenY := float64(worldPos.Y-cameraPos.Y)*cameraZoom + float64(cameraSize.Y)/2
-
-	return NewVector2D(screenX, screenY)
+	return worldToScreen(worldPos, camera)
 }
 
 // ScreenToWorld converts screen coordinates to world coordinates using camera data
@@ -130,15 +129,7 @@ func (grid *HexGrid) ScreenToWorld(
 	screenPos Vector2D[float64],
 	camera Camera,
 ) Vector2D[float64] {
-	cameraPos := camera.GetPosition()
-	cameraZoom := camera.GetZoom()
-	cameraSize := camera.GetSize()
-
-	// Convert back to world space
-	worldX := float64(screenPos.X-cameraSize.X/2)/cameraZoom + float64(cameraPos.X)
-	worldY := float64(screenPos.Y-cameraSize.Y/2)/cameraZoom + float64(cameraPos.Y)
-
-	return NewVector2D(worldX, worldY)
+	return screenToWorld(screenPos, camera)
 }
 
 // HexToScreen converts hex coordinates to screen coordinates considering camera
@@ -270,3 +261,64 @@ func (grid *HexGrid) HexImageToScreen(
 
 	return centerPosition, imageScaleFactor
 }
+
+// worldToScreen converts world coordinates to screen coordinates of a camera centered on its position
+func worldToScreen(worldPos Vector2D[float64], camera Camera) Vector2D[float64] {
+	cameraPos := camera.GetPosition()
+	cameraZoom := camera.GetZoom()
+	cameraSize := camera.GetSize()
+
+	// Convert to screen space considering camera position and zoom
+	screenX := (worldPos.X-cameraPos.X)*cameraZoom + cameraSize.X/2
+	screenY := (worldPos.Y-cameraPos.Y)*cameraZoom + cameraSize.Y/2
+
+	return NewVector2D(screenX, screenY)
+}
+
+// screenToWorld converts screen coordinates of a camera centered on its position to world coordinates
+func screenToWorld(screenPos Vector2D[float64], camera Camera) Vector2D[float64] {
+	cameraPos := camera.GetPosition()
+	cameraZoom := camera.GetZoom()
+	cameraSize := camera.GetSize()
+
+	// Convert back to world space
+	worldX := (screenPos.X-cameraSize.X/2)/cameraZoom + cameraPos.X
+	worldY := (screenPos.Y-cameraSize.Y/2)/cameraZoom + cameraPos.Y
+
+	return NewVector2D(worldX, worldY)
+}
+
+// cameraView returns the area of the world visible in the camera
+func cameraView(camera Camera) Rect {
+	size := camera.GetSize()
+	return NewRect(screenToWorld(NewVector2D[float64](0, 0), camera), screenToWorld(size, camera))
+}
+
+// imageScale returns the uniform scale factor fitting an image into a cell of the size
+func imageScale(cellSize, imageDefaultSize Vector2D[float64]) Vector2D[float64] {
+	scaleFactor := math.Min(cellSize.X/imageDefaultSize.X, cellSize.Y/imageDefaultSize.Y)
+	return Vector2D[float64]{X: scaleFactor, Y: scaleFactor}
+}
+
+// hexesInRect returns the hexes of the layout with their center inside the world rect row by row
+func hexesInRect(layout HexLayout, rect Rect) []Hex[int64] {
+	// Range of hexes covering the rect
+	minQ, minR := int64(math.MaxInt64), int64(math.MaxInt64)
+	maxQ, maxR := int64(math.MinInt64), int64(math.MinInt64)
+	for _, corner := range []Vector2D[float64]{rect.Min, rect.Max, {X: rect.Min.X, Y: rect.Max.Y}, {X: rect.Max.X, Y: rect.Min.Y}} {
+		h := hexRound(layout.Vector2DToHex(corner))
+		minQ, maxQ = min(minQ, h.Q), max(maxQ, h.Q)
+		minR, maxR = min(minR, h.R), max(maxR, h.R)
+	}
+
+	var hexes []Hex[int64]
+	for r := minR - 1; r <= maxR+1; r++ {
+		for q := minQ - 1; q <= maxQ+1; q++ {
+			h := Hex[int64]{Q: q, R: r}
+			if rect.Contains(layout.HexToVector2D(h.ToFloat())) {
+				hexes = append(hexes, h)
+			}
+		}
+	}
+	return hexes
+}
@@ -0,0 +1,39 @@
+package maths
+
+import (
+	"cmp"
+	"slices"
+)
+
+// HexMap stores a value for every hex of a region
+type HexMap[V any] map[Hex[int64]]V
+
+// NewHexMap creates a new HexMap with the same value for all hexes
+func NewHexMap[V any](hexes []Hex[int64], value V) HexMap[V] {
+	m := make(HexMap[V], len(hexes))
+	for _, h := range hexes {
+		m[h] = value
+	}
+	return m
+}
+
+// Has reports whether the hex is part of the map
+func (m HexMap[V]) Has(h Hex[int64]) bool {
+	_, ok := m[h]
+	return ok
+}
+
+// Hexes returns all hexes of the map sorted by R and then by Q
+func (m HexMap[V]) Hexes() []Hex[int64] {
+	hexes := make([]Hex[int64], 0, len(m))
+	for h := range m {
+		hexes = append(hexes, h)
+	}
+	slices.SortFunc(hexes, compareHex)
+	return hexes
+}
+
+// compareHex orders hexes by R and then by Q
+func compareHex(a, b Hex[int64]) int {
+	return cmp.Or(cmp.Compare(a.R, b.R), cmp.Compare(a.Q, b.Q))
+}
@@ -0,0 +1,21 @@
+package maths
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHexMap(t *testing.T) {
+	t.Parallel()
+
+	hexes := []Hex[int64]{{Q: 1, R: 0}, {Q: 0, R: 1}, {Q: -1, R: 1}, {Q: 0, R: 0}, {Q: 2, R: -1}}
+	m := NewHexMap(hexes, 7)
+
+	assert.Len(t, m, len(hexes))
+	assert.True(t, m.Has(Hex[int64]{Q: -1, R: 1}))
+	assert.False(t, m.Has(Hex[int64]{Q: 5, R: 5}))
+	assert.Equal(t, 7, m[Hex[int64]{Q: 0, R: 0}])
+	assert.Equal(t, []Hex[int64]{{Q: 2, R: -1}, {Q: 0, R: 0}, {Q: 1, R: 0}, {Q: -1, R: 1}, {Q: 0, R: 1}}, m.Hexes())
+	assert.Empty(t, HexMap[int]{}.Hexes())
+}
@@ -394,3 +394,91 @@ func TestHexSpiralRing(t *testing.T) {
 		})
 	}
 }
+
+func TestHexDistance(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		hex1     Hex[int64]
+		hex2     Hex[int64]
+		expected float64
+	}{
+		{Hex[int64]{Q: 0, R: 0}, Hex[int64]{Q: 0, R: 0}, 0},
+		{Hex[int64]{Q: 0, R: 0}, Hex[int64]{Q: 1, R: 0}, 1},
+		{Hex[int64]{Q: 0, R: 0}, Hex[int64]{Q: 1, R: -1}, 1},
+		{Hex[int64]{Q: 0, R: 0}, Hex[int64]{Q: 2, R: 1}, 3},
+		{Hex[int64]{Q: -2, R: 3}, Hex[int64]{Q: 1, R: -1}, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v to %v", tt.hex1, tt.hex2), func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, tt.hex1.Distance(tt.hex2))
+			assert.Equal(t, tt.expected, tt.hex2.Distance(tt.hex1))
+		})
+	}
+}
+
+func TestHexDistanceSteps(t *testing.T) {
+	t.Parallel()
+
+	// The distance is the number of neighbour steps found by a breadth first search
+	origin := Hex[int64]{Q: 2, R: -1}
+	steps := map[Hex[int64]]int{origin: 0}
+	frontier := []Hex[int64]{origin}
+	for step := 1; step <= 5; step++ {
+		var next []Hex[int64]
+		for _, h := range frontier {
+			for _, n := range h.Neighbours() {
+				if _, ok := steps[n]; !ok {
+					steps[n] = step
+					next = append(next, n)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	for h, step := range steps {
+		assert.Equal(t, float64(step), origin.Distance(h), h)
+		assert.Equal(t, float64(step), h.Distance(origin), h)
+	}
+	assert.Len(t, steps, len(origin.Spiral(5)))
+}
+
+func TestHexDirection(t *testing.T) {
+	t.Parallel()
+
+	for i := range 6 {
+		assert.Equal(t, directions[i], HexDirection(i))
+		assert.Equal(t, 1.0, HexDirection(i).Distance(Hex[int64]{}))
+	}
+	assert.Equal(t, HexDirection(0), HexDirection(6))
+	assert.Equal(t, HexDirection(5), HexDirection(-1))
+	assert.Equal(t, HexDirection(3), HexDirection(-9))
+	assert.Equal(t, HexDirection(2), HexDirection(14))
+}
+
+func TestHexRound(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		hex      Hex[float64]
+		expected Hex[int64]
+	}{
+		{Hex[float64]{Q: 0.1, R: -0.2}, Hex[int64]{Q: 0, R: 0}},
+		{Hex[float64]{Q: 0.6, R: 0.6}, Hex[int64]{Q: 1, R: 0}},
+		{Hex[float64]{Q: 0.4, R: 0.4}, Hex[int64]{Q: 0, R: 1}},
+		{Hex[float64]{Q: -1.3, R: 2.6}, Hex[int64]{Q: -1, R: 2}},
+		{Hex[float64]{Q: 2.7, R: -1.4}, Hex[int64]{Q: 3, R: -2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v", tt.hex), func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, hexRound(tt.hex))
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package maths
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrInvalidHexWrap is returned when a map cannot wrap with the given size
+var ErrInvalidHexWrap = errors.New("maths: invalid hex wrap")
+
+// HexWrapMode is the way a hex map wraps around
+type HexWrapMode int
+
+const (
+	// HexWrapCylinder maps wrap east-west, the top and bottom rows are the border of the map
+	HexWrapCylinder HexWrapMode = iota
+	// HexWrapTorus maps wrap east-west and north-south
+	HexWrapTorus
+)
+
+// HexWrap is a rectangular hex map of Width columns and Height rows which wraps
+// around. Rows and columns are offset coordinates: odd rows are shifted right for
+// pointy layouts and odd columns are shifted down for flat layouts. Every hex has
+// a canonical representation inside the rectangle. HexWrap implements Grid, so
+// GridAStar finds paths across the seam.
+type HexWrap struct {
+	Layout        HexLayout
+	Width, Height int64
+	Mode          HexWrapMode
+
+	// period moves a hex once around the map east-west, periodY north-south
+	period, periodY Hex[int64]
+}
+
+// HexGhost is a hex to draw at Image, the unwrapped position next to the camera.
+// Hex and Image differ for ghosts repeating the map beyond the seam.
+type HexGhost struct {
+	Hex   Hex[int64]
+	Image Hex[int64]
+}
+
+// NewHexWrap creates a new wrapping map. Flat layouts need an even width and
+// pointy tori an even height, so the offset rows line up across the seam.
+func NewHexWrap(layout HexLayout, width, height int64, mode HexWrapMode) (*HexWrap, error) {
+	flat := layout.Orientation.isFlat()
+	if width <= 0 || height <= 0 || (flat && width%2 != 0) || (!flat && mode == HexWrapTorus && height%2 != 0) {
+		return nil, ErrInvalidHexWrap
+	}
+
+	wrap := &HexWrap{Layout: layout, Width: width, Height: height, Mode: mode}
+	if flat {
+		wrap.period = Hex[int64]{Q: width, R: -width / 2}
+		wrap.periodY = Hex[int64]{Q: 0, R: height}
+	} else {
+		wrap.period = Hex[int64]{Q: width, R: 0}
+		wrap.periodY = Hex[int64]{Q: -height / 2, R: height}
+	}
+	return wrap, nil
+}
+
+// Canonical returns the representation of the hex inside the map, false for hexes beyond the top or bottom of a cylinder
+func (w *HexWrap) Canonical(h Hex[int64]) (Hex[int64], bool) {
+	col, row := w.toOffset(h)
+	col = mod(col, w.Width)
+	if w.Mode == HexWrapTorus {
+		row = mod(row, w.Height)
+	}
+	return w.fromOffset(col, row), row >= 0 && row < w.Height
+}
+
+// Contains reports whether the hex or one of its images lies on the map
+func (w *HexWrap) Contains(h Hex[int64]) bool {
+	_, ok := w.Canonical(h)
+	return ok
+}
+
+// Hexes returns all canonical hexes of the map row by row
+func (w *HexWrap) Hexes() []Hex[int64] {
+	hexes := make([]Hex[int64], 0, w.Width*w.Height)
+	for row := int64(0); row < w.Height; row++ {
+		for col := int64(0); col < w.Width; col++ {
+			hexes = append(hexes, w.fromOffset(col, row))
+		}
+	}
+	return hexes
+}
+
+// Neighbours returns the canonical neighbours of the hex in the order of the
+// directions, neighbours beyond the top or bottom of a cylinder are left out
+func (w *HexWrap) Neighbours(h Hex[int64]) []Hex[int64] {
+	neighbours := make([]Hex[int64], 0, 6)
+	for _, n := range h.Neighbours() {
+		if c, ok := w.Canonical(n); ok {
+			neighbours = append(neighbours, c)
+		}
+	}
+	return neighbours
+}
+
+// Distance returns the hex distance the short way around the map
+func (w *HexWrap) Distance(a, b Hex[int64]) float64 {
+	a, _ = w.Canonical(a)
+	return a.Distance(w.nearestImage(a, b))
+}
+
+// LineTo returns the canonical hexes on the shortest line from a to b, which may cross the seam
+func (w *HexWrap) LineTo(a, b Hex[int64]) []Hex[int64] {
+	a, _ = w.Canonical(a)
+	target := w.nearestImage(a, b)
+
+	n := int(a.Distance(target))
+	line := make([]Hex[int64], n+1)
+	for i := range line {
+		t := 0.0
+		if n > 0 {
+			t = float64(i) / float64(n)
+		}
+
+		// Nudge the line so it never runs exactly between two hexes
+		p := Hex[float64]{
+			Q: float64(a.Q) + (float64(target.Q)-float64(a.Q))*t + 1e-6,
+			R: float64(a.R) + (float64(target.R)-float64(a.R))*t + 1e-6,
+		}
+		line[i], _ = w.Canonical(hexRound(p))
+	}
+	return line
+}
+
+// FindPath finds the cheapest path across the seam with GridAStar, the cost function gets canonical hexes
+func (w *HexWrap) FindPath(start, goal Hex[int64], cost HexCostFunc, minCost float64) ([]Hex[int64], float64, bool) {
+	start, _ = w.Canonical(start)
+	goal, _ = w.Canonical(goal)
+	return GridAStar[Hex[int64]](w, start, goal, cost, minCost)
+}
+
+// CellToWorld returns the world position of the canonical hex
+func (w *HexWrap) CellToWorld(h Hex[int64]) Vector2D[float64] {
+	h, _ = w.Canonical(h)
+	return w.Layout.HexToVector2D(h.ToFloat())
+}
+
+// WorldToCell returns the canonical hex at the world position
+func (w *HexWrap) WorldToCell(p Vector2D[float64]) Hex[int64] {
+	h, _ := w.Canonical(hexRound(w.Layout.Vector2DToHex(p)))
+	return h
+}
+
+// HexToScreen returns the screen positions of all images of the hex overlapping the camera view
+func (w *HexWrap) HexToScreen(h Hex[int64], camera Camera) []Vector2D[float64] {
+	h, ok := w.Canonical(h)
+	if !ok {
+		return nil
+	}
+
+	view := w.paddedView(camera)
+	repeatX, repeatY := w.repeats(view)
+
+	// Start with the image closest to the camera, the periods are horizontal and vertical
+	origin := w.Layout.HexToVector2D(Hex[float64]{})
+	offset := view.Center().Subtract(w.Layout.HexToVector2D(h.ToFloat()))
+	baseX := int64(math.Round(offset.X / (w.Layout.HexToVector2D(w.period.ToFloat()).X - origin.X)))
+	baseY := int64(0)
+	if w.Mode == HexWrapTorus {
+		baseY = int64(math.Round(offset.Y / (w.Layout.HexToVector2D(w.periodY.ToFloat()).Y - origin.Y)))
+	}
+
+	var positions []Vector2D[float64]
+	for m := baseY - repeatY; m <= baseY+repeatY; m++ {
+		for k := baseX - repeatX; k <= baseX+repeatX; k++ {
+			image := h.Add(w.period.Multiply(k)).Add(w.periodY.Multiply(m))
+			position := w.Layout.HexToVector2D(image.ToFloat())
+			if view.Contains(position) {
+				positions = append(positions, worldToScreen(position, camera))
+			}
+		}
+	}
+	return positions
+}
+
+// ScreenToHex returns the canonical hex under the screen position
+func (w *HexWrap) ScreenToHex(screenPos Vector2D[float64], camera Camera) Hex[int64] {
+	return w.WorldToCell(screenToWorld(screenPos, camera))
+}
+
+// GetVisibleHexes returns the hexes overlapping the camera view. Hexes near the
+// seam appear several times when the view shows the map more than once.
+func (w *HexWrap) GetVisibleHexes(camera Camera) []HexGhost {
+	var ghosts []HexGhost
+	for _, image := range hexesInRect(w.Layout, w.paddedView(camera)) {
+		if h, ok := w.Canonical(image); ok {
+			ghosts = append(ghosts, HexGhost{Hex: h, Image: image})
+		}
+	}
+	return ghosts
+}
+
+// nearestImage returns the image of b closest to a
+func (w *HexWrap) nearestImage(a, b Hex[int64]) Hex[int64] {
+	b, _ = w.Canonical(b)
+
+	repeatY := int64(0)
+	if w.Mode == HexWrapTorus {
+		repeatY = 1
+	}
+
+	best := b
+	for m := -repeatY; m <= repeatY; m++ {
+		for k := int64(-1); k <= 1; k++ {
+			image := b.Add(w.period.Multiply(k)).Add(w.periodY.Multiply(m))
+			if a.Distance(image) < a.Distance(best) {
+				best = image
+			}
+		}
+	}
+	return best
+}
+
+// paddedView returns the camera view grown by a hex, so hexes reaching into the view are included
+func (w *HexWrap) paddedView(camera Camera) Rect {
+	size := w.Layout.Size.Multiply(w.Layout.Zoom)
+	return cameraView(camera).Expand(math.Max(size.X, size.Y))
+}
+
+// repeats returns how often the map repeats east-west and north-south within the view
+func (w *HexWrap) repeats(view Rect) (int64, int64) {
+	origin := w.Layout.HexToVector2D(Hex[float64]{})
+	width := w.Layout.HexToVector2D(w.period.ToFloat()).Distance(origin)
+	repeatX := int64(math.Ceil(view.Width()/width)) + 1
+
+	repeatY := int64(0)
+	if w.Mode == HexWrapTorus {
+		height := w.Layout.HexToVector2D(w.periodY.ToFloat()).Distance(origin)
+		repeatY = int64(math.Ceil(view.Height()/height)) + 1
+	}
+	return repeatX, repeatY
+}
+
+// toOffset converts the hex to the column and row of the map
+func (w *HexWrap) toOffset(h Hex[int64]) (int64, int64) {
+	if w.Layout.Orientation.isFlat() {
+		return h.Q, h.R + floorDiv(h.Q, 2)
+	}
+	return h.Q + floorDiv(h.R, 2), h.R
+}
+
+// fromOffset converts the column and row of the map to a hex
+func (w *HexWrap) fromOffset(col, row int64) Hex[int64] {
+	if w.Layout.Orientation.isFlat() {
+		return Hex[int64]{Q: col, R: row - floorDiv(col, 2)}
+	}
+	return Hex[int64]{Q: col - floorDiv(row, 2), R: row}
+}
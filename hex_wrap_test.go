@@ -0,0 +1,249 @@
+package maths
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testWraps returns wrapping maps for both orientations and modes
+func testWraps(t *testing.T) map[string]*HexWrap {
+	t.Helper()
+
+	wraps := make(map[string]*HexWrap)
+	for name, orientation := range map[string]HexOrientation{"Pointy": LayoutPointy, "Flat": LayoutFlat} {
+		layout := NewHexLayout(orientation, NewVector2D[float64](10, 10), NewVector2D[float64](0, 0), 1)
+		for modeName, mode := range map[string]HexWrapMode{"Cylinder": HexWrapCylinder, "Torus": HexWrapTorus} {
+			wrap, err := NewHexWrap(layout, 10, 8, mode)
+			require.NoError(t, err)
+			wraps[name+" "+modeName] = wrap
+		}
+	}
+	return wraps
+}
+
+func TestNewHexWrap(t *testing.T) {
+	t.Parallel()
+
+	pointy := NewHexLayout(LayoutPointy, NewVector2D[float64](10, 10), NewVector2D[float64](0, 0), 1)
+	flat := NewHexLayout(LayoutFlat, NewVector2D[float64](10, 10), NewVector2D[float64](0, 0), 1)
+
+	_, err := NewHexWrap(pointy, 9, 5, HexWrapCylinder)
+	assert.NoError(t, err)
+	_, err = NewHexWrap(pointy, 9, 5, HexWrapTorus)
+	assert.ErrorIs(t, err, ErrInvalidHexWrap)
+	_, err = NewHexWrap(flat, 9, 5, HexWrapCylinder)
+	assert.ErrorIs(t, err, ErrInvalidHexWrap)
+	_, err = NewHexWrap(flat, 10, 0, HexWrapCylinder)
+	assert.ErrorIs(t, err, ErrInvalidHexWrap)
+
+	// Orientations which are not an exact copy of LayoutFlat still wrap as flat
+	computed := flat
+	computed.Orientation.F2 = 0.8660254
+	computed.Orientation.B3 = 0.5773503
+	_, err = NewHexWrap(computed, 9, 5, HexWrapCylinder)
+	assert.ErrorIs(t, err, ErrInvalidHexWrap)
+
+	exact, err := NewHexWrap(flat, 10, 8, HexWrapTorus)
+	require.NoError(t, err)
+	approx, err := NewHexWrap(computed, 10, 8, HexWrapTorus)
+	require.NoError(t, err)
+	for _, h := range (Hex[int64]{Q: 3, R: -2}).Spiral(12) {
+		expected, _ := exact.Canonical(h)
+		actual, _ := approx.Canonical(h)
+		assert.Equal(t, expected, actual, h)
+	}
+}
+
+func TestHexWrapCanonical(t *testing.T) {
+	t.Parallel()
+
+	for name, wrap := range testWraps(t) {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			hexes := wrap.Hexes()
+			assert.Len(t, hexes, int(wrap.Width*wrap.Height))
+			unique := make(map[Hex[int64]]bool)
+			for _, h := range hexes {
+				c, ok := wrap.Canonical(h)
+				assert.True(t, ok)
+				assert.Equal(t, h, c)
+				unique[h] = true
+
+				// Images around the map are the same hex
+				for k := int64(-2); k <= 2; k++ {
+					c, ok = wrap.Canonical(h.Add(wrap.period.Multiply(k)))
+					assert.True(t, ok)
+					assert.Equal(t, h, c)
+
+					c, ok = wrap.Canonical(h.Add(wrap.periodY.Multiply(k)))
+					assert.Equal(t, wrap.Mode == HexWrapTorus || k == 0, ok)
+					if ok {
+						assert.Equal(t, h, c)
+					}
+				}
+			}
+			assert.Len(t, unique, len(hexes))
+
+			// The world position of an image repeats the map
+			h := hexes[13]
+			shifted := wrap.Layout.HexToVector2D(h.Add(wrap.period).ToFloat())
+			assert.InDelta(t, wrap.CellToWorld(h).Y, shifted.Y, 1e-9)
+			assert.Equal(t, h, wrap.WorldToCell(shifted))
+		})
+	}
+}
+
+func TestHexWrapDistance(t *testing.T) {
+	t.Parallel()
+
+	for name, wrap := range testWraps(t) {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			hexes := wrap.Hexes()
+			for _, start := range []Hex[int64]{hexes[0], hexes[17], hexes[len(hexes)-1]} {
+				steps := GridFloodFill[Hex[int64]](wrap, start, 100, func(Hex[int64]) bool { return true })
+				assert.Len(t, steps, len(hexes))
+				for _, h := range hexes {
+					assert.Equal(t, float64(steps[h]), wrap.Distance(start, h), "%v to %v", start, h)
+					assert.Equal(t, wrap.Distance(h, start), wrap.Distance(start, h))
+				}
+			}
+		})
+	}
+}
+
+func TestHexWrapNeighbours(t *testing.T) {
+	t.Parallel()
+
+	layout := NewHexLayout(LayoutPointy, NewVector2D[float64](10, 10), NewVector2D[float64](0, 0), 1)
+	cylinder, err := NewHexWrap(layout, 10, 8, HexWrapCylinder)
+	require.NoError(t, err)
+	torus, err := NewHexWrap(layout, 10, 8, HexWrapTorus)
+	require.NoError(t, err)
+
+	corner := Hex[int64]{Q: 0, R: 0}
+	assert.Len(t, cylinder.Neighbours(corner), 4)
+	assert.Contains(t, cylinder.Neighbours(corner), Hex[int64]{Q: 9, R: 0})
+	assert.Len(t, torus.Neighbours(corner), 6)
+	assert.Equal(t, 1.0, cylinder.Distance(corner, Hex[int64]{Q: 9, R: 0}))
+}
+
+func TestHexWrapLineTo(t *testing.T) {
+	t.Parallel()
+
+	for name, wrap := range testWraps(t) {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			hexes := wrap.Hexes()
+			for _, pair := range [][2]int{{0, 9}, {3, 75}, {12, 12}, {41, 50}} {
+				a, b := hexes[pair[0]], hexes[pair[1]]
+				line := wrap.LineTo(a, b)
+				assert.Len(t, line, int(wrap.Distance(a, b))+1)
+				assert.Equal(t, a, line[0])
+				assert.Equal(t, b, line[len(line)-1])
+				for i := 1; i < len(line); i++ {
+					assert.Equal(t, 1.0, wrap.Distance(line[i-1], line[i]))
+				}
+			}
+		})
+	}
+}
+
+func TestHexWrapFindPath(t *testing.T) {
+	t.Parallel()
+
+	layout := NewHexLayout(LayoutPointy, NewVector2D[float64](10, 10), NewVector2D[float64](0, 0), 1)
+	wrap, err := NewHexWrap(layout, 12, 6, HexWrapCylinder)
+	require.NoError(t, err)
+
+	// A wall in the middle of the map forces the path across the seam
+	cost := func(h Hex[int64]) float64 {
+		col, _ := wrap.toOffset(h)
+		if col == 6 {
+			return math.Inf(1)
+		}
+		return 1
+	}
+	start, goal := wrap.fromOffset(4, 2), wrap.fromOffset(8, 2)
+
+	path, pathCost, ok := wrap.FindPath(start, goal, cost, 1)
+	assert.True(t, ok)
+	assert.Equal(t, 8.0, pathCost)
+	assert.Contains(t, path, wrap.fromOffset(0, 2))
+	assert.Contains(t, path, wrap.fromOffset(11, 2))
+}
+
+func TestHexWrapCamera(t *testing.T) {
+	t.Parallel()
+
+	layout := NewHexLayout(LayoutPointy, NewVector2D[float64](10, 10), NewVector2D[float64](0, 0), 1)
+	wrap, err := NewHexWrap(layout, 10, 8, HexWrapCylinder)
+	require.NoError(t, err)
+	mapWidth := 10 * math.Sqrt(3) * 10
+
+	// The camera looks at the western seam
+	camera := testCamera{position: NewVector2D[float64](0, 30), zoom: 1, size: NewVector2D[float64](100, 60)}
+	ghosts := wrap.GetVisibleHexes(camera)
+	images := make(map[Hex[int64]]bool)
+	hasGhost := false
+	for _, ghost := range ghosts {
+		c, _ := wrap.Canonical(ghost.Image)
+		assert.Equal(t, c, ghost.Hex)
+		assert.False(t, images[ghost.Image])
+		images[ghost.Image] = true
+		hasGhost = hasGhost || ghost.Hex != ghost.Image
+	}
+	assert.True(t, hasGhost)
+
+	east := wrap.fromOffset(9, 1)
+	positions := wrap.HexToScreen(east, camera)
+	assert.Len(t, positions, 1)
+	assert.Less(t, positions[0].X, camera.size.X/2) // drawn west of the seam
+	assert.Equal(t, east, wrap.ScreenToHex(positions[0], camera))
+
+	// A view wider than the map shows two images of the hex
+	camera.position.X = wrap.CellToWorld(east).X - mapWidth/2
+	camera.zoom = 0.5
+	camera.size = NewVector2D(0.75*mapWidth, 60)
+	assert.Len(t, wrap.HexToScreen(east, camera), 2)
+	assert.Empty(t, wrap.HexToScreen(Hex[int64]{Q: 0, R: 20}, camera))
+}
+
+func TestHexWrapCameraFarAway(t *testing.T) {
+	t.Parallel()
+
+	for name, wrap := range testWraps(t) {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// The camera scrolled several times around the map
+			origin := wrap.Layout.HexToVector2D(Hex[float64]{})
+			period := wrap.Layout.HexToVector2D(wrap.period.ToFloat()).Subtract(origin)
+			position := period.Multiply(5).Add(NewVector2D[float64](7, 20))
+			if wrap.Mode == HexWrapTorus {
+				position = position.Add(wrap.Layout.HexToVector2D(wrap.periodY.ToFloat()).Subtract(origin).Multiply(-3))
+			}
+			camera := testCamera{position: position, zoom: 1, size: NewVector2D[float64](80, 60)}
+
+			ghosts := wrap.GetVisibleHexes(camera)
+			assert.NotEmpty(t, ghosts)
+			for _, ghost := range ghosts {
+				expected := worldToScreen(wrap.Layout.HexToVector2D(ghost.Image.ToFloat()), camera)
+				positions := wrap.HexToScreen(ghost.Hex, camera)
+				require.NotEmpty(t, positions, ghost.Image)
+
+				found := false
+				for _, p := range positions {
+					found = found || p.Distance(expected) < 1e-6
+				}
+				assert.True(t, found, ghost.Image)
+			}
+		})
+	}
+}
@@ -0,0 +1,337 @@
+package maths
+
+import (
+	"container/heap"
+	"slices"
+)
+
+// hpaClusterKey identifies a cluster by its position in the grid of clusters
+type hpaClusterKey = Hex[int64]
+
+// hpaEdge is an edge of the abstract graph
+type hpaEdge struct {
+	to   Hex[int64]
+	cost float64
+}
+
+// hpaCluster holds the entrance nodes of a cluster and the costs between them
+type hpaCluster struct {
+	nodes []Hex[int64]
+	edges HexMap[[]hpaEdge]
+}
+
+// HPAStar finds paths on large hex maps with hierarchical pathfinding. The map
+// is split into parallelograms of clusterSize by clusterSize hexes in axial
+// coordinates. Clusters are connected by entrance nodes in the middle of every
+// passable stretch of their borders, and the costs between the entrances of a
+// cluster are computed once by Precompute or when a search first reaches it.
+// Paths between close hexes are also searched directly around their clusters.
+// Paths are close to optimal but not guaranteed to be the cheapest.
+type HPAStar struct {
+	cost        HexCostFunc
+	clusterSize int64
+	minCost     float64
+	clusters    map[hpaClusterKey]*hpaCluster
+	borders     map[[2]hpaClusterKey][][2]Hex[int64]
+}
+
+// NewHPAStar creates a new hierarchical pathfinder. The cost function and
+// minCost are used like in HexAStar.
+func NewHPAStar(cost HexCostFunc, clusterSize int, minCost float64) *HPAStar {
+	return &HPAStar{
+		cost:        cost,
+		clusterSize: int64(max(clusterSize, 1)),
+		minCost:     minCost,
+		clusters:    make(map[hpaClusterKey]*hpaCluster),
+		borders:     make(map[[2]hpaClusterKey][][2]Hex[int64]),
+	}
+}
+
+// Cluster returns the key of the cluster containing the hex
+func (p *HPAStar) Cluster(h Hex[int64]) Hex[int64] {
+	return Hex[int64]{Q: floorDiv(h.Q, p.clusterSize), R: floorDiv(h.R, p.clusterSize)}
+}
+
+// Invalidate drops the precomputed data of the cluster containing the hex after
+// its terrain changed. The entrances of the neighbouring clusters are
+// recomputed as well as they share borders with the cluster.
+func (p *HPAStar) Invalidate(h Hex[int64]) {
+	key := p.Cluster(h)
+	delete(p.clusters, key)
+	for _, dir := range directions {
+		neighbour := key.Add(dir)
+		delete(p.clusters, neighbour)
+		delete(p.borders, borderKey(key, neighbour))
+	}
+}
+
+// Precompute computes the entrances and the costs between them for all clusters
+// overlapping the axial bounds from low to high, so searches do not pay for it
+func (p *HPAStar) Precompute(low, high Hex[int64]) {
+	first, last := p.Cluster(low), p.Cluster(high)
+	for q := first.Q; q <= last.Q; q++ {
+		for r := first.R; r <= last.R; r++ {
+			p.cluster(hpaClusterKey{Q: q, R: r})
+		}
+	}
+}
+
+// FindPath returns a path from start to goal including both and its cost.
+// Before reporting that no path exists it runs a full HexAStar search.
+func (p *HPAStar) FindPath(start, goal Hex[int64]) ([]Hex[int64], float64, bool) {
+	if !passable(p.cost, start) || !passable(p.cost, goal) {
+		return nil, 0, false
+	}
+	if start == goal {
+		return []Hex[int64]{start}, 0, true
+	}
+
+	// Temporary edges connect start and goal to the entrances of their clusters
+	startKey, goalKey := p.Cluster(start), p.Cluster(goal)
+	startCosts := p.clusterCosts(start, false)
+	goalCosts := p.clusterCosts(goal, true)
+
+	var startEdges []hpaEdge
+	if distance, ok := startCosts[goal]; ok {
+		startEdges = append(startEdges, hpaEdge{to: goal, cost: distance})
+	}
+	for _, node := range p.cluster(startKey).nodes {
+		if distance, ok := startCosts[node]; ok && node != start {
+			startEdges = append(startEdges, hpaEdge{to: node, cost: distance})
+		}
+	}
+	for _, edge := range p.cluster(startKey).edges[start] {
+		if p.Cluster(edge.to) != startKey {
+			startEdges = append(startEdges, edge)
+		}
+	}
+
+	neighbours := func(h Hex[int64]) []hpaEdge {
+		if h == start {
+			return startEdges
+		}
+		edges := p.cluster(p.Cluster(h)).edges[h]
+		if p.Cluster(h) == goalKey {
+			if distance, ok := goalCosts[h]; ok {
+				edges = append(slices.Clip(edges), hpaEdge{to: goal, cost: distance})
+			}
+		}
+		return edges
+	}
+
+	abstract, cost, ok := p.abstractSearch(start, goal, neighbours)
+	var path []Hex[int64]
+	if ok {
+		path, ok = p.refine(abstract)
+	}
+
+	// Entrances are coarse for close hexes, a local search around both clusters can be cheaper
+	if start.Distance(goal) <= float64(p.clusterSize) {
+		local, localCost, localOK := hexAStar(start, goal, p.cost, p.minCost, func(h Hex[int64]) bool {
+			key := p.Cluster(h)
+			return key.Distance(startKey) <= 1 || key.Distance(goalKey) <= 1
+		})
+		if localOK && (!ok || localCost < cost) {
+			path, cost, ok = local, localCost, true
+		}
+	}
+
+	// The cluster data is stale when costs changed without Invalidate, a full search has the final word
+	if !ok {
+		path, cost, ok = HexAStar(start, goal, p.cost, p.minCost)
+	}
+	return path, cost, ok
+}
+
+// abstractSearch runs A* over the abstract graph
+func (p *HPAStar) abstractSearch(
+	start, goal Hex[int64],
+	neighbours func(h Hex[int64]) []hpaEdge,
+) ([]Hex[int64], float64, bool) {
+	distances := HexMap[float64]{start: 0}
+	previous := make(HexMap[Hex[int64]])
+	closed := make(HexMap[struct{}])
+	queue := &gridQueue[Hex[int64]]{{cell: start, priority: start.Distance(goal) * p.minCost}}
+	order := 0
+
+	for queue.Len() > 0 {
+		current := heap.Pop(queue).(gridQueueItem[Hex[int64]]).cell
+		if current == goal {
+			return gridPath(previous, start, goal), distances[goal], true
+		}
+		if closed.Has(current) {
+			continue
+		}
+		closed[current] = struct{}{}
+
+		for _, edge := range neighbours(current) {
+			if closed.Has(edge.to) {
+				continue
+			}
+			distance := distances[current] + edge.cost
+			if known, ok := distances[edge.to]; ok && known <= distance {
+				continue
+			}
+			distances[edge.to] = distance
+			previous[edge.to] = current
+			order++
+			heap.Push(queue, gridQueueItem[Hex[int64]]{cell: edge.to, priority: distance + edge.to.Distance(goal)*p.minCost, order: order})
+		}
+	}
+	return nil, 0, false
+}
+
+// refine expands the abstract path into a path over neighbouring hexes, false if a
+// segment cannot be walked within its cluster
+func (p *HPAStar) refine(abstract []Hex[int64]) ([]Hex[int64], bool) {
+	path := []Hex[int64]{abstract[0]}
+	for i := 1; i < len(abstract); i++ {
+		from, to := abstract[i-1], abstract[i]
+		key := p.Cluster(from)
+		if key != p.Cluster(to) {
+			// Entrances of neighbouring clusters are adjacent
+			path = append(path, to)
+			continue
+		}
+
+		segment, _, ok := hexAStar(from, to, p.cost, p.minCost, func(h Hex[int64]) bool {
+			return p.Cluster(h) == key
+		})
+		if !ok {
+			return nil, false
+		}
+		path = append(path, segment[1:]...)
+	}
+	return path, true
+}
+
+// cluster returns the cluster with its entrances and costs, computing them on first use
+func (p *HPAStar) cluster(key hpaClusterKey) *hpaCluster {
+	if c, ok := p.clusters[key]; ok {
+		return c
+	}
+
+	c := &hpaCluster{edges: make(HexMap[[]hpaEdge])}
+	for _, dir := range directions {
+		neighbour := key.Add(dir)
+		for _, transition := range p.border(key, neighbour) {
+			inside, outside := transition[0], transition[1]
+			if p.Cluster(inside) != key {
+				inside, outside = outside, inside
+			}
+			if _, ok := c.edges[inside]; !ok {
+				c.nodes = append(c.nodes, inside)
+			}
+			c.edges[inside] = append(c.edges[inside], hpaEdge{to: outside, cost: p.cost(outside)})
+		}
+	}
+
+	// Costs between all entrances within the cluster
+	for _, node := range c.nodes {
+		costs := p.clusterCosts(node, false)
+		for _, other := range c.nodes {
+			if distance, ok := costs[other]; ok && other != node {
+				c.edges[node] = append(c.edges[node], hpaEdge{to: other, cost: distance})
+			}
+		}
+	}
+
+	p.clusters[key] = c
+	return c
+}
+
+// border returns the transitions between two neighbouring clusters chosen as entrances.
+// Every transition is a pair of adjacent passable hexes, one in each cluster.
+func (p *HPAStar) border(a, b hpaClusterKey) [][2]Hex[int64] {
+	key := borderKey(a, b)
+	if transitions, ok := p.borders[key]; ok {
+		return transitions
+	}
+
+	// Collect all passable transitions from the first cluster of the key
+	var all [][2]Hex[int64]
+	origin := Hex[int64]{Q: key[0].Q * p.clusterSize, R: key[0].R * p.clusterSize}
+	for q := int64(0); q < p.clusterSize; q++ {
+		for r := int64(0); r < p.clusterSize; r++ {
+			h := origin.Add(Hex[int64]{Q: q, R: r})
+			if !passable(p.cost, h) {
+				continue
+			}
+			for _, dir := range directions {
+				n := h.Add(dir)
+				if p.Cluster(n) == key[1] && passable(p.cost, n) {
+					all = append(all, [2]Hex[int64]{h, n})
+				}
+			}
+		}
+	}
+	slices.SortFunc(all, func(x, y [2]Hex[int64]) int {
+		if c := compareHex(x[0], y[0]); c != 0 {
+			return c
+		}
+		return compareHex(x[1], y[1])
+	})
+
+	// Split into runs of adjacent transitions and use the middle of every run
+	var chosen [][2]Hex[int64]
+	start := 0
+	for i := 1; i <= len(all); i++ {
+		if i < len(all) && all[i][0].Distance(all[i-1][0]) <= 1 && all[i][1].Distance(all[i-1][1]) <= 1 {
+			continue
+		}
+		chosen = append(chosen, all[(start+i-1)/2])
+		start = i
+	}
+
+	p.borders[key] = chosen
+	return chosen
+}
+
+// clusterCosts returns the costs from the hex to all reachable hexes of its
+// cluster, or from all of them to the hex when reverse is set
+func (p *HPAStar) clusterCosts(from Hex[int64], reverse bool) HexMap[float64] {
+	key := p.Cluster(from)
+	distances := HexMap[float64]{from: 0}
+	queue := &gridQueue[Hex[int64]]{{cell: from}}
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(gridQueueItem[Hex[int64]])
+		if item.priority > distances[item.cell] {
+			continue
+		}
+		for _, dir := range directions {
+			next := item.cell.Add(dir)
+			if p.Cluster(next) != key || !passable(p.cost, next) {
+				continue
+			}
+
+			// Backwards the step from next onto the current hex is paid
+			distance := item.priority + p.cost(next)
+			if reverse {
+				distance = item.priority + p.cost(item.cell)
+			}
+			if known, ok := distances[next]; ok && known <= distance {
+				continue
+			}
+			distances[next] = distance
+			heap.Push(queue, gridQueueItem[Hex[int64]]{cell: next, priority: distance})
+		}
+	}
+	return distances
+}
+
+// borderKey returns the two clusters of a border in a fixed order
+func borderKey(a, b hpaClusterKey) [2]hpaClusterKey {
+	if compareHex(a, b) > 0 {
+		a, b = b, a
+	}
+	return [2]hpaClusterKey{a, b}
+}
+
+// floorDiv divides rounding towards negative infinity
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
@@ -0,0 +1,217 @@
+package maths
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testMaze returns a cost function over a parallelogram map with walls and expensive terrain
+func testMaze(size int64, costs HexMap[float64]) HexCostFunc {
+	return func(h Hex[int64]) float64 {
+		if h.Q < 0 || h.R < 0 || h.Q >= size || h.R >= size {
+			return math.Inf(1)
+		}
+		if cost, ok := costs[h]; ok {
+			return cost
+		}
+		return 1
+	}
+}
+
+// testMazeCosts places walls with gaps and some expensive hexes
+func testMazeCosts(size int64) HexMap[float64] {
+	costs := make(HexMap[float64])
+	for q := int64(5); q < size; q += 9 {
+		for r := int64(0); r < size; r++ {
+			if (r+q)%13 != 0 {
+				costs[Hex[int64]{Q: q, R: r}] = -1
+			}
+		}
+	}
+	for r := int64(7); r < size; r += 11 {
+		for q := int64(0); q < size; q++ {
+			if q%4 == 0 {
+				costs[Hex[int64]{Q: q, R: r}] = 3
+			}
+		}
+	}
+	return costs
+}
+
+func TestHPAStar(t *testing.T) {
+	t.Parallel()
+
+	const size = 40
+	cost := testMaze(size, testMazeCosts(size))
+	pathfinder := NewHPAStar(cost, 8, 1)
+
+	queries := [][2]Hex[int64]{
+		{{Q: 0, R: 0}, {Q: 39, R: 39}},
+		{{Q: 39, R: 0}, {Q: 0, R: 39}},
+		{{Q: 1, R: 1}, {Q: 3, R: 2}},    // same cluster
+		{{Q: 7, R: 7}, {Q: 8, R: 8}},    // neighbouring clusters
+		{{Q: 20, R: 3}, {Q: 20, R: 30}}, // along a wall
+		{{Q: 2, R: 30}, {Q: 38, R: 1}},
+	}
+
+	for _, query := range queries {
+		start, goal := query[0], query[1]
+		path, pathCost, ok := pathfinder.FindPath(start, goal)
+		_, optimal, optimalOK := HexAStar(start, goal, cost, 1)
+		assert.Equal(t, optimalOK, ok)
+		if !ok {
+			continue
+		}
+
+		assertPath(t, path, start, goal, cost, pathCost)
+		assert.GreaterOrEqual(t, pathCost, optimal-1e-9)
+		assert.LessOrEqual(t, pathCost, optimal*1.5, "%v to %v", start, goal)
+	}
+
+	// Blocked and unreachable goals
+	_, _, ok := pathfinder.FindPath(Hex[int64]{Q: 0, R: 0}, Hex[int64]{Q: 5, R: 1})
+	assert.False(t, ok)
+	_, _, ok = pathfinder.FindPath(Hex[int64]{Q: 0, R: 0}, Hex[int64]{Q: 50, R: 1})
+	assert.False(t, ok)
+
+	path, pathCost, ok := pathfinder.FindPath(Hex[int64]{Q: 3, R: 3}, Hex[int64]{Q: 3, R: 3})
+	assert.True(t, ok)
+	assert.Equal(t, []Hex[int64]{{Q: 3, R: 3}}, path)
+	assert.Zero(t, pathCost)
+}
+
+func TestHPAStarInvalidate(t *testing.T) {
+	t.Parallel()
+
+	const size = 24
+	costs := make(HexMap[float64])
+	cost := testMaze(size, costs)
+	pathfinder := NewHPAStar(cost, 6, 1)
+
+	start, goal := Hex[int64]{Q: 2, R: 9}, Hex[int64]{Q: 20, R: 9}
+	path, _, ok := pathfinder.FindPath(start, goal)
+	assert.True(t, ok)
+
+	// Wall off the whole column the path used in the middle cluster
+	var changed Hex[int64]
+	for _, h := range path {
+		if h.Q == 12 {
+			changed = h
+		}
+	}
+	for r := int64(0); r < size; r++ {
+		if r != 20 {
+			costs[Hex[int64]{Q: 12, R: r}] = -1
+		}
+	}
+	for r := int64(0); r < size; r += 6 {
+		pathfinder.Invalidate(Hex[int64]{Q: 12, R: r})
+	}
+
+	path, pathCost, ok := pathfinder.FindPath(start, goal)
+	assert.True(t, ok)
+	assertPath(t, path, start, goal, cost, pathCost)
+	assert.NotContains(t, path, changed)
+	assert.Contains(t, path, Hex[int64]{Q: 12, R: 20})
+}
+
+func TestHPAStarPrecompute(t *testing.T) {
+	t.Parallel()
+
+	const size = 40
+	cost := testMaze(size, testMazeCosts(size))
+	lazy, eager := NewHPAStar(cost, 8, 1), NewHPAStar(cost, 8, 1)
+	eager.Precompute(Hex[int64]{}, Hex[int64]{Q: size - 1, R: size - 1})
+	assert.Len(t, eager.clusters, 25)
+
+	// Searches give the same results and do not build more clusters
+	start, goal := Hex[int64]{Q: 0, R: 0}, Hex[int64]{Q: 39, R: 39}
+	lazyPath, lazyCost, lazyOK := lazy.FindPath(start, goal)
+	eagerPath, eagerCost, eagerOK := eager.FindPath(start, goal)
+	assert.True(t, eagerOK)
+	assert.Equal(t, lazyOK, eagerOK)
+	assert.Equal(t, lazyPath, eagerPath)
+	assert.Equal(t, lazyCost, eagerCost)
+	assert.Len(t, eager.clusters, 25)
+
+	// Negative bounds are split into clusters rounding down
+	eager.Precompute(Hex[int64]{Q: -1, R: -9}, Hex[int64]{Q: 0, R: 0})
+	assert.Len(t, eager.clusters, 30)
+}
+
+func TestHPAStarStale(t *testing.T) {
+	t.Parallel()
+
+	const size = 18
+	costs := make(HexMap[float64])
+	cost := testMaze(size, costs)
+	pathfinder := NewHPAStar(cost, 6, 1)
+	pathfinder.Precompute(Hex[int64]{}, Hex[int64]{Q: size - 1, R: size - 1})
+
+	// A wall through the middle cluster without Invalidate leaves its costs stale
+	for r := int64(6); r < 12; r++ {
+		costs[Hex[int64]{Q: 9, R: r}] = -1
+	}
+
+	start, goal := Hex[int64]{Q: 0, R: 8}, Hex[int64]{Q: 17, R: 8}
+	path, pathCost, ok := pathfinder.FindPath(start, goal)
+	_, optimal, _ := HexAStar(start, goal, cost, 1)
+	assert.True(t, ok)
+	assertPath(t, path, start, goal, cost, pathCost)
+	assert.Equal(t, optimal, pathCost)
+}
+
+func TestHPAStarStaleBorder(t *testing.T) {
+	t.Parallel()
+
+	const size = 12
+	costs := make(HexMap[float64])
+	cost := testMaze(size, costs)
+	for r := int64(0); r < size; r++ {
+		costs[Hex[int64]{Q: 6, R: r}] = -1
+	}
+	pathfinder := NewHPAStar(cost, 6, 1)
+
+	start, goal := Hex[int64]{Q: 0, R: 3}, Hex[int64]{Q: 11, R: 3}
+	_, _, ok := pathfinder.FindPath(start, goal)
+	assert.False(t, ok)
+
+	// A gap opened without Invalidate is missing from the cached entrances
+	delete(costs, Hex[int64]{Q: 6, R: 3})
+	path, pathCost, ok := pathfinder.FindPath(start, goal)
+	_, optimal, _ := HexAStar(start, goal, cost, 1)
+	assert.True(t, ok)
+	assertPath(t, path, start, goal, cost, pathCost)
+	assert.Equal(t, optimal, pathCost)
+}
+
+func TestFloorDiv(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, int64(2), floorDiv(7, 3))
+	assert.Equal(t, int64(-3), floorDiv(-7, 3))
+	assert.Equal(t, int64(-1), floorDiv(-3, 3))
+	assert.Equal(t, int64(0), floorDiv(0, 3))
+}
+
+func BenchmarkHPAStar(b *testing.B) {
+	const size = 200
+	cost := testMaze(size, testMazeCosts(size))
+	start, goal := Hex[int64]{Q: 1, R: 1}, Hex[int64]{Q: 198, R: 190}
+
+	b.Run("AStar", func(b *testing.B) {
+		for b.Loop() {
+			HexAStar(start, goal, cost, 1)
+		}
+	})
+
+	b.Run("HPAStar", func(b *testing.B) {
+		pathfinder := NewHPAStar(cost, 10, 1)
+		pathfinder.Precompute(Hex[int64]{}, Hex[int64]{Q: size - 1, R: size - 1})
+		for b.Loop() {
+			pathfinder.FindPath(start, goal)
+		}
+	})
+}
@@ -0,0 +1,175 @@
+package maths
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// IsoLayout is the way the cells of an isometric grid are numbered
+type IsoLayout int
+
+const (
+	// IsoDiamond grids have the X axis to the bottom right and the Y axis to the bottom left, the map forms a diamond
+	IsoDiamond IsoLayout = iota
+	// IsoStaggered grids have rows of tiles where every odd row is shifted half a tile to the right, the map forms a rectangle
+	IsoStaggered
+)
+
+// IsoGrid is a grid of diamond shaped tiles with camera integration. TileSize is the
+// width and height of a tile and Origin the world position of the center of cell (0, 0).
+type IsoGrid struct {
+	Layout   IsoLayout
+	TileSize Vector2D[float64]
+	Origin   Vector2D[float64]
+}
+
+// NewIsoGrid creates a new isometric grid with the layout, tile size and the origin at zero
+func NewIsoGrid(layout IsoLayout, tileSize Vector2D[float64]) *IsoGrid {
+	return &IsoGrid{Layout: layout, TileSize: tileSize}
+}
+
+// CellToWorld returns the world position of the cell center
+func (grid *IsoGrid) CellToWorld(cell Vector2D[int64]) Vector2D[float64] {
+	d := grid.toDiamond(cell)
+	return Vector2D[float64]{
+		X: grid.Origin.X + float64(d.X-d.Y)*grid.TileSize.X/2,
+		Y: grid.Origin.Y + float64(d.X+d.Y)*grid.TileSize.Y/2,
+	}
+}
+
+// WorldToCell returns the cell containing the world position
+func (grid *IsoGrid) WorldToCell(worldPos Vector2D[float64]) Vector2D[int64] {
+	d := grid.worldToDiamond(worldPos)
+	return grid.fromDiamond(Vector2D[int64]{X: int64(math.Floor(d.X + 0.5)), Y: int64(math.Floor(d.Y + 0.5))})
+}
+
+// WorldToScreen converts world coordinates to screen coordinates using camera data
+func (grid *IsoGrid) WorldToScreen(worldPos Vector2D[float64], camera Camera) Vector2D[float64] {
+	return worldToScreen(worldPos, camera)
+}
+
+// ScreenToWorld converts screen coordinates to world coordinates using camera data
+func (grid *IsoGrid) ScreenToWorld(screenPos Vector2D[float64], camera Camera) Vector2D[float64] {
+	return screenToWorld(screenPos, camera)
+}
+
+// CellToScreen returns the screen position of the cell center
+func (grid *IsoGrid) CellToScreen(cell Vector2D[int64], camera Camera) Vector2D[float64] {
+	return worldToScreen(grid.CellToWorld(cell), camera)
+}
+
+// ScreenToCell returns the cell under the screen position
+func (grid *IsoGrid) ScreenToCell(screenPos Vector2D[float64], camera Camera) Vector2D[int64] {
+	return grid.WorldToCell(screenToWorld(screenPos, camera))
+}
+
+// GetVisibleCells returns all cells overlapping the camera view sorted back to front for drawing
+func (grid *IsoGrid) GetVisibleCells(camera Camera) []Vector2D[int64] {
+	view := cameraView(camera)
+
+	// Range of diamond coordinates covering the view
+	minA, minB := math.Inf(1), math.Inf(1)
+	maxA, maxB := math.Inf(-1), math.Inf(-1)
+	for _, corner := range []Vector2D[float64]{view.Min, view.Max, {X: view.Min.X, Y: view.Max.Y}, {X: view.Max.X, Y: view.Min.Y}} {
+		d := grid.worldToDiamond(corner)
+		minA, maxA = math.Min(minA, d.X), math.Max(maxA, d.X)
+		minB, maxB = math.Min(minB, d.Y), math.Max(maxB, d.Y)
+	}
+
+	halfTile := grid.TileSize.Divide(2)
+	var cells []Vector2D[int64]
+	for a := int64(math.Floor(minA)); a <= int64(math.Ceil(maxA)); a++ {
+		for b := int64(math.Floor(minB)); b <= int64(math.Ceil(maxB)); b++ {
+			cell := grid.fromDiamond(Vector2D[int64]{X: a, Y: b})
+			if NewRectFromCenter(grid.CellToWorld(cell), halfTile).Intersects(view) {
+				cells = append(cells, cell)
+			}
+		}
+	}
+
+	slices.SortFunc(cells, func(a, b Vector2D[int64]) int {
+		pa, pb := grid.CellToWorld(a), grid.CellToWorld(b)
+		return cmp.Or(cmp.Compare(pa.Y, pb.Y), cmp.Compare(pa.X, pb.X))
+	})
+	return cells
+}
+
+// CellCorners returns the top, right, bottom and left corner of the tile in world coordinates
+func (grid *IsoGrid) CellCorners(cell Vector2D[int64]) []Vector2D[float64] {
+	center := grid.CellToWorld(cell)
+	halfWidth, halfHeight := grid.TileSize.X/2, grid.TileSize.Y/2
+
+	return []Vector2D[float64]{
+		{X: center.X, Y: center.Y - halfHeight},
+		{X: center.X + halfWidth, Y: center.Y},
+		{X: center.X, Y: center.Y + halfHeight},
+		{X: center.X - halfWidth, Y: center.Y},
+	}
+}
+
+// CellCornersScreen returns the corners of the tile in screen coordinates
+func (grid *IsoGrid) CellCornersScreen(cell Vector2D[int64], camera Camera) []Vector2D[float64] {
+	corners := grid.CellCorners(cell)
+	for i, corner := range corners {
+		corners[i] = worldToScreen(corner, camera)
+	}
+	return corners
+}
+
+// CellImageToScreen calculates the screen position and scale factor for an image in a tile.
+// The image keeps its aspect ratio and fits into the bounds of the tile.
+func (grid *IsoGrid) CellImageToScreen(
+	cell Vector2D[int64],
+	imageDefaultSize Vector2D[float64],
+	camera Camera,
+) (centerPosition Vector2D[float64], imageScaleFactor Vector2D[float64]) {
+	centerPosition = grid.CellToScreen(cell, camera)
+	tileSize := Vector2D[float64]{X: grid.TileSize.X * camera.GetZoom(), Y: grid.TileSize.Y * camera.GetZoom()}
+	return centerPosition, imageScale(tileSize, imageDefaultSize)
+}
+
+// Neighbours4 returns the tiles sharing an edge with the tile
+func (grid *IsoGrid) Neighbours4(cell Vector2D[int64]) []Vector2D[int64] {
+	return grid.neighbours(cell, squareDirections4)
+}
+
+// Neighbours8 returns the tiles sharing an edge or a corner with the tile
+func (grid *IsoGrid) Neighbours8(cell Vector2D[int64]) []Vector2D[int64] {
+	return grid.neighbours(cell, squareDirections8)
+}
+
+// neighbours moves the cell by the offsets in diamond coordinates
+func (grid *IsoGrid) neighbours(cell Vector2D[int64], offsets []Vector2D[int64]) []Vector2D[int64] {
+	cells := offsetCells(grid.toDiamond(cell), offsets)
+	for i, d := range cells {
+		cells[i] = grid.fromDiamond(d)
+	}
+	return cells
+}
+
+// worldToDiamond returns the fractional diamond coordinates of the world position
+func (grid *IsoGrid) worldToDiamond(worldPos Vector2D[float64]) Vector2D[float64] {
+	u := (worldPos.X - grid.Origin.X) / (grid.TileSize.X / 2)
+	v := (worldPos.Y - grid.Origin.Y) / (grid.TileSize.Y / 2)
+	return Vector2D[float64]{X: (u + v) / 2, Y: (v - u) / 2}
+}
+
+// toDiamond converts a cell of the layout to diamond coordinates
+func (grid *IsoGrid) toDiamond(cell Vector2D[int64]) Vector2D[int64] {
+	if grid.Layout != IsoStaggered {
+		return cell
+	}
+
+	// Column offset in half tiles including the shift of odd rows
+	column := 2*cell.X + cell.Y - 2*floorDiv(cell.Y, 2)
+	return Vector2D[int64]{X: (cell.Y + column) / 2, Y: (cell.Y - column) / 2}
+}
+
+// fromDiamond converts diamond coordinates to a cell of the layout
+func (grid *IsoGrid) fromDiamond(d Vector2D[int64]) Vector2D[int64] {
+	if grid.Layout != IsoStaggered {
+		return d
+	}
+	return Vector2D[int64]{X: floorDiv(d.X-d.Y, 2), Y: d.X + d.Y}
+}
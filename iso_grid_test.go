@@ -0,0 +1,119 @@
+package maths
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsoGridWorld(t *testing.T) {
+	t.Parallel()
+
+	for _, layout := range []IsoLayout{IsoDiamond, IsoStaggered} {
+		grid := NewIsoGrid(layout, NewVector2D[float64](64, 32))
+		grid.Origin = NewVector2D[float64](10, -5)
+
+		for x := int64(-4); x <= 4; x++ {
+			for y := int64(-4); y <= 4; y++ {
+				cell := NewVector2D(x, y)
+				center := grid.CellToWorld(cell)
+				assert.Equal(t, cell, grid.WorldToCell(center))
+
+				// Points inside the diamond belong to the tile
+				for _, offset := range []Vector2D[float64]{{X: 31, Y: 0}, {X: 0, Y: -15}, {X: -15, Y: 7}, {X: 15, Y: 7}} {
+					assert.Equal(t, cell, grid.WorldToCell(center.Add(offset)))
+				}
+			}
+		}
+	}
+}
+
+func TestIsoGridLayout(t *testing.T) {
+	t.Parallel()
+
+	diamond := NewIsoGrid(IsoDiamond, NewVector2D[float64](64, 32))
+	assert.Equal(t, NewVector2D[float64](32, 16), diamond.CellToWorld(NewVector2D[int64](1, 0)))
+	assert.Equal(t, NewVector2D[float64](-32, 16), diamond.CellToWorld(NewVector2D[int64](0, 1)))
+
+	staggered := NewIsoGrid(IsoStaggered, NewVector2D[float64](64, 32))
+	assert.Equal(t, NewVector2D[float64](64, 0), staggered.CellToWorld(NewVector2D[int64](1, 0)))
+	assert.Equal(t, NewVector2D[float64](32, 16), staggered.CellToWorld(NewVector2D[int64](0, 1)))
+	assert.Equal(t, NewVector2D[float64](-32, -16), staggered.CellToWorld(NewVector2D[int64](-1, -1)))
+
+	// Odd rows are shifted to the right
+	assert.ElementsMatch(t, []Vector2D[int64]{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 2}, {X: 1, Y: 2}},
+		staggered.Neighbours4(NewVector2D[int64](0, 1)))
+	assert.ElementsMatch(t, []Vector2D[int64]{{X: -1, Y: -1}, {X: 0, Y: -1}, {X: -1, Y: 1}, {X: 0, Y: 1}},
+		staggered.Neighbours4(NewVector2D[int64](0, 0)))
+}
+
+func TestIsoGridNeighbours(t *testing.T) {
+	t.Parallel()
+
+	for _, layout := range []IsoLayout{IsoDiamond, IsoStaggered} {
+		grid := NewIsoGrid(layout, NewVector2D[float64](64, 32))
+		cell := NewVector2D[int64](3, -3)
+		center := grid.CellToWorld(cell)
+		edge := math.Hypot(32, 16)
+
+		for _, n := range grid.Neighbours4(cell) {
+			assert.InDelta(t, edge, grid.CellToWorld(n).Distance(center), 1e-9)
+		}
+
+		neighbours := grid.Neighbours8(cell)
+		assert.Len(t, neighbours, 8)
+		for _, n := range neighbours {
+			distance := grid.CellToWorld(n).Distance(center)
+			assert.True(t, math.Abs(distance-edge) < 1e-9 || distance == 64 || distance == 32)
+		}
+	}
+}
+
+func TestIsoGridCamera(t *testing.T) {
+	t.Parallel()
+
+	grid := NewIsoGrid(IsoStaggered, NewVector2D[float64](64, 32))
+	camera := testCamera{position: NewVector2D[float64](100, 50), zoom: 0.5, size: NewVector2D[float64](640, 480)}
+
+	cell := NewVector2D[int64](2, 5)
+	screen := grid.CellToScreen(cell, camera)
+	assert.Equal(t, cell, grid.ScreenToCell(screen, camera))
+	assert.Equal(t, grid.WorldToScreen(NewVector2D[float64](160, 80), camera), screen)
+
+	corners := grid.CellCornersScreen(cell, camera)
+	assert.Len(t, corners, 4)
+	assert.Equal(t, NewVector2D[float64](screen.X, screen.Y-8), corners[0])
+	assert.Equal(t, NewVector2D[float64](screen.X+16, screen.Y), corners[1])
+
+	center, scale := grid.CellImageToScreen(cell, NewVector2D[float64](64, 64), camera)
+	assert.Equal(t, screen, center)
+	assert.Equal(t, NewVector2D[float64](0.25, 0.25), scale)
+}
+
+func TestIsoGridVisibleCells(t *testing.T) {
+	t.Parallel()
+
+	for _, layout := range []IsoLayout{IsoDiamond, IsoStaggered} {
+		grid := NewIsoGrid(layout, NewVector2D[float64](64, 32))
+		camera := testCamera{position: NewVector2D[float64](40, 70), zoom: 1, size: NewVector2D[float64](300, 200)}
+		view := NewRect(grid.ScreenToWorld(NewVector2D[float64](0, 0), camera), grid.ScreenToWorld(camera.size, camera))
+
+		cells := grid.GetVisibleCells(camera)
+		visible := make(map[Vector2D[int64]]bool)
+		for i, cell := range cells {
+			visible[cell] = true
+			assert.True(t, NewRectFromCenter(grid.CellToWorld(cell), NewVector2D[float64](32, 16)).Intersects(view))
+			if i > 0 {
+				assert.LessOrEqual(t, grid.CellToWorld(cells[i-1]).Y, grid.CellToWorld(cell).Y)
+			}
+		}
+
+		// Every tile under a point of the view is included
+		for x := view.Min.X; x <= view.Max.X; x += 7 {
+			for y := view.Min.Y; y <= view.Max.Y; y += 5 {
+				assert.True(t, visible[grid.WorldToCell(NewVector2D(x, y))])
+			}
+		}
+	}
+}
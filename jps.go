@@ -0,0 +1,176 @@
+package maths
+
+import (
+	"container/heap"
+	"slices"
+)
+
+// jpsState is a jump point with the way it was reached. Primary moves may
+// turn once to a neighbouring direction, secondary moves only go straight.
+type jpsState struct {
+	hex       Hex[int64]
+	direction int
+	primary   bool
+}
+
+// HexJPS finds a shortest path on a hex grid with uniform costs using jump
+// point search. Shortest hex paths in open terrain only use two neighbouring
+// directions, so the search jumps along straight lines and only stops at hexes
+// where obstacles force a turn. The result has the same length as a path found
+// by HexAStar. The search pays off on maps with large obstacles, on noisy maps
+// the many forced turns make it slower than HexAStar. Passable has to return
+// false outside of the map, otherwise jumps into open space never end.
+func HexJPS(start, goal Hex[int64], passable func(h Hex[int64]) bool) ([]Hex[int64], bool) {
+	if !passable(start) || !passable(goal) {
+		return nil, false
+	}
+	if start == goal {
+		return []Hex[int64]{start}, true
+	}
+
+	search := &jpsSearch{goal: goal, passable: passable}
+	distances := make(map[jpsState]float64)
+	previous := make(map[jpsState]jpsState)
+	queue := &gridQueue[jpsState]{}
+	order := 0
+
+	push := func(from *jpsState, to jpsState, distance float64) {
+		if known, ok := distances[to]; ok && known <= distance {
+			return
+		}
+		distances[to] = distance
+		if from != nil {
+			previous[to] = *from
+		}
+		order++
+		heap.Push(queue, gridQueueItem[jpsState]{cell: to, priority: distance + to.hex.Distance(goal), order: order})
+	}
+
+	// The start moves into every direction
+	for d := range directions {
+		if jump, ok := search.jumpPrimary(start, d); ok {
+			push(nil, jpsState{hex: jump, direction: d, primary: true}, jump.Distance(start))
+		}
+	}
+
+	closed := make(map[jpsState]bool)
+	for queue.Len() > 0 {
+		current := heap.Pop(queue).(gridQueueItem[jpsState]).cell
+		if current.hex == goal {
+			return jpsPath(previous, current, start), true
+		}
+		if closed[current] {
+			continue
+		}
+		closed[current] = true
+
+		distance := distances[current]
+		for _, next := range search.successors(current) {
+			push(&current, next, distance+next.hex.Distance(current.hex))
+		}
+	}
+	return nil, false
+}
+
+// jpsSearch holds the grid and goal of a jump point search
+type jpsSearch struct {
+	goal     Hex[int64]
+	passable func(h Hex[int64]) bool
+}
+
+// successors returns the jump points reachable from the state
+func (s *jpsSearch) successors(state jpsState) []jpsState {
+	var result []jpsState
+	h, d := state.hex, state.direction
+
+	if state.primary {
+		if jump, ok := s.jumpPrimary(h, d); ok {
+			result = append(result, jpsState{hex: jump, direction: d, primary: true})
+		}
+		for _, turn := range []int{(d + 1) % 6, (d + 5) % 6} {
+			if jump, ok := s.jumpSecondary(h, turn); ok {
+				result = append(result, jpsState{hex: jump, direction: turn})
+			}
+		}
+		return result
+	}
+
+	if jump, ok := s.jumpSecondary(h, d); ok {
+		result = append(result, jpsState{hex: jump, direction: d})
+	}
+
+	// Forced neighbours start new primary moves
+	parent := h.Subtract(directions[d])
+	for _, turn := range []int{(d + 1) % 6, (d + 5) % 6} {
+		if s.forced(parent, h, turn) {
+			if jump, ok := s.jumpPrimary(h, turn); ok {
+				result = append(result, jpsState{hex: jump, direction: turn, primary: true})
+			}
+		}
+	}
+	return result
+}
+
+// forced reports whether the neighbour of h in the turn direction can only be
+// reached over h because the matching neighbour of its parent is blocked
+func (s *jpsSearch) forced(parent, h Hex[int64], turn int) bool {
+	return !s.passable(parent.Add(directions[turn])) && s.passable(h.Add(directions[turn]))
+}
+
+// jumpPrimary moves from h in direction d until it reaches the goal or a hex
+// from which a secondary move finds a jump point
+func (s *jpsSearch) jumpPrimary(h Hex[int64], d int) (Hex[int64], bool) {
+	for {
+		h = h.Add(directions[d])
+		if !s.passable(h) {
+			return Hex[int64]{}, false
+		}
+		if h == s.goal {
+			return h, true
+		}
+		for _, turn := range []int{(d + 1) % 6, (d + 5) % 6} {
+			if _, ok := s.jumpSecondary(h, turn); ok {
+				return h, true
+			}
+		}
+	}
+}
+
+// jumpSecondary moves from h in direction d until it reaches the goal or a hex with a forced neighbour
+func (s *jpsSearch) jumpSecondary(h Hex[int64], d int) (Hex[int64], bool) {
+	for {
+		parent := h
+		h = h.Add(directions[d])
+		if !s.passable(h) {
+			return Hex[int64]{}, false
+		}
+		if h == s.goal || s.forced(parent, h, (d+1)%6) || s.forced(parent, h, (d+5)%6) {
+			return h, true
+		}
+	}
+}
+
+// jpsPath expands the jump points leading to the state into a path of neighbouring hexes
+func jpsPath(previous map[jpsState]jpsState, state jpsState, start Hex[int64]) []Hex[int64] {
+	jumps := []Hex[int64]{state.hex}
+	for {
+		parent, ok := previous[state]
+		if !ok {
+			break
+		}
+		jumps = append(jumps, parent.hex)
+		state = parent
+	}
+	jumps = append(jumps, start)
+	slices.Reverse(jumps)
+
+	path := []Hex[int64]{start}
+	for i := 1; i < len(jumps); i++ {
+		steps := int64(jumps[i].Distance(jumps[i-1]))
+		step := jumps[i].Subtract(jumps[i-1]).Divide(steps)
+		for k := int64(1); k <= steps; k++ {
+			path = append(path, jumps[i-1].Add(step.Multiply(k)))
+		}
+	}
+	return path
+}
@@ -0,0 +1,210 @@
+package maths
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testPassable returns a passability predicate over a hex map of the radius without the blocked hexes
+func testPassable(radius int, blocked HexMap[bool]) func(h Hex[int64]) bool {
+	return func(h Hex[int64]) bool {
+		return h.Distance(Hex[int64]{}) <= float64(radius) && !blocked[h]
+	}
+}
+
+// uniformCost converts a passability predicate into a cost function for HexAStar
+func uniformCost(passable func(h Hex[int64]) bool) HexCostFunc {
+	return func(h Hex[int64]) float64 {
+		if passable(h) {
+			return 1
+		}
+		return math.Inf(1)
+	}
+}
+
+// testBlocked blocks hexes of the map randomly with the probability
+func testBlocked(rng *RNG, radius int, probability float64) HexMap[bool] {
+	blocked := make(HexMap[bool])
+	for _, h := range (Hex[int64]{}).Spiral(radius) {
+		if rng.Bool(probability) {
+			blocked[h] = true
+		}
+	}
+	return blocked
+}
+
+// testWalls blocks walls across a map of the radius with gaps alternating between both ends
+func testWalls(radius int64) HexMap[bool] {
+	blocked := make(HexMap[bool])
+	for i, q := 0, -radius+5; q < radius; i, q = i+1, q+15 {
+		low, high := max(-radius, -q-radius), min(radius, radius-q)
+		gap := low + 2
+		if i%2 == 1 {
+			gap = high - 2
+		}
+		for r := low; r <= high; r++ {
+			if r != gap {
+				blocked[Hex[int64]{Q: q, R: r}] = true
+			}
+		}
+	}
+	return blocked
+}
+
+func TestHexJPS(t *testing.T) {
+	t.Parallel()
+
+	blocked := make(HexMap[bool])
+	for _, h := range (Hex[int64]{Q: 0, R: -4}).LineTo(Hex[int64]{Q: 0, R: 5}) {
+		blocked[h] = true
+	}
+	for _, h := range (Hex[int64]{Q: 3, R: -6}).LineTo(Hex[int64]{Q: 3, R: 1}) {
+		blocked[h] = true
+	}
+	passable := testPassable(6, blocked)
+
+	tests := []struct {
+		name      string
+		start     Hex[int64]
+		goal      Hex[int64]
+		reachable bool
+	}{
+		{name: "Around the walls", start: Hex[int64]{Q: -3, R: 0}, goal: Hex[int64]{Q: 5, R: -3}, reachable: true},
+		{name: "Open line", start: Hex[int64]{Q: -5, R: 0}, goal: Hex[int64]{Q: -5, R: 5}, reachable: true},
+		{name: "Same hex", start: Hex[int64]{Q: 2, R: 1}, goal: Hex[int64]{Q: 2, R: 1}, reachable: true},
+		{name: "Neighbour", start: Hex[int64]{Q: 2, R: 1}, goal: Hex[int64]{Q: 2, R: 2}, reachable: true},
+		{name: "Goal blocked", start: Hex[int64]{Q: -3, R: 0}, goal: Hex[int64]{Q: 0, R: 0}},
+		{name: "Goal outside", start: Hex[int64]{Q: -3, R: 0}, goal: Hex[int64]{Q: 10, R: 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path, ok := HexJPS(tt.start, tt.goal, passable)
+			assert.Equal(t, tt.reachable, ok)
+			if !tt.reachable {
+				assert.Nil(t, path)
+				return
+			}
+
+			cost := uniformCost(passable)
+			_, expected, _ := HexAStar(tt.start, tt.goal, cost, 1)
+			assertPath(t, path, tt.start, tt.goal, cost, expected)
+		})
+	}
+}
+
+func TestHexJPSUnreachable(t *testing.T) {
+	t.Parallel()
+
+	blocked := make(HexMap[bool])
+	for _, h := range (Hex[int64]{}).SpiralRing(2) {
+		blocked[h] = true
+	}
+	passable := testPassable(5, blocked)
+
+	path, ok := HexJPS(Hex[int64]{}, Hex[int64]{Q: 4, R: 0}, passable)
+	assert.False(t, ok)
+	assert.Nil(t, path)
+}
+
+func TestHexJPSWalls(t *testing.T) {
+	t.Parallel()
+
+	passable := testPassable(40, testWalls(40))
+	cost := uniformCost(passable)
+	start, goal := Hex[int64]{Q: -38, R: 10}, Hex[int64]{Q: 38, R: -20}
+
+	path, ok := HexJPS(start, goal, passable)
+	_, expected, _ := HexAStar(start, goal, cost, 1)
+	assert.True(t, ok)
+	assertPath(t, path, start, goal, cost, expected)
+}
+
+func TestHexJPSRandom(t *testing.T) {
+	t.Parallel()
+
+	rng := NewRNG(7)
+	for range 50 {
+		const radius = 12
+		passable := testPassable(radius, testBlocked(rng, radius, 0.3))
+		cost := uniformCost(passable)
+		start := rng.HexInRadius(Hex[int64]{}, radius)
+		goal := rng.HexInRadius(Hex[int64]{}, radius)
+
+		path, ok := HexJPS(start, goal, passable)
+		_, expected, reachable := HexAStar(start, goal, cost, 1)
+		assert.Equal(t, reachable, ok)
+		if reachable {
+			assertPath(t, path, start, goal, cost, expected)
+		}
+	}
+}
+
+func FuzzHexJPS(f *testing.F) {
+	f.Add(uint64(1), uint8(60), int8(-5), int8(2), int8(6), int8(-3))
+	f.Add(uint64(2), uint8(0), int8(0), int8(0), int8(8), int8(0))
+	f.Add(uint64(3), uint8(120), int8(8), int8(-8), int8(-8), int8(8))
+
+	f.Fuzz(func(t *testing.T, seed uint64, density uint8, q1, r1, q2, r2 int8) {
+		const radius = 8
+		passable := testPassable(radius, testBlocked(NewRNG(seed), radius, float64(density)/512))
+		cost := uniformCost(passable)
+		start := Hex[int64]{Q: int64(q1 % radius), R: int64(r1 % radius)}
+		goal := Hex[int64]{Q: int64(q2 % radius), R: int64(r2 % radius)}
+
+		path, ok := HexJPS(start, goal, passable)
+		_, expected, reachable := HexAStar(start, goal, cost, 1)
+		if ok != reachable {
+			t.Fatalf("reachable %v, expected %v", ok, reachable)
+		}
+		if reachable {
+			assertPath(t, path, start, goal, cost, expected)
+		}
+	})
+}
+
+func BenchmarkHexJPS(b *testing.B) {
+	const radius = 100
+	start, goal := Hex[int64]{Q: -90, R: 10}, Hex[int64]{Q: 80, R: -60}
+
+	maps := []struct {
+		name    string
+		blocked HexMap[bool]
+	}{
+		{name: "Open", blocked: make(HexMap[bool])},
+		{name: "Walls", blocked: testWalls(radius)},
+		{name: "Noise", blocked: testBlocked(NewRNG(3), radius, 0.1)},
+	}
+	for _, m := range maps {
+		delete(m.blocked, start)
+		delete(m.blocked, goal)
+
+		// Grids in games are usually backed by slices
+		const size = 2*radius + 1
+		cells := make([]bool, size*size)
+		for _, h := range (Hex[int64]{}).Spiral(radius) {
+			cells[(h.R+radius)*size+h.Q+radius] = !m.blocked[h]
+		}
+		passable := func(h Hex[int64]) bool {
+			q, r := h.Q+radius, h.R+radius
+			return q >= 0 && r >= 0 && q < size && r < size && cells[r*size+q]
+		}
+
+		b.Run(m.name+"/AStar", func(b *testing.B) {
+			cost := uniformCost(passable)
+			for b.Loop() {
+				HexAStar(start, goal, cost, 1)
+			}
+		})
+
+		b.Run(m.name+"/JPS", func(b *testing.B) {
+			for b.Loop() {
+				HexJPS(start, goal, passable)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package maths
+
+import (
+	"math"
+	"sort"
+)
+
+// DistanceMetric measures the distance between two points for the KDTree
+type DistanceMetric[T interface {
+	int64 | float64
+}] interface {
+	// Distance returns the distance between two points
+	Distance(a, b Vector2D[T]) float64
+	// AxisDistance returns a lower bound for the distance of two points
+	// whose coordinates differ by delta on a single axis
+	AxisDistance(delta float64) float64
+}
+
+// EuclideanMetric measures the straight line distance like Vector2D.Distance
+type EuclideanMetric[T interface {
+	int64 | float64
+}] struct{}
+
+// Distance returns the Euclidean distance between two points
+func (EuclideanMetric[T]) Distance(a, b Vector2D[T]) float64 {
+	return a.Distance(b)
+}
+
+// AxisDistance returns the delta itself
+func (EuclideanMetric[T]) AxisDistance(delta float64) float64 {
+	return math.Abs(delta)
+}
+
+// ManhattanMetric measures the sum of the absolute axis differences
+type ManhattanMetric[T interface {
+	int64 | float64
+}] struct{}
+
+// Distance returns the Manhattan distance between two points
+func (ManhattanMetric[T]) Distance(a, b Vector2D[T]) float64 {
+	return math.Abs(float64(a.X)-float64(b.X)) + math.Abs(float64(a.Y)-float64(b.Y))
+}
+
+// AxisDistance returns the delta itself
+func (ManhattanMetric[T]) AxisDistance(delta float64) float64 {
+	return math.Abs(delta)
+}
+
+// HexMetric measures the hex distance between the hexes of a layout containing the points
+type HexMetric struct {
+	Layout HexLayout
+	// step is the largest world distance between two neighbouring hex centers
+	step float64
+	// reach is the largest world distance from a hex center to its corners
+	reach float64
+}
+
+// NewHexMetric creates a new hex metric for the layout
+func NewHexMetric(layout HexLayout) HexMetric {
+	origin := layout.HexToVector2D(Hex[float64]{})
+	step := 0.0
+	for _, dir := range directions {
+		step = math.Max(step, layout.HexToVector2D(dir.ToFloat()).Distance(origin))
+	}
+	reach := 0.0
+	for _, corner := range layout.HexCorners(Hex[float64]{}) {
+		reach = math.Max(reach, corner.Distance(origin))
+	}
+	return HexMetric{Layout: layout, step: step, reach: reach}
+}
+
+// Distance returns the hex distance between the hexes at the two points
+func (m HexMetric) Distance(a, b Vector2D[float64]) float64 {
+	hexA := hexRound(m.Layout.Vector2DToHex(a))
+	hexB := hexRound(m.Layout.Vector2DToHex(b))
+	return hexA.Distance(hexB)
+}
+
+// AxisDistance returns the number of hex steps needed to cover the delta,
+// less the way both points can be away from the centers of their hexes
+func (m HexMetric) AxisDistance(delta float64) float64 {
+	return math.Max(0, (math.Abs(delta)-2*m.reach)/m.step)
+}
+
+// KDItem is a value stored at a position in a KDTree
+type KDItem[T interface {
+	int64 | float64
+}, V any] struct {
+	Position Vector2D[T]
+	Value    V
+}
+
+// kdEntry is an item with its original index used for deterministic ordering
+type kdEntry[T interface {
+	int64 | float64
+}, V any] struct {
+	item  KDItem[T, V]
+	index int
+}
+
+// kdCandidate is a search result waiting to be returned
+type kdCandidate struct {
+	entry    int
+	distance float64
+}
+
+// KDTree is a static two dimensional tree for nearest neighbour queries.
+// Equal distances are resolved by the order of the items given to NewKDTree.
+type KDTree[T interface {
+	int64 | float64
+}, V any] struct {
+	entries []kdEntry[T, V]
+	metric  DistanceMetric[T]
+}
+
+// NewKDTree builds a balanced tree from the items using the metric
+func NewKDTree[T interface {
+	int64 | float64
+}, V any](items []KDItem[T, V], metric DistanceMetric[T]) *KDTree[T, V] {
+	entries := make([]kdEntry[T, V], len(items))
+	for i, item := range items {
+		entries[i] = kdEntry[T, V]{item: item, index: i}
+	}
+
+	tree := &KDTree[T, V]{entries: entries, metric: metric}
+	tree.build(0, len(entries), 0)
+	return tree
+}
+
+// Len returns the number of items in the tree
+func (tree *KDTree[T, V]) Len() int {
+	return len(tree.entries)
+}
+
+// Nearest returns the item closest to the position and its distance
+func (tree *KDTree[T, V]) Nearest(position Vector2D[T]) (KDItem[T, V], float64, bool) {
+	results := tree.search(position, 1, math.Inf(1))
+	if len(results) == 0 {
+		return KDItem[T, V]{}, 0, false
+	}
+	return tree.entries[results[0].entry].item, results[0].distance, true
+}
+
+// NearestK returns up to k items closest to the position, nearest first
+func (tree *KDTree[T, V]) NearestK(position Vector2D[T], k int) []KDItem[T, V] {
+	if k <= 0 {
+		return nil
+	}
+	return tree.items(tree.search(position, k, math.Inf(1)))
+}
+
+// Radius returns all items within the radius around the position, nearest first
+func (tree *KDTree[T, V]) Radius(position Vector2D[T], radius float64) []KDItem[T, V] {
+	return tree.items(tree.search(position, len(tree.entries), radius))
+}
+
+// build sorts the entries in the range so the median splits the range on the axis
+func (tree *KDTree[T, V]) build(lo, hi, depth int) {
+	if hi-lo <= 1 {
+		return
+	}
+
+	entries := tree.entries[lo:hi]
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := axisValue(entries[i].item.Position, depth), axisValue(entries[j].item.Position, depth)
+		if a != b {
+			return a < b
+		}
+		return entries[i].index < entries[j].index
+	})
+
+	mid := (lo + hi) / 2
+	tree.build(lo, mid, depth+1)
+	tree.build(mid+1, hi, depth+1)
+}
+
+// search collects up to k candidates within maxDistance ordered by distance
+func (tree *KDTree[T, V]) search(position Vector2D[T], k int, maxDistance float64) []kdCandidate {
+	var results []kdCandidate
+
+	// limit is the distance a candidate has to beat to be added
+	limit := func() float64 {
+		if len(results) < k {
+			return maxDistance
+		}
+		return results[len(results)-1].distance
+	}
+
+	var visit func(lo, hi, depth int)
+	visit = func(lo, hi, depth int) {
+		if lo >= hi {
+			return
+		}
+
+		mid := (lo + hi) / 2
+		entry := tree.entries[mid]
+		distance := tree.metric.Distance(position, entry.item.Position)
+		if distance <= limit() {
+			results = insertCandidate(results, kdCandidate{entry: mid, distance: distance}, tree.entries, k)
+		}
+
+		delta := axisValue(position, depth) - axisValue(entry.item.Position, depth)
+		if delta < 0 {
+			visit(lo, mid, depth+1)
+			if tree.metric.AxisDistance(delta) <= limit() {
+				visit(mid+1, hi, depth+1)
+			}
+		} else {
+			visit(mid+1, hi, depth+1)
+			if tree.metric.AxisDistance(delta) <= limit() {
+				visit(lo, mid, depth+1)
+			}
+		}
+	}
+	visit(0, len(tree.entries), 0)
+
+	return results
+}
+
+// items returns the items of the candidates
+func (tree *KDTree[T, V]) items(candidates []kdCandidate) []KDItem[T, V] {
+	results := make([]KDItem[T, V], len(candidates))
+	for i, candidate := range candidates {
+		results[i] = tree.entries[candidate.entry].item
+	}
+	return results
+}
+
+// insertCandidate adds the candidate in distance order and keeps at most k
+func insertCandidate[T interface {
+	int64 | float64
+}, V any](results []kdCandidate, candidate kdCandidate, entries []kdEntry[T, V], k int) []kdCandidate {
+	i := sort.Search(len(results), func(i int) bool {
+		if results[i].distance != candidate.distance {
+			return results[i].distance > candidate.distance
+		}
+		return entries[results[i].entry].index > entries[candidate.entry].index
+	})
+	if i >= k {
+		return results
+	}
+
+	results = append(results, kdCandidate{})
+	copy(results[i+1:], results[i:])
+	results[i] = candidate
+	if len(results) > k {
+		results = results[:k]
+	}
+	return results
+}
+
+// axisValue returns the X coordinate on even depths and the Y coordinate on odd depths
+func axisValue[T interface {
+	int64 | float64
+}](p Vector2D[T], depth int) float64 {
+	if depth%2 == 0 {
+		return float64(p.X)
+	}
+	return float64(p.Y)
+}
@@ -0,0 +1,141 @@
+package maths
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// bruteKNearest returns the k nearest items sorted by distance and original order
+func bruteKNearest[T interface {
+	int64 | float64
+}, V any](items []KDItem[T, V], metric DistanceMetric[T], p Vector2D[T], k int, radius float64) []KDItem[T, V] {
+	indices := make([]int, 0, len(items))
+	for i := range items {
+		if metric.Distance(p, items[i].Position) <= radius {
+			indices = append(indices, i)
+		}
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		return metric.Distance(p, items[indices[i]].Position) < metric.Distance(p, items[indices[j]].Position)
+	})
+
+	results := make([]KDItem[T, V], 0, k)
+	for _, i := range indices[:min(k, len(indices))] {
+		results = append(results, items[i])
+	}
+	return results
+}
+
+func TestKDTreeEuclidean(t *testing.T) {
+	t.Parallel()
+
+	bounds := NewRect(NewVector2D[float64](-100, -100), NewVector2D[float64](100, 100))
+	var items []KDItem[float64, int]
+	for i, p := range testPoints(400, bounds) {
+		items = append(items, KDItem[float64, int]{Position: p, Value: i})
+	}
+	metric := EuclideanMetric[float64]{}
+	tree := NewKDTree(items, metric)
+	assert.Equal(t, 400, tree.Len())
+
+	for _, p := range testPoints(20, bounds.Expand(20)) {
+		nearest, distance, ok := tree.Nearest(p)
+		assert.True(t, ok)
+		assert.Equal(t, bruteKNearest(items, metric, p, 1, distance)[0], nearest)
+		assert.Equal(t, bruteKNearest(items, metric, p, 6, 1e9), tree.NearestK(p, 6))
+		assert.Equal(t, bruteKNearest(items, metric, p, len(items), 15), tree.Radius(p, 15))
+	}
+}
+
+func TestKDTreeManhattanInt(t *testing.T) {
+	t.Parallel()
+
+	// a regular grid produces many equal distances
+	var items []KDItem[int64, string]
+	for x := int64(-5); x <= 5; x++ {
+		for y := int64(-5); y <= 5; y++ {
+			items = append(items, KDItem[int64, string]{Position: NewVector2D(x, y), Value: NewVector2D(x, y).String()})
+		}
+	}
+	metric := ManhattanMetric[int64]{}
+	tree := NewKDTree(items, metric)
+
+	p := NewVector2D[int64](1, 2)
+	assert.Equal(t, bruteKNearest(items, metric, p, 9, 1e9), tree.NearestK(p, 9))
+	assert.Equal(t, bruteKNearest(items, metric, p, len(items), 3), tree.Radius(p, 3))
+	assert.Len(t, tree.Radius(p, 1), 5)
+	assert.Empty(t, tree.NearestK(p, 0))
+}
+
+func TestKDTreeHexMetric(t *testing.T) {
+	t.Parallel()
+
+	layout := NewHexLayout(LayoutPointy, NewVector2D[float64](10, 10), NewVector2D[float64](0, 0), 1)
+	metric := NewHexMetric(layout)
+
+	var items []KDItem[float64, Hex[int64]]
+	for _, hex := range NewHex[int64](0, 0).Spiral(8) {
+		if (hex.Q*7+hex.R*3)%4 != 0 {
+			continue
+		}
+		items = append(items, KDItem[float64, Hex[int64]]{Position: layout.HexToVector2D(hex.ToFloat()), Value: hex})
+	}
+	tree := NewKDTree(items, metric)
+
+	for _, hex := range NewHex[int64](1, -2).Spiral(3) {
+		p := layout.HexToVector2D(hex.ToFloat())
+		nearest, distance, ok := tree.Nearest(p)
+		assert.True(t, ok)
+		assert.Equal(t, hex.Distance(nearest.Value), distance)
+		assert.Equal(t, bruteKNearest(items, metric, p, 4, 1e9), tree.NearestK(p, 4))
+		assert.Equal(t, bruteKNearest(items, metric, p, len(items), 2), tree.Radius(p, 2))
+	}
+
+	// Points off the hex centers are measured from the hex containing them
+	for _, p := range testPoints(200, NewRect(NewVector2D[float64](-60, -60), NewVector2D[float64](60, 60))) {
+		assert.Equal(t, bruteKNearest(items, metric, p, 4, 1e9), tree.NearestK(p, 4), p)
+		assert.Equal(t, bruteKNearest(items, metric, p, len(items), 2), tree.Radius(p, 2), p)
+	}
+	corner := layout.HexToVector2D(Hex[float64]{Q: 0.4, R: 0.4})
+	assert.Equal(t, 1.0, metric.Distance(layout.HexToVector2D(Hex[float64]{}), corner))
+	assert.Equal(t, 0.0, metric.Distance(layout.HexToVector2D(Hex[float64]{Q: 0, R: 1}), corner))
+}
+
+func TestKDTreeEmpty(t *testing.T) {
+	t.Parallel()
+
+	tree := NewKDTree[float64, int](nil, EuclideanMetric[float64]{})
+	_, _, ok := tree.Nearest(NewVector2D[float64](0, 0))
+	assert.False(t, ok)
+	assert.Empty(t, tree.Radius(NewVector2D[float64](0, 0), 10))
+}
+
+func BenchmarkKDTree(b *testing.B) {
+	bounds := NewRect(NewVector2D[float64](0, 0), NewVector2D[float64](1000, 1000))
+	points := testPoints(10000, bounds)
+	items := make([]KDItem[float64, int], len(points))
+	for i, p := range points {
+		items[i] = KDItem[float64, int]{Position: p, Value: i}
+	}
+	tree := NewKDTree(items, EuclideanMetric[float64]{})
+	center := NewVector2D[float64](500, 500)
+
+	b.Run("Nearest/KDTree", func(b *testing.B) {
+		for b.Loop() {
+			tree.Nearest(center)
+		}
+	})
+	b.Run("Nearest/BruteForce", func(b *testing.B) {
+		for b.Loop() {
+			best := -1
+			bestDistance := 0.0
+			for i, p := range points {
+				if d := p.Distance(center); best < 0 || d < bestDistance {
+					best, bestDistance = i, d
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,116 @@
+package maths
+
+// looseQuadTreeLooseness makes the loose bounds of every node twice its size
+const looseQuadTreeLooseness = 1
+
+// LooseQuadTree is a quadtree for objects with an extent. Every node accepts
+// objects overlapping its bounds by up to half its size, so moving objects
+// rarely have to change their node. Objects too large for any child and
+// objects reaching outside the tree stay in the root.
+type LooseQuadTree[V comparable] struct {
+	index quadIndex[V]
+}
+
+// NewLooseQuadTree creates a new loose quadtree covering the bounds.
+// A node is split once it holds more than capacity values and has not reached maxDepth.
+func NewLooseQuadTree[V comparable](bounds Rect, capacity, maxDepth int) *LooseQuadTree[V] {
+	return &LooseQuadTree[V]{index: newQuadIndex[V](bounds, capacity, maxDepth, looseQuadTreeLooseness)}
+}
+
+// Bounds returns the area covered by the quadtree
+func (tree *LooseQuadTree[V]) Bounds() Rect {
+	return tree.index.root.bounds
+}
+
+// Len returns the number of values in the quadtree
+func (tree *LooseQuadTree[V]) Len() int {
+	return tree.index.root.count
+}
+
+// ValueBounds returns the bounds of a value
+func (tree *LooseQuadTree[V]) ValueBounds(value V) (Rect, bool) {
+	entry, ok := tree.index.entry(value)
+	return entry.Bounds, ok
+}
+
+// Insert adds a value with the bounds. It returns false when the center of the
+// bounds is outside of the quadtree or the value is already stored.
+func (tree *LooseQuadTree[V]) Insert(value V, bounds Rect) bool {
+	return tree.index.add(quadEntry[V]{Value: value, Bounds: bounds})
+}
+
+// Remove deletes a value from the quadtree
+func (tree *LooseQuadTree[V]) Remove(value V) bool {
+	return tree.index.remove(value)
+}
+
+// Update moves a value to new bounds. It returns false when the value is
+// unknown or the center of the bounds is outside of the quadtree.
+func (tree *LooseQuadTree[V]) Update(value V, bounds Rect) bool {
+	return tree.index.update(value, bounds)
+}
+
+// Clear removes all values from the quadtree
+func (tree *LooseQuadTree[V]) Clear() {
+	tree.index.clear()
+}
+
+// QueryRect returns all values overlapping the area
+func (tree *LooseQuadTree[V]) QueryRect(area Rect) []V {
+	var results []V
+	tree.index.walk(
+		func(node *quadNode[V]) bool { return node.loose.Intersects(area) },
+		func(entry quadEntry[V]) {
+			if entry.Bounds.Intersects(area) {
+				results = append(results, entry.Value)
+			}
+		},
+	)
+	return results
+}
+
+// QueryCircle returns all values overlapping the circle
+func (tree *LooseQuadTree[V]) QueryCircle(center Vector2D[float64], radius float64) []V {
+	var results []V
+	tree.index.walk(
+		func(node *quadNode[V]) bool { return node.loose.IntersectsCircle(center, radius) },
+		func(entry quadEntry[V]) {
+			if entry.Bounds.IntersectsCircle(center, radius) {
+				results = append(results, entry.Value)
+			}
+		},
+	)
+	return results
+}
+
+// Nearest returns up to k values whose bounds are closest to the position, nearest first
+func (tree *LooseQuadTree[V]) Nearest(position Vector2D[float64], k int) []V {
+	return tree.index.nearest(position, k, func(entry quadEntry[V]) float64 {
+		return entry.Bounds.DistanceTo(position)
+	})
+}
+
+// QueryRay returns all values hit by the ray from origin along direction up to
+// maxDistance, ordered by the distance at which the ray enters them
+func (tree *LooseQuadTree[V]) QueryRay(
+	origin Vector2D[float64],
+	direction Vector2D[float64],
+	maxDistance float64,
+) []V {
+	dir := direction.Normalize()
+	var hits []rayHit[V]
+
+	tree.index.walk(
+		func(node *quadNode[V]) bool {
+			_, ok := node.loose.IntersectRay(origin, dir, maxDistance)
+			return ok
+		},
+		func(entry quadEntry[V]) {
+			if t, ok := entry.Bounds.IntersectRay(origin, dir, maxDistance); ok {
+				hits = append(hits, rayHit[V]{value: entry.Value, distance: t})
+			}
+		},
+	)
+
+	return sortRayHits(hits)
+}
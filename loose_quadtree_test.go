@@ -0,0 +1,172 @@
+package maths
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLooseQuadTree(t *testing.T) {
+	t.Parallel()
+
+	bounds := NewRect(NewVector2D[float64](0, 0), NewVector2D[float64](100, 100))
+	tree := NewLooseQuadTree[int](bounds, 2, 6)
+
+	objects := make([]Rect, 0, 200)
+	for i, p := range testPoints(200, bounds) {
+		size := NewVector2D(1+float64(i%5), 1+float64(i%3))
+		objects = append(objects, NewRectFromCenter(p, size))
+		assert.True(t, tree.Insert(i, objects[i]))
+	}
+	assert.False(t, tree.Insert(0, objects[0]))
+	assert.True(t, tree.Insert(1000, NewRectFromCenter(NewVector2D[float64](50, 50), NewVector2D[float64](80, 80))))
+	objects = append(objects, NewRectFromCenter(NewVector2D[float64](50, 50), NewVector2D[float64](80, 80)))
+
+	// move objects around to exercise in place and relocating updates
+	for i := 0; i < len(objects)-1; i += 3 {
+		objects[i] = NewRectFromCenter(NewVector2D(math.Mod(objects[i].Center().X*7, 100), objects[i].Center().Y), objects[i].Size().Divide(2))
+		assert.True(t, tree.Update(i, objects[i]))
+	}
+	for i := 1; i < len(objects)-1; i += 10 {
+		assert.True(t, tree.Remove(i))
+	}
+	assert.Equal(t, 181, tree.Len())
+
+	value := func(i int) int {
+		if i == len(objects)-1 {
+			return 1000
+		}
+		return i
+	}
+	removed := func(i int) bool { return i%10 == 1 && i != len(objects)-1 }
+
+	area := NewRect(NewVector2D[float64](30, 30), NewVector2D[float64](45, 60))
+	center := NewVector2D[float64](70, 20)
+	origin := NewVector2D[float64](0, 33)
+	var expectedRect, expectedCircle []int
+	type hit struct {
+		value    int
+		distance float64
+	}
+	var hits []hit
+	for i, bounds := range objects {
+		if removed(i) {
+			continue
+		}
+		if bounds.Intersects(area) {
+			expectedRect = append(expectedRect, value(i))
+		}
+		if bounds.IntersectsCircle(center, 6) {
+			expectedCircle = append(expectedCircle, value(i))
+		}
+		if d, ok := bounds.IntersectRay(origin, NewVector2D[float64](1, 0), 100); ok {
+			hits = append(hits, hit{value(i), d})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].distance < hits[j].distance })
+
+	assert.ElementsMatch(t, expectedRect, tree.QueryRect(area))
+	assert.ElementsMatch(t, expectedCircle, tree.QueryCircle(center, 6))
+
+	ray := tree.QueryRay(origin, NewVector2D[float64](1, 0), 100)
+	assert.Len(t, ray, len(hits))
+	for i, value := range ray {
+		b, ok := tree.ValueBounds(value)
+		assert.True(t, ok)
+		d, _ := b.IntersectRay(origin, NewVector2D[float64](1, 0), 100)
+		assert.InDelta(t, hits[i].distance, d, 1e-9)
+	}
+
+	nearest := tree.Nearest(NewVector2D[float64](95, 5), 5)
+	assert.Len(t, nearest, 5)
+	last := 0.0
+	for _, value := range nearest {
+		b, _ := tree.ValueBounds(value)
+		d := b.DistanceTo(NewVector2D[float64](95, 5))
+		assert.GreaterOrEqual(t, d, last)
+		last = d
+	}
+}
+
+func BenchmarkLooseQuadTree(b *testing.B) {
+	bounds := NewRect(NewVector2D[float64](0, 0), NewVector2D[float64](1000, 1000))
+	area := NewRect(NewVector2D[float64](400, 400), NewVector2D[float64](450, 450))
+	center := NewVector2D[float64](500, 500)
+
+	for _, n := range []int{1000, 10000} {
+		// Boxes between 1 and 10 units wide around the points
+		boxes := make([]Rect, n)
+		for i, p := range testPoints(n, bounds) {
+			half := NewVector2D(0.5+float64(i%10)/2, 0.5+float64(i%7)/2)
+			boxes[i] = NewRect(p.Subtract(half), p.Add(half))
+		}
+
+		// Every benchmark builds its own tree as Update moves the boxes
+		newTree := func() *LooseQuadTree[int] {
+			tree := NewLooseQuadTree[int](bounds, 8, 12)
+			for i, box := range boxes {
+				tree.Insert(i, box)
+			}
+			return tree
+		}
+		moveBox := func(i int) Rect {
+			box := boxes[i%len(boxes)]
+			offset := NewVector2D(math.Mod(box.Min.X+float64(i), 990)-box.Min.X, 0)
+			return NewRect(box.Min.Add(offset), box.Max.Add(offset))
+		}
+
+		b.Run(fmt.Sprintf("QueryRect/LooseQuadTree/%d", n), func(b *testing.B) {
+			tree := newTree()
+			for b.Loop() {
+				tree.QueryRect(area)
+			}
+		})
+		b.Run(fmt.Sprintf("QueryRect/BruteForce/%d", n), func(b *testing.B) {
+			for b.Loop() {
+				var results []int
+				for i, box := range boxes {
+					if box.Intersects(area) {
+						results = append(results, i)
+					}
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("Nearest/LooseQuadTree/%d", n), func(b *testing.B) {
+			tree := newTree()
+			for b.Loop() {
+				tree.Nearest(center, 8)
+			}
+		})
+		b.Run(fmt.Sprintf("Nearest/BruteForce/%d", n), func(b *testing.B) {
+			indices := make([]int, n)
+			for b.Loop() {
+				for i := range indices {
+					indices[i] = i
+				}
+				sort.SliceStable(indices, func(i, j int) bool {
+					return boxes[indices[i]].DistanceTo(center) < boxes[indices[j]].DistanceTo(center)
+				})
+			}
+		})
+		b.Run(fmt.Sprintf("Update/LooseQuadTree/%d", n), func(b *testing.B) {
+			tree := newTree()
+			i := 0
+			for b.Loop() {
+				tree.Update(i%n, moveBox(i))
+				i++
+			}
+		})
+		b.Run(fmt.Sprintf("Update/BruteForce/%d", n), func(b *testing.B) {
+			moved := slices.Clone(boxes)
+			i := 0
+			for b.Loop() {
+				moved[i%n] = moveBox(i)
+				i++
+			}
+		})
+	}
+}
@@ -0,0 +1,470 @@
+package maths
+
+import (
+	"math"
+)
+
+// noiseOctaveOffset shifts the coordinates of every octave so octaves are not correlated at the origin
+const noiseOctaveOffset = 19.19
+
+var (
+	// noiseGradients2 are the unit gradients of the 2D Perlin noise
+	noiseGradients2 = [8]Vector2D[float64]{
+		{X: 1, Y: 0}, {X: -1, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: -1},
+		{X: math.Sqrt2 / 2, Y: math.Sqrt2 / 2}, {X: -math.Sqrt2 / 2, Y: math.Sqrt2 / 2},
+		{X: math.Sqrt2 / 2, Y: -math.Sqrt2 / 2}, {X: -math.Sqrt2 / 2, Y: -math.Sqrt2 / 2},
+	}
+	// noiseGradients3 are the cube edge gradients of the 3D Perlin noise
+	noiseGradients3 = [12][3]float64{
+		{1, 1, 0}, {-1, 1, 0}, {1, -1, 0}, {-1, -1, 0},
+		{1, 0, 1}, {-1, 0, 1}, {1, 0, -1}, {-1, 0, -1},
+		{0, 1, 1}, {0, -1, 1}, {0, 1, -1}, {0, -1, -1},
+	}
+)
+
+// Noise is a coherent noise function in two and three dimensions
+type Noise interface {
+	// Eval2 returns the noise value at a 2D position
+	Eval2(x, y float64) float64
+	// Eval3 returns the noise value at a 3D position
+	Eval3(x, y, z float64) float64
+}
+
+// noiseTable is a random permutation of 0..255 repeated twice to avoid wrapping indices
+type noiseTable [512]uint8
+
+// newNoiseTable creates a permutation table shuffled by the RNG
+func newNoiseTable(rng *RNG) noiseTable {
+	var table noiseTable
+	for i := 0; i < 256; i++ {
+		table[i] = uint8(i)
+	}
+	rng.Shuffle(256, func(i, j int) {
+		table[i], table[j] = table[j], table[i]
+	})
+	copy(table[256:], table[:256])
+	return table
+}
+
+// hash2 returns a pseudo random value in 0..255 for a 2D lattice point
+func (table *noiseTable) hash2(x, y int) int {
+	return int(table[int(table[x&255])+y&255])
+}
+
+// hash3 returns a pseudo random value in 0..255 for a 3D lattice point
+func (table *noiseTable) hash3(x, y, z int) int {
+	return int(table[int(table[int(table[x&255])+y&255])+z&255])
+}
+
+// Perlin is Ken Perlin's improved gradient noise with values in about [-1, 1]
+type Perlin struct {
+	table noiseTable
+}
+
+// NewPerlin creates a new Perlin noise seeded by the RNG
+func NewPerlin(rng *RNG) *Perlin {
+	return &Perlin{table: newNoiseTable(rng)}
+}
+
+// Eval2 returns the noise value at a 2D position
+func (noise *Perlin) Eval2(x, y float64) float64 {
+	x0, y0 := math.Floor(x), math.Floor(y)
+	fx, fy := x-x0, y-y0
+	ix, iy := int(x0), int(y0)
+
+	grad := func(dx, dy int) float64 {
+		g := noiseGradients2[noise.table.hash2(ix+dx, iy+dy)&7]
+		return g.X*(fx-float64(dx)) + g.Y*(fy-float64(dy))
+	}
+
+	u, v := quintic(fx), quintic(fy)
+	value := lerp(
+		lerp(grad(0, 0), grad(1, 0), u),
+		lerp(grad(0, 1), grad(1, 1), u),
+		v,
+	)
+
+	// Unit gradients reach at most sqrt(1/2)
+	return value * math.Sqrt2
+}
+
+// Eval3 returns the noise value at a 3D position
+func (noise *Perlin) Eval3(x, y, z float64) float64 {
+	x0, y0, z0 := math.Floor(x), math.Floor(y), math.Floor(z)
+	fx, fy, fz := x-x0, y-y0, z-z0
+	ix, iy, iz := int(x0), int(y0), int(z0)
+
+	grad := func(dx, dy, dz int) float64 {
+		g := noiseGradients3[noise.table.hash3(ix+dx, iy+dy, iz+dz)%12]
+		return g[0]*(fx-float64(dx)) + g[1]*(fy-float64(dy)) + g[2]*(fz-float64(dz))
+	}
+
+	u, v, w := quintic(fx), quintic(fy), quintic(fz)
+	return lerp(
+		lerp(
+			lerp(grad(0, 0, 0), grad(1, 0, 0), u),
+			lerp(grad(0, 1, 0), grad(1, 1, 0), u),
+			v,
+		),
+		lerp(
+			lerp(grad(0, 0, 1), grad(1, 0, 1), u),
+			lerp(grad(0, 1, 1), grad(1, 1, 1), u),
+			v,
+		),
+		w,
+	)
+}
+
+// OpenSimplex2 is KdotJPG's OpenSimplex2 gradient noise. 2D noise uses the triangular
+// A2 lattice and 3D noise a rotated body centered cubic lattice, both with evenly spread
+// gradients, so it has fewer axis aligned artifacts than Perlin noise. Values are in about [-1, 1].
+type OpenSimplex2 struct {
+	table noiseTable
+}
+
+const (
+	openSimplexSkew2      = 0.366025403784439    // (sqrt(3) - 1) / 2
+	openSimplexUnskew2    = -0.21132486540518713 // (1 / sqrt(3) - 1) / 2
+	openSimplexRotate3    = 2.0 / 3.0
+	openSimplexRadius2    = 0.5
+	openSimplexRadius3    = 0.6
+	openSimplexNormalize2 = 0.01001634121365712
+	openSimplexNormalize3 = 0.07969837668935331
+)
+
+var (
+	// openSimplexGradients2 are the 24 gradients of the 2D noise, eight at 22.5 + 45k degrees
+	// and sixteen at 7.5, 37.5, 52.5 and 82.5 degrees in every quadrant
+	openSimplexGradients2 = func() [24]Vector2D[float64] {
+		var gradients [24]Vector2D[float64]
+		for k := range 8 {
+			angle := (22.5 + 45*float64(k)) * math.Pi / 180
+			gradients[k] = NewVector2D(math.Cos(angle), math.Sin(angle)).Divide(openSimplexNormalize2)
+		}
+		for k := range 16 {
+			angle := ([4]float64{7.5, 37.5, 52.5, 82.5}[k%4] + 90*float64(k/4)) * math.Pi / 180
+			gradients[8+k] = NewVector2D(math.Cos(angle), math.Sin(angle)).Divide(openSimplexNormalize2)
+		}
+		return gradients
+	}()
+	// openSimplexGradients3 are the 48 gradients of the 3D noise, every axis order and
+	// sign of (a, a, 1) and (b, c, 0) which all have the same length
+	openSimplexGradients3 = func() [48][3]float64 {
+		const a, b, c = 2.22474487139, 3.0862664687972017, 1.1721513422464978
+		var gradients [48][3]float64
+		n := 0
+		for axis := range 3 {
+			for signs := range 8 {
+				var g [3]float64
+				for i := range 3 {
+					g[(axis+i)%3] = [3]float64{1, a, a}[i]
+					if signs&(1<<i) != 0 {
+						g[(axis+i)%3] *= -1
+					}
+				}
+				gradients[n] = g
+				n++
+			}
+			for _, pair := range [2][2]float64{{b, c}, {c, b}} {
+				for signs := range 4 {
+					var g [3]float64
+					g[(axis+1)%3], g[(axis+2)%3] = pair[0], pair[1]
+					if signs&1 != 0 {
+						g[(axis+1)%3] *= -1
+					}
+					if signs&2 != 0 {
+						g[(axis+2)%3] *= -1
+					}
+					gradients[n] = g
+					n++
+				}
+			}
+		}
+		for i := range gradients {
+			for j := range 3 {
+				gradients[i][j] /= openSimplexNormalize3
+			}
+		}
+		return gradients
+	}()
+)
+
+// NewOpenSimplex2 creates a new OpenSimplex2 noise seeded by the RNG
+func NewOpenSimplex2(rng *RNG) *OpenSimplex2 {
+	return &OpenSimplex2{table: newNoiseTable(rng)}
+}
+
+// Eval2 returns the noise value at a 2D position
+func (noise *OpenSimplex2) Eval2(x, y float64) float64 {
+	// Skew onto the A2 lattice and find the base of the rhombus containing the point
+	s := openSimplexSkew2 * (x + y)
+	xs, ys := x+s, y+s
+	xb, yb := math.Floor(xs), math.Floor(ys)
+	xi, yi := xs-xb, ys-yb
+	ix, iy := int(xb), int(yb)
+
+	t := (xi + yi) * openSimplexUnskew2
+	dx, dy := xi+t, yi+t
+
+	contribution := func(i, j int, dx, dy float64) float64 {
+		falloff := openSimplexRadius2 - dx*dx - dy*dy
+		if falloff <= 0 {
+			return 0
+		}
+		g := openSimplexGradients2[noise.table.hash2(i, j)%len(openSimplexGradients2)]
+		falloff *= falloff
+		return falloff * falloff * (g.X*dx + g.Y*dy)
+	}
+
+	// The two vertices shared by both triangles of the rhombus and the one of the triangle containing the point
+	value := contribution(ix, iy, dx, dy)
+	value += contribution(ix+1, iy+1, dx-(1+2*openSimplexUnskew2), dy-(1+2*openSimplexUnskew2))
+	if dy > dx {
+		value += contribution(ix, iy+1, dx-openSimplexUnskew2, dy-(1+openSimplexUnskew2))
+	} else {
+		value += contribution(ix+1, iy, dx-(1+openSimplexUnskew2), dy-openSimplexUnskew2)
+	}
+	return value
+}
+
+// Eval3 returns the noise value at a 3D position
+func (noise *OpenSimplex2) Eval3(x, y, z float64) float64 {
+	// Rotate so the diagonal of the cubic lattices points up, which gives a familiar look in XY slices
+	r := openSimplexRotate3 * (x + y + z)
+	p := [3]float64{r - x, r - y, r - z}
+
+	contribution := func(point [3]int, d [3]float64) float64 {
+		falloff := openSimplexRadius3 - d[0]*d[0] - d[1]*d[1] - d[2]*d[2]
+		if falloff <= 0 {
+			return 0
+		}
+		g := openSimplexGradients3[noise.table.hash3(point[0], point[1], point[2])%len(openSimplexGradients3)]
+		falloff *= falloff
+		return falloff * falloff * (g[0]*d[0] + g[1]*d[1] + g[2]*d[2])
+	}
+
+	// The BCC lattice is two cubic lattices offset by half a cell, lattice points are
+	// hashed in doubled coordinates so the points of both lattices are distinct
+	value := 0.0
+	for lattice := range 2 {
+		offset := 0.5 * float64(lattice)
+		var point [3]int
+		var d [3]float64
+		for i := range p {
+			base := math.Round(p[i] - offset)
+			d[i] = p[i] - offset - base
+			point[i] = 2*int(base) + lattice
+		}
+		value += contribution(point, d)
+
+		// The second closest point is the neighbor along the axis furthest from the closest point
+		axis := 0
+		if math.Abs(d[1]) > math.Abs(d[axis]) {
+			axis = 1
+		}
+		if math.Abs(d[2]) > math.Abs(d[axis]) {
+			axis = 2
+		}
+		sign := 1
+		if d[axis] < 0 {
+			sign = -1
+		}
+		point[axis] += 2 * sign
+		d[axis] -= float64(sign)
+		value += contribution(point, d)
+	}
+	return value
+}
+
+// ValueNoise interpolates random values at the lattice points, values are in [-1, 1]
+type ValueNoise struct {
+	table  noiseTable
+	values [256]float64
+}
+
+// NewValueNoise creates a new value noise seeded by the RNG
+func NewValueNoise(rng *RNG) *ValueNoise {
+	noise := &ValueNoise{table: newNoiseTable(rng)}
+	for i := range noise.values {
+		noise.values[i] = rng.Float64Range(-1, 1)
+	}
+	return noise
+}
+
+// Eval2 returns the noise value at a 2D position
+func (noise *ValueNoise) Eval2(x, y float64) float64 {
+	x0, y0 := math.Floor(x), math.Floor(y)
+	ix, iy := int(x0), int(y0)
+	value := func(dx, dy int) float64 {
+		return noise.values[noise.table.hash2(ix+dx, iy+dy)]
+	}
+
+	u, v := quintic(x-x0), quintic(y-y0)
+	return lerp(
+		lerp(value(0, 0), value(1, 0), u),
+		lerp(value(0, 1), value(1, 1), u),
+		v,
+	)
+}
+
+// Eval3 returns the noise value at a 3D position
+func (noise *ValueNoise) Eval3(x, y, z float64) float64 {
+	x0, y0, z0 := math.Floor(x), math.Floor(y), math.Floor(z)
+	ix, iy, iz := int(x0), int(y0), int(z0)
+	value := func(dx, dy, dz int) float64 {
+		return noise.values[noise.table.hash3(ix+dx, iy+dy, iz+dz)]
+	}
+
+	u, v, w := quintic(x-x0), quintic(y-y0), quintic(z-z0)
+	return lerp(
+		lerp(
+			lerp(value(0, 0, 0), value(1, 0, 0), u),
+			lerp(value(0, 1, 0), value(1, 1, 0), u),
+			v,
+		),
+		lerp(
+			lerp(value(0, 0, 1), value(1, 0, 1), u),
+			lerp(value(0, 1, 1), value(1, 1, 1), u),
+			v,
+		),
+		w,
+	)
+}
+
+// Fractal configures how octaves of a source noise are summed up. Every octave
+// multiplies the frequency by Lacunarity and the amplitude by Gain.
+type Fractal struct {
+	Source     Noise
+	Octaves    int
+	Lacunarity float64
+	Gain       float64
+}
+
+// NewFractal creates a new fractal with lacunarity 2 and gain 0.5
+func NewFractal(source Noise, octaves int) Fractal {
+	return Fractal{Source: source, Octaves: octaves, Lacunarity: 2, Gain: 0.5}
+}
+
+// sum adds the shaped octaves and normalizes the result by the total amplitude
+func (f Fractal) sum(sample func(frequency, offset float64) float64, shape func(float64) float64) float64 {
+	total, amplitude, frequency, weight := 0.0, 1.0, 1.0, 0.0
+	for octave := 0; octave < max(f.Octaves, 1); octave++ {
+		total += amplitude * shape(sample(frequency, float64(octave)*noiseOctaveOffset))
+		weight += amplitude
+		amplitude *= f.Gain
+		frequency *= f.Lacunarity
+	}
+	return total / weight
+}
+
+// sum2 sums the shaped octaves at a 2D position
+func (f Fractal) sum2(x, y float64, shape func(float64) float64) float64 {
+	return f.sum(func(frequency, offset float64) float64 {
+		return f.Source.Eval2(x*frequency+offset, y*frequency+offset)
+	}, shape)
+}
+
+// sum3 sums the shaped octaves at a 3D position
+func (f Fractal) sum3(x, y, z float64, shape func(float64) float64) float64 {
+	return f.sum(func(frequency, offset float64) float64 {
+		return f.Source.Eval3(x*frequency+offset, y*frequency+offset, z*frequency+offset)
+	}, shape)
+}
+
+// FBm is fractal Brownian motion, the plain sum of octaves with values in the range of the source
+type FBm struct {
+	Fractal
+}
+
+// Eval2 returns the noise value at a 2D position
+func (f FBm) Eval2(x, y float64) float64 {
+	return f.sum2(x, y, identity)
+}
+
+// Eval3 returns the noise value at a 3D position
+func (f FBm) Eval3(x, y, z float64) float64 {
+	return f.sum3(x, y, z, identity)
+}
+
+// Ridged sums inverted absolute octaves, producing sharp ridges with values in [0, 1]
+type Ridged struct {
+	Fractal
+}
+
+// Eval2 returns the noise value at a 2D position
+func (f Ridged) Eval2(x, y float64) float64 {
+	return f.sum2(x, y, ridge)
+}
+
+// Eval3 returns the noise value at a 3D position
+func (f Ridged) Eval3(x, y, z float64) float64 {
+	return f.sum3(x, y, z, ridge)
+}
+
+// Turbulence sums absolute octaves, producing billowy shapes with values in [0, 1]
+type Turbulence struct {
+	Fractal
+}
+
+// Eval2 returns the noise value at a 2D position
+func (f Turbulence) Eval2(x, y float64) float64 {
+	return f.sum2(x, y, math.Abs)
+}
+
+// Eval3 returns the noise value at a 3D position
+func (f Turbulence) Eval3(x, y, z float64) float64 {
+	return f.sum3(x, y, z, math.Abs)
+}
+
+// DomainWarp distorts the position by Warp noise scaled by Strength before sampling Source
+type DomainWarp struct {
+	Source   Noise
+	Warp     Noise
+	Strength float64
+}
+
+// Eval2 returns the noise value at a 2D position
+func (d DomainWarp) Eval2(x, y float64) float64 {
+	wx := d.Warp.Eval2(x, y)
+	wy := d.Warp.Eval2(x+5.2, y+1.3)
+	return d.Source.Eval2(x+d.Strength*wx, y+d.Strength*wy)
+}
+
+// Eval3 returns the noise value at a 3D position
+func (d DomainWarp) Eval3(x, y, z float64) float64 {
+	wx := d.Warp.Eval3(x, y, z)
+	wy := d.Warp.Eval3(x+5.2, y+1.3, z+2.8)
+	wz := d.Warp.Eval3(x+9.1, y+4.7, z+7.4)
+	return d.Source.Eval3(x+d.Strength*wx, y+d.Strength*wy, z+d.Strength*wz)
+}
+
+// NoiseHexMap samples the noise at the world position of every hex center scaled by frequency
+func NoiseHexMap(noise Noise, layout HexLayout, hexes []Hex[int64], frequency float64) HexMap[float64] {
+	result := make(HexMap[float64], len(hexes))
+	for _, h := range hexes {
+		p := layout.HexToVector2D(h.ToFloat())
+		result[h] = noise.Eval2(p.X*frequency, p.Y*frequency)
+	}
+	return result
+}
+
+// lerp interpolates linearly between a and b
+func lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+// quintic is the smooth interpolation curve 6t^5 - 15t^4 + 10t^3
+func quintic(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+// identity returns the value unchanged
+func identity(v float64) float64 {
+	return v
+}
+
+// ridge turns a noise value into a ridge peaking at zero
+func ridge(v float64) float64 {
+	r := 1 - math.Abs(v)
+	return r * r
+}
@@ -0,0 +1,125 @@
+package maths
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoise(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		create   func(rng *RNG) Noise
+		min, max float64
+	}{
+		{name: "Perlin", create: func(rng *RNG) Noise { return NewPerlin(rng) }, min: -1.05, max: 1.05},
+		{name: "OpenSimplex2", create: func(rng *RNG) Noise { return NewOpenSimplex2(rng) }, min: -1.05, max: 1.05},
+		{name: "Value", create: func(rng *RNG) Noise { return NewValueNoise(rng) }, min: -1, max: 1},
+		{name: "FBm", create: func(rng *RNG) Noise { return FBm{NewFractal(NewPerlin(rng), 5)} }, min: -1.05, max: 1.05},
+		{name: "Ridged", create: func(rng *RNG) Noise { return Ridged{NewFractal(NewOpenSimplex2(rng), 4)} }, min: 0, max: 1},
+		{name: "Turbulence", create: func(rng *RNG) Noise { return Turbulence{NewFractal(NewValueNoise(rng), 3)} }, min: 0, max: 1},
+		{name: "Domain warp", create: func(rng *RNG) Noise {
+			return DomainWarp{Source: NewPerlin(rng), Warp: NewOpenSimplex2(rng), Strength: 2}
+		}, min: -1.05, max: 1.05},
+	}
+
+	points := testPoints(500, NewRect(Vector2D[float64]{X: -20, Y: -20}, Vector2D[float64]{X: 20, Y: 20}))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			a, b, other := tt.create(NewRNG(1)), tt.create(NewRNG(1)), tt.create(NewRNG(2))
+			low, high := math.Inf(1), math.Inf(-1)
+			different := false
+			for i, p := range points {
+				z := float64(i%7) * 0.37
+				for _, v := range []float64{a.Eval2(p.X, p.Y), a.Eval3(p.X, p.Y, z)} {
+					low, high = math.Min(low, v), math.Max(high, v)
+				}
+
+				// Same seed gives the same values
+				assert.Equal(t, a.Eval2(p.X, p.Y), b.Eval2(p.X, p.Y))
+				assert.Equal(t, a.Eval3(p.X, p.Y, z), b.Eval3(p.X, p.Y, z))
+				different = different || a.Eval2(p.X, p.Y) != other.Eval2(p.X, p.Y)
+
+				// Noise is continuous
+				assert.InDelta(t, a.Eval2(p.X, p.Y), a.Eval2(p.X+1e-6, p.Y), 1e-3)
+				assert.InDelta(t, a.Eval3(p.X, p.Y, z), a.Eval3(p.X, p.Y, z+1e-6), 1e-3)
+			}
+
+			assert.True(t, different, "different seeds give different noise")
+			assert.GreaterOrEqual(t, low, tt.min)
+			assert.LessOrEqual(t, high, tt.max)
+			assert.Less(t, low+0.2, high, "noise is not constant")
+		})
+	}
+}
+
+func TestPerlinLattice(t *testing.T) {
+	t.Parallel()
+
+	noise := NewPerlin(NewRNG(3))
+	for x := -3; x <= 3; x++ {
+		for y := -3; y <= 3; y++ {
+			assert.Zero(t, noise.Eval2(float64(x), float64(y)))
+			assert.Zero(t, noise.Eval3(float64(x), float64(y), 1))
+		}
+	}
+}
+
+func TestNoiseHexMap(t *testing.T) {
+	t.Parallel()
+
+	noise := NewOpenSimplex2(NewRNG(4))
+	layout := NewHexLayout(LayoutPointy, Vector2D[float64]{X: 10, Y: 10}, Vector2D[float64]{}, 1)
+	hexes := Hex[int64]{}.Spiral(3)
+	result := NoiseHexMap(noise, layout, hexes, 0.05)
+
+	assert.Len(t, result, len(hexes))
+	for _, h := range hexes {
+		p := layout.HexToVector2D(h.ToFloat())
+		assert.Equal(t, noise.Eval2(p.X*0.05, p.Y*0.05), result[h])
+	}
+}
+
+func TestOpenSimplex2(t *testing.T) {
+	t.Parallel()
+
+	// The gradients are distinct and spread evenly, all with the same length
+	distinct2 := map[Vector2D[float64]]bool{}
+	for _, g := range openSimplexGradients2 {
+		assert.InDelta(t, 1/openSimplexNormalize2, g.Length(), 1e-9)
+		distinct2[g] = true
+	}
+	assert.Len(t, distinct2, len(openSimplexGradients2))
+
+	length3 := func(g [3]float64) float64 { return math.Sqrt(g[0]*g[0] + g[1]*g[1] + g[2]*g[2]) }
+	distinct3 := map[[3]float64]bool{}
+	for _, g := range openSimplexGradients3 {
+		assert.InDelta(t, length3(openSimplexGradients3[0]), length3(g), 1e-6)
+		distinct3[g] = true
+	}
+	assert.Len(t, distinct3, len(openSimplexGradients3))
+
+	// A dense sample uses most of the range without leaving it
+	noise := NewOpenSimplex2(NewRNG(5))
+	low2, high2, low3, high3 := math.Inf(1), math.Inf(-1), math.Inf(1), math.Inf(-1)
+	for x := 0.0; x < 30; x += 0.07 {
+		for y := 0.0; y < 30; y += 0.07 {
+			v2, v3 := noise.Eval2(x, y), noise.Eval3(x, y, x*0.3)
+			low2, high2 = math.Min(low2, v2), math.Max(high2, v2)
+			low3, high3 = math.Min(low3, v3), math.Max(high3, v3)
+		}
+	}
+	for _, v := range []float64{low2, low3} {
+		assert.Less(t, v, -0.7)
+		assert.GreaterOrEqual(t, v, -1.05)
+	}
+	for _, v := range []float64{high2, high3} {
+		assert.Greater(t, v, 0.7)
+		assert.LessOrEqual(t, v, 1.05)
+	}
+}
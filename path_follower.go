@@ -0,0 +1,174 @@
+package maths
+
+import (
+	"math"
+	"sort"
+)
+
+// pathArcIntervals is the number of lookup intervals of every curved path segment
+const pathArcIntervals = 8
+
+// PathFollower moves a position along a polyline or spline at constant speed.
+// Segment i of a path runs from point i to point i+1 of the input, so for
+// hex paths the segment tells between which hexes the position is.
+type PathFollower struct {
+	start    Vector2D[float64]
+	segments []Curve
+	arcs     []*ArcLength
+	offsets  []float64
+	distance float64
+}
+
+// NewPolylineFollower creates a new follower moving along straight lines through the points
+func NewPolylineFollower(points []Vector2D[float64]) *PathFollower {
+	follower := &PathFollower{offsets: []float64{0}}
+	if len(points) > 0 {
+		follower.start = points[0]
+	}
+	for i := 1; i < len(points); i++ {
+		follower.add(lineCurve{A: points[i-1], B: points[i]}, 1)
+	}
+	return follower
+}
+
+// NewSplineFollower creates a new follower moving along the segments of the spline
+func NewSplineFollower(spline *Spline) *PathFollower {
+	follower := &PathFollower{offsets: []float64{0}}
+	for _, segment := range spline.Segments {
+		follower.add(segment, pathArcIntervals)
+	}
+	if len(spline.Segments) > 0 {
+		follower.start = spline.Segments[0].P0
+	}
+	return follower
+}
+
+// NewHexPathFollower creates a new follower through the centers of the hexes,
+// smoothed by a Catmull-Rom spline when smooth is set
+func NewHexPathFollower(layout HexLayout, path []Hex[int64], smooth bool) *PathFollower {
+	if smooth {
+		return NewSplineFollower(HexPathSpline(layout, path))
+	}
+
+	points := make([]Vector2D[float64], len(path))
+	for i, h := range path {
+		points[i] = layout.HexToVector2D(h.ToFloat())
+	}
+	return NewPolylineFollower(points)
+}
+
+// add appends a segment to the path
+func (follower *PathFollower) add(segment Curve, intervals int) {
+	arc := NewArcLength(segment, intervals)
+	follower.segments = append(follower.segments, segment)
+	follower.arcs = append(follower.arcs, arc)
+	follower.offsets = append(follower.offsets, follower.Length()+arc.Length())
+}
+
+// Advance moves the position forward by distance, continuing around corners
+// onto the following segments. It returns the part of the distance which was
+// not used because the end of the path was reached. Negative distances move
+// backwards and stop at the start.
+func (follower *PathFollower) Advance(distance float64) float64 {
+	target := follower.distance + distance
+	follower.distance = math.Max(0, math.Min(follower.Length(), target))
+	return math.Max(0, target-follower.Length())
+}
+
+// SetDistance moves the position to the distance from the start of the path
+func (follower *PathFollower) SetDistance(distance float64) {
+	follower.distance = math.Max(0, math.Min(follower.Length(), distance))
+}
+
+// Distance returns the distance travelled from the start of the path
+func (follower *PathFollower) Distance() float64 {
+	return follower.distance
+}
+
+// Length returns the total length of the path
+func (follower *PathFollower) Length() float64 {
+	return follower.offsets[len(follower.offsets)-1]
+}
+
+// Remaining returns the distance left until the end of the path
+func (follower *PathFollower) Remaining() float64 {
+	return follower.Length() - follower.distance
+}
+
+// Finished reports whether the end of the path was reached
+func (follower *PathFollower) Finished() bool {
+	return follower.Remaining() <= 0
+}
+
+// Segment returns the index of the current segment, -1 for paths without segments
+func (follower *PathFollower) Segment() int {
+	n := len(follower.segments)
+	if n == 0 {
+		return -1
+	}
+
+	i := sort.Search(n, func(i int) bool {
+		return follower.offsets[i+1] > follower.distance
+	})
+	return min(i, n-1)
+}
+
+// Progress returns the travelled fraction of the current segment
+func (follower *PathFollower) Progress() float64 {
+	i := follower.Segment()
+	if i < 0 {
+		return 0
+	}
+
+	length := follower.offsets[i+1] - follower.offsets[i]
+	if length == 0 {
+		return 1
+	}
+	return (follower.distance - follower.offsets[i]) / length
+}
+
+// Waypoint returns the index of the path point closest along the path, for hex paths the current hex
+func (follower *PathFollower) Waypoint() int {
+	i := follower.Segment()
+	if i < 0 {
+		return 0
+	}
+	if follower.Progress() >= 0.5 {
+		return i + 1
+	}
+	return i
+}
+
+// Position returns the current position
+func (follower *PathFollower) Position() Vector2D[float64] {
+	i := follower.Segment()
+	if i < 0 {
+		return follower.start
+	}
+	return follower.arcs[i].Point(follower.distance - follower.offsets[i])
+}
+
+// Direction returns the normalized direction of movement at the current position
+func (follower *PathFollower) Direction() Vector2D[float64] {
+	i := follower.Segment()
+	if i < 0 {
+		return Vector2D[float64]{}
+	}
+	t := follower.arcs[i].Parameter(follower.distance - follower.offsets[i])
+	return curveTangent(follower.segments[i], t)
+}
+
+// lineCurve is a straight segment as Curve
+type lineCurve struct {
+	A, B Vector2D[float64]
+}
+
+// Point returns the position at t
+func (line lineCurve) Point(t float64) Vector2D[float64] {
+	return lerpVector(line.A, line.B, t)
+}
+
+// Derivative returns the constant direction of the line
+func (line lineCurve) Derivative(float64) Vector2D[float64] {
+	return line.B.Subtract(line.A)
+}
@@ -0,0 +1,89 @@
+package maths
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolylineFollower(t *testing.T) {
+	t.Parallel()
+
+	follower := NewPolylineFollower([]Vector2D[float64]{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 5}})
+	assert.InDelta(t, 15, follower.Length(), 1e-12)
+	assert.Equal(t, Vector2D[float64]{X: 0, Y: 0}, follower.Position())
+	assert.Equal(t, Vector2D[float64]{X: 1, Y: 0}, follower.Direction())
+
+	assert.Zero(t, follower.Advance(4))
+	assert.Equal(t, 0, follower.Segment())
+	assert.InDelta(t, 0.4, follower.Progress(), 1e-12)
+	assert.Equal(t, 0, follower.Waypoint())
+
+	// Overshooting the corner continues on the next segment
+	assert.Zero(t, follower.Advance(8))
+	assert.Equal(t, 2, follower.Segment())
+	assert.Equal(t, 2, follower.Waypoint())
+	assert.InDelta(t, 0, follower.Position().Distance(Vector2D[float64]{X: 10, Y: 2}), 1e-12)
+	assert.InDelta(t, 0, follower.Direction().Distance(Vector2D[float64]{X: 0, Y: 1}), 1e-12)
+	assert.InDelta(t, 3, follower.Remaining(), 1e-12)
+	assert.False(t, follower.Finished())
+
+	// Overshooting the end returns the unused distance
+	assert.InDelta(t, 2, follower.Advance(5), 1e-12)
+	assert.True(t, follower.Finished())
+	assert.Equal(t, 3, follower.Waypoint())
+	assert.Equal(t, Vector2D[float64]{X: 10, Y: 5}, follower.Position())
+
+	assert.Zero(t, follower.Advance(-20))
+	assert.Zero(t, follower.Distance())
+
+	follower.SetDistance(10)
+	assert.Equal(t, 1, follower.Waypoint())
+}
+
+func TestPathFollowerDegenerated(t *testing.T) {
+	t.Parallel()
+
+	single := NewPolylineFollower([]Vector2D[float64]{{X: 3, Y: 4}})
+	assert.InDelta(t, 1, single.Advance(1), 1e-12)
+	assert.Equal(t, Vector2D[float64]{X: 3, Y: 4}, single.Position())
+	assert.Equal(t, -1, single.Segment())
+	assert.True(t, single.Finished())
+
+	empty := NewSplineFollower(NewCatmullRom(nil))
+	assert.Zero(t, empty.Length())
+	assert.Equal(t, Vector2D[float64]{}, empty.Position())
+}
+
+func TestSplineFollower(t *testing.T) {
+	t.Parallel()
+
+	layout := NewHexLayout(LayoutFlat, Vector2D[float64]{X: 10, Y: 10}, Vector2D[float64]{}, 1)
+	path := []Hex[int64]{{Q: 0, R: 0}, {Q: 1, R: 0}, {Q: 1, R: 1}, {Q: 2, R: 1}, {Q: 3, R: 0}}
+	follower := NewHexPathFollower(layout, path, true)
+	spline := HexPathSpline(layout, path)
+	assert.InDelta(t, spline.Length(), follower.Length(), 1e-9)
+
+	// Constant speed, equal steps cover equal arc lengths
+	step := follower.Length() / 50
+	previous := follower.Position()
+	for !follower.Finished() {
+		follower.Advance(step)
+		current := follower.Position()
+		assert.InDelta(t, step, current.Distance(previous), step*0.01)
+		previous = current
+	}
+	assert.InDelta(t, 0, previous.Distance(layout.HexToVector2D(path[4].ToFloat())), 1e-9)
+
+	// The waypoint is the hex the position is closest to along the path
+	follower.SetDistance(0)
+	for i := range path {
+		for follower.Waypoint() < i {
+			follower.Advance(step / 4)
+		}
+		assert.Less(t, follower.Position().Distance(layout.HexToVector2D(path[i].ToFloat())), 10.0)
+	}
+
+	straight := NewHexPathFollower(layout, path, false)
+	assert.Less(t, straight.Length(), follower.Length())
+}
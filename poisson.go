@@ -0,0 +1,228 @@
+package maths
+
+import (
+	"math"
+)
+
+// poissonAttempts is the number of candidates tried around every active sample when none is given
+const poissonAttempts = 30
+
+// PoissonDisk returns blue noise samples inside the bounds with Bridson's
+// algorithm. No two samples are closer than radius and the bounds are typically
+// covered without gaps wider than 2*radius, more attempts make gaps less likely.
+// Attempts is the number of candidates tried around every sample, 30 when zero
+// or negative.
+func PoissonDisk(rng *RNG, bounds Rect, radius float64, attempts int) []Vector2D[float64] {
+	if radius <= 0 {
+		return nil
+	}
+
+	s := newPoissonSampler(rng, bounds, radius, func(Vector2D[float64]) float64 { return radius }, attempts)
+	s.run(rng.PointInRect(bounds))
+	return s.points
+}
+
+// PoissonDiskVariable returns blue noise samples whose spacing depends on the
+// position. Two samples are at least the larger of their radii apart. The
+// radius function is clamped to maxRadius and candidates where it is not
+// positive are rejected.
+func PoissonDiskVariable(
+	rng *RNG,
+	bounds Rect,
+	radius func(p Vector2D[float64]) float64,
+	maxRadius float64,
+	attempts int,
+) []Vector2D[float64] {
+	if maxRadius <= 0 {
+		return nil
+	}
+
+	s := newPoissonSampler(rng, bounds, maxRadius, radius, attempts)
+
+	// Retry the start so a zero radius at a random point does not end the sampling
+	for i := 0; i < s.attempts && len(s.points) == 0; i++ {
+		s.run(rng.PointInRect(bounds))
+	}
+	return s.points
+}
+
+// PoissonDiskHexes returns blue noise samples inside the union of the hexes of
+// the layout. Every hex is used as starting point when it has no sample yet, so
+// disconnected regions are filled as well.
+func PoissonDiskHexes(rng *RNG, layout HexLayout, hexes []Hex[int64], radius float64, attempts int) []Vector2D[float64] {
+	if radius <= 0 || len(hexes) == 0 {
+		return nil
+	}
+
+	region := NewHexMap(hexes, struct{}{})
+	var bounds Rect
+	for i, h := range region.Hexes() {
+		hexBounds := polygonBounds(layout.HexCorners(h.ToFloat()))
+		if i == 0 {
+			bounds = hexBounds
+		} else {
+			bounds = bounds.Union(hexBounds)
+		}
+	}
+
+	s := newPoissonSampler(rng, bounds, radius, func(Vector2D[float64]) float64 { return radius }, attempts)
+	s.inside = func(p Vector2D[float64]) bool {
+		rounded := layout.Vector2DToHex(p).Round().ToInt()
+		for _, h := range append(rounded.Neighbours(), rounded) {
+			if region.Has(h) && PointInPolygonEvenOdd(p, layout.HexCorners(h.ToFloat())) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, h := range region.Hexes() {
+		s.run(layout.HexToVector2D(h.ToFloat()))
+	}
+	return s.points
+}
+
+// PoissonHexes picks a random subset of the hexes so that no two picked hexes are
+// closer than minDistance and every other hex is closer than minDistance to a
+// picked hex. The result only depends on the RNG state and the set of hexes.
+func PoissonHexes(rng *RNG, hexes []Hex[int64], minDistance int) []Hex[int64] {
+	candidates := NewHexMap(hexes, struct{}{}).Hexes()
+	rng.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+
+	picked := make(HexMap[struct{}])
+	var result []Hex[int64]
+	for _, h := range candidates {
+		free := true
+		for _, other := range h.Spiral(minDistance - 1) {
+			if picked.Has(other) {
+				free = false
+				break
+			}
+		}
+		if free {
+			picked[h] = struct{}{}
+			result = append(result, h)
+		}
+	}
+	return result
+}
+
+// poissonSampler is the state of Bridson's algorithm with a bucket grid
+type poissonSampler struct {
+	rng       *RNG
+	bounds    Rect
+	radius    func(p Vector2D[float64]) float64
+	maxRadius float64
+	inside    func(p Vector2D[float64]) bool
+	attempts  int
+
+	columns, rows int
+	cells         [][]int
+	points        []Vector2D[float64]
+	radii         []float64
+}
+
+// newPoissonSampler creates a sampler with grid cells the size of the largest radius
+func newPoissonSampler(
+	rng *RNG,
+	bounds Rect,
+	maxRadius float64,
+	radius func(p Vector2D[float64]) float64,
+	attempts int,
+) *poissonSampler {
+	if attempts <= 0 {
+		attempts = poissonAttempts
+	}
+	columns := int(bounds.Width()/maxRadius) + 1
+	rows := int(bounds.Height()/maxRadius) + 1
+	return &poissonSampler{
+		rng:       rng,
+		bounds:    bounds,
+		radius:    radius,
+		maxRadius: maxRadius,
+		inside:    func(Vector2D[float64]) bool { return true },
+		attempts:  attempts,
+		columns:   columns,
+		rows:      rows,
+		cells:     make([][]int, columns*rows),
+	}
+}
+
+// cell returns the grid column and row of the point
+func (s *poissonSampler) cell(p Vector2D[float64]) (int, int) {
+	column := min(max(int((p.X-s.bounds.Min.X)/s.maxRadius), 0), s.columns-1)
+	row := min(max(int((p.Y-s.bounds.Min.Y)/s.maxRadius), 0), s.rows-1)
+	return column, row
+}
+
+// accepts reports whether a sample with the radius fits at the point
+func (s *poissonSampler) accepts(p Vector2D[float64], radius float64) bool {
+	if radius <= 0 || !s.bounds.Contains(p) || !s.inside(p) {
+		return false
+	}
+
+	column, row := s.cell(p)
+	for y := max(row-1, 0); y <= min(row+1, s.rows-1); y++ {
+		for x := max(column-1, 0); x <= min(column+1, s.columns-1); x++ {
+			for _, i := range s.cells[y*s.columns+x] {
+				if p.Distance(s.points[i]) < math.Max(radius, s.radii[i]) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// add stores a new sample and returns its index
+func (s *poissonSampler) add(p Vector2D[float64], radius float64) int {
+	column, row := s.cell(p)
+	index := len(s.points)
+	s.cells[row*s.columns+column] = append(s.cells[row*s.columns+column], index)
+	s.points = append(s.points, p)
+	s.radii = append(s.radii, radius)
+	return index
+}
+
+// run grows samples from the start point until no active sample is left
+func (s *poissonSampler) run(start Vector2D[float64]) {
+	radius := math.Min(s.radius(start), s.maxRadius)
+	if !s.accepts(start, radius) {
+		return
+	}
+	active := []int{s.add(start, radius)}
+
+	for len(active) > 0 {
+		k := s.rng.IntN(len(active))
+		p, r := s.points[active[k]], s.radii[active[k]]
+
+		found := false
+		for i := 0; i < s.attempts; i++ {
+			// Uniform in the annulus between r and 2r
+			distance := r * math.Sqrt(1+3*s.rng.Float64())
+			candidate := p.Add(s.rng.UnitVector().Multiply(distance))
+			candidateRadius := math.Min(s.radius(candidate), s.maxRadius)
+			if s.accepts(candidate, candidateRadius) {
+				active = append(active, s.add(candidate, candidateRadius))
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			active[k] = active[len(active)-1]
+			active = active[:len(active)-1]
+		}
+	}
+}
+
+// polygonBounds returns the bounding rectangle of the polygon
+func polygonBounds(polygon []Vector2D[float64]) Rect {
+	bounds := NewRect(polygon[0], polygon[0])
+	for _, p := range polygon[1:] {
+		bounds = bounds.Union(NewRect(p, p))
+	}
+	return bounds
+}
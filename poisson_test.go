@@ -0,0 +1,125 @@
+package maths
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPoissonDisk(t *testing.T) {
+	t.Parallel()
+
+	bounds := NewRect(Vector2D[float64]{X: -10, Y: 0}, Vector2D[float64]{X: 40, Y: 30})
+	points := PoissonDisk(NewRNG(1), bounds, 2, 0)
+
+	assert.Greater(t, len(points), 50)
+	for i, p := range points {
+		assert.True(t, bounds.Contains(p))
+		for _, q := range points[i+1:] {
+			assert.GreaterOrEqual(t, p.Distance(q), 2.0)
+		}
+	}
+
+	// The bounds are covered, every point is close to a sample
+	for _, p := range testPoints(300, bounds) {
+		_, distance := bruteNearestDistance(points, p)
+		assert.Less(t, distance, 4.0)
+	}
+
+	assert.Equal(t, points, PoissonDisk(NewRNG(1), bounds, 2, 0))
+	assert.Empty(t, PoissonDisk(NewRNG(1), bounds, 0, 0))
+}
+
+func TestPoissonDiskVariable(t *testing.T) {
+	t.Parallel()
+
+	bounds := NewRect(Vector2D[float64]{}, Vector2D[float64]{X: 40, Y: 20})
+	radius := func(p Vector2D[float64]) float64 {
+		return 1 + p.X/8
+	}
+	clamped := func(p Vector2D[float64]) float64 {
+		return math.Min(radius(p), 4)
+	}
+	points := PoissonDiskVariable(NewRNG(2), bounds, radius, 4, 0)
+
+	left, right := 0, 0
+	for i, p := range points {
+		assert.True(t, bounds.Contains(p))
+		for _, q := range points[i+1:] {
+			assert.GreaterOrEqual(t, p.Distance(q), math.Max(clamped(p), clamped(q)))
+		}
+		if p.X < 10 {
+			left++
+		} else if p.X >= 30 {
+			right++
+		}
+	}
+
+	// Smaller radii produce denser samples
+	assert.Greater(t, left, 2*right)
+}
+
+func TestPoissonDiskHexes(t *testing.T) {
+	t.Parallel()
+
+	layout := NewHexLayout(LayoutFlat, Vector2D[float64]{X: 10, Y: 10}, Vector2D[float64]{}, 1)
+
+	// Two disconnected regions
+	hexes := append(Hex[int64]{}.Spiral(2), Hex[int64]{Q: 10, R: 0}.Spiral(1)...)
+	region := NewHexMap(hexes, struct{}{})
+	points := PoissonDiskHexes(NewRNG(3), layout, hexes, 3, 0)
+
+	perHex := make(map[Hex[int64]]int)
+	for i, p := range points {
+		inside := false
+		for h := range region {
+			if PointInPolygonEvenOdd(p, layout.HexCorners(h.ToFloat())) {
+				inside = true
+				perHex[h]++
+			}
+		}
+		assert.True(t, inside, "%v is outside of the region", p)
+		for _, q := range points[i+1:] {
+			assert.GreaterOrEqual(t, p.Distance(q), 3.0)
+		}
+	}
+
+	// Every hex received samples
+	assert.Len(t, perHex, len(hexes))
+}
+
+func TestPoissonHexes(t *testing.T) {
+	t.Parallel()
+
+	hexes := Hex[int64]{}.Spiral(10)
+	picked := PoissonHexes(NewRNG(4), hexes, 3)
+
+	assert.NotEmpty(t, picked)
+	for i, a := range picked {
+		for _, b := range picked[i+1:] {
+			assert.GreaterOrEqual(t, a.Distance(b), 3.0)
+		}
+	}
+	for _, h := range hexes {
+		closest := math.Inf(1)
+		for _, p := range picked {
+			closest = math.Min(closest, h.Distance(p))
+		}
+		assert.Less(t, closest, 3.0)
+	}
+
+	assert.Equal(t, picked, PoissonHexes(NewRNG(4), hexes, 3))
+	assert.Len(t, PoissonHexes(NewRNG(4), hexes, 1), len(hexes))
+}
+
+// bruteNearestDistance returns the index and distance of the point closest to p
+func bruteNearestDistance(points []Vector2D[float64], p Vector2D[float64]) (int, float64) {
+	best, bestDistance := -1, math.Inf(1)
+	for i, q := range points {
+		if d := p.Distance(q); d < bestDistance {
+			best, bestDistance = i, d
+		}
+	}
+	return best, bestDistance
+}
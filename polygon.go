@@ -0,0 +1,330 @@
+package maths
+
+import (
+	"math"
+	"slices"
+)
+
+// Winding is the orientation of the vertices of a polygon. Counter-clockwise
+// refers to a Y-up coordinate system, on a Y-down screen it appears clockwise.
+type Winding int
+
+const (
+	// WindingNone is the winding of degenerated polygons without area
+	WindingNone Winding = iota
+	// WindingCounterClockwise polygons have a positive signed area
+	WindingCounterClockwise
+	// WindingClockwise polygons have a negative signed area
+	WindingClockwise
+)
+
+// offsetMiterLimit is the longest miter allowed at a polygon corner as multiple of the offset
+const offsetMiterLimit = 4.0
+
+// ConvexHull returns the convex hull of the points in counter-clockwise order
+// using Andrew's monotone chain. Collinear points on the hull are dropped and the
+// result is exact for int64 points.
+func ConvexHull[T interface {
+	int64 | float64
+}](points []Vector2D[T]) []Vector2D[T] {
+	sorted := slices.Clone(points)
+	slices.SortFunc(sorted, compareVector)
+	sorted = slices.Compact(sorted)
+	if len(sorted) < 3 {
+		return sorted
+	}
+
+	hull := make([]Vector2D[T], 0, 2*len(sorted))
+
+	// Lower hull
+	for _, p := range sorted {
+		for len(hull) >= 2 && orientation(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+
+	// Upper hull
+	lower := len(hull) + 1
+	for i := len(sorted) - 2; i >= 0; i-- {
+		p := sorted[i]
+		for len(hull) >= lower && orientation(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+
+	// The last point equals the first one
+	return hull[:len(hull)-1]
+}
+
+// SignedArea2 returns twice the signed area of the polygon, exact for int64 polygons.
+// It is positive for counter-clockwise polygons.
+func SignedArea2[T interface {
+	int64 | float64
+}](polygon []Vector2D[T]) T {
+	var area T
+	for i, a := range polygon {
+		b := polygon[(i+1)%len(polygon)]
+		area += a.Cross(b)
+	}
+	return area
+}
+
+// SignedArea returns the signed area of the polygon, positive for counter-clockwise polygons
+func SignedArea[T interface {
+	int64 | float64
+}](polygon []Vector2D[T]) float64 {
+	return float64(SignedArea2(polygon)) / 2
+}
+
+// Area returns the area of the polygon
+func Area[T interface {
+	int64 | float64
+}](polygon []Vector2D[T]) float64 {
+	return math.Abs(SignedArea(polygon))
+}
+
+// Centroid returns the center of mass of the polygon. Polygons without area
+// return the average of their vertices.
+func Centroid[T interface {
+	int64 | float64
+}](polygon []Vector2D[T]) Vector2D[float64] {
+	if len(polygon) == 0 {
+		return Vector2D[float64]{}
+	}
+
+	// Relative to the first vertex to reduce rounding errors
+	origin := polygon[0].ToFloat()
+	var cx, cy, area2 float64
+	for i := range polygon {
+		a := polygon[i].ToFloat().Subtract(origin)
+		b := polygon[(i+1)%len(polygon)].ToFloat().Subtract(origin)
+		cross := a.Cross(b)
+		area2 += cross
+		cx += (a.X + b.X) * cross
+		cy += (a.Y + b.Y) * cross
+	}
+
+	if area2 == 0 {
+		var sum Vector2D[float64]
+		for _, p := range polygon {
+			sum = sum.Add(p.ToFloat())
+		}
+		return sum.Divide(float64(len(polygon)))
+	}
+
+	return Vector2D[float64]{
+		X: origin.X + cx/(3*area2),
+		Y: origin.Y + cy/(3*area2),
+	}
+}
+
+// PolygonWinding returns the winding order of the polygon
+func PolygonWinding[T interface {
+	int64 | float64
+}](polygon []Vector2D[T]) Winding {
+	area := SignedArea2(polygon)
+	switch {
+	case area > 0:
+		return WindingCounterClockwise
+	case area < 0:
+		return WindingClockwise
+	default:
+		return WindingNone
+	}
+}
+
+// EnsureWinding returns the polygon with the requested winding, reversing a copy when needed
+func EnsureWinding[T interface {
+	int64 | float64
+}](polygon []Vector2D[T], winding Winding) []Vector2D[T] {
+	current := PolygonWinding(polygon)
+	if current == WindingNone || current == winding || winding == WindingNone {
+		return polygon
+	}
+
+	reversed := slices.Clone(polygon)
+	slices.Reverse(reversed)
+	return reversed
+}
+
+// PointInPolygonEvenOdd reports whether the point is inside the polygon using the
+// even-odd rule. The test is exact for int64 polygons, points on edges are undefined.
+func PointInPolygonEvenOdd[T interface {
+	int64 | float64
+}](p Vector2D[T], polygon []Vector2D[T]) bool {
+	inside := false
+	for i, a := range polygon {
+		b := polygon[(i+1)%len(polygon)]
+		if a.Y <= p.Y && b.Y > p.Y && orientation(a, b, p) > 0 {
+			inside = !inside
+		} else if a.Y > p.Y && b.Y <= p.Y && orientation(a, b, p) < 0 {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// PointInPolygonNonZero reports whether the point is inside the polygon using the
+// non-zero winding rule. The test is exact for int64 polygons, points on edges are undefined.
+func PointInPolygonNonZero[T interface {
+	int64 | float64
+}](p Vector2D[T], polygon []Vector2D[T]) bool {
+	return WindingNumber(p, polygon) != 0
+}
+
+// WindingNumber returns how often the polygon winds counter-clockwise around the point
+func WindingNumber[T interface {
+	int64 | float64
+}](p Vector2D[T], polygon []Vector2D[T]) int {
+	winding := 0
+	for i, a := range polygon {
+		b := polygon[(i+1)%len(polygon)]
+		if a.Y <= p.Y {
+			if b.Y > p.Y && orientation(a, b, p) > 0 {
+				winding++
+			}
+		} else if b.Y <= p.Y && orientation(a, b, p) < 0 {
+			winding--
+		}
+	}
+	return winding
+}
+
+// Simplify reduces the vertices of a polyline with the Ramer-Douglas-Peucker
+// algorithm. Removed vertices are at most epsilon away from the result and the
+// end points are always kept.
+func Simplify[T interface {
+	int64 | float64
+}](points []Vector2D[T], epsilon float64) []Vector2D[T] {
+	if len(points) < 3 {
+		return slices.Clone(points)
+	}
+
+	keep := make([]bool, len(points))
+	keep[0], keep[len(points)-1] = true, true
+	simplifyRange(points, 0, len(points)-1, epsilon, keep)
+
+	var result []Vector2D[T]
+	for i, p := range points {
+		if keep[i] {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// SimplifyPolygon reduces the vertices of a closed polygon with the
+// Ramer-Douglas-Peucker algorithm. The polygon is split at the vertex farthest
+// from the first vertex so both halves can be simplified as polylines.
+func SimplifyPolygon[T interface {
+	int64 | float64
+}](polygon []Vector2D[T], epsilon float64) []Vector2D[T] {
+	if len(polygon) < 4 {
+		return slices.Clone(polygon)
+	}
+
+	split := 0
+	for i, p := range polygon {
+		if p.Distance(polygon[0]) > polygon[split].Distance(polygon[0]) {
+			split = i
+		}
+	}
+	if split == 0 {
+		return []Vector2D[T]{polygon[0]}
+	}
+
+	first := Simplify(polygon[:split+1], epsilon)
+	second := Simplify(append(slices.Clone(polygon[split:]), polygon[0]), epsilon)
+	return append(first, second[1:len(second)-1]...)
+}
+
+// OffsetPolygon moves every edge of the polygon outward by distance, a negative
+// distance insets the polygon. Corners are mitred and bevelled when the miter
+// gets too long. Self intersections caused by large insets are not removed.
+func OffsetPolygon(polygon []Vector2D[float64], distance float64) []Vector2D[float64] {
+	n := len(polygon)
+	if n < 3 || distance == 0 {
+		return slices.Clone(polygon)
+	}
+
+	// Outward normals point to the right of counter-clockwise edges
+	sign := 1.0
+	if PolygonWinding(polygon) == WindingClockwise {
+		sign = -1.0
+	}
+	normal := func(a, b Vector2D[float64]) Vector2D[float64] {
+		edge := b.Subtract(a).Normalize()
+		return Vector2D[float64]{X: edge.Y, Y: -edge.X}.Multiply(sign)
+	}
+
+	result := make([]Vector2D[float64], 0, n)
+	for i, p := range polygon {
+		prev := polygon[(i+n-1)%n]
+		next := polygon[(i+1)%n]
+		n1 := normal(prev, p)
+		n2 := normal(p, next)
+
+		bisector := n1.Add(n2)
+		cos := n1.Dot(n2)
+		if bisector.Length() == 0 || 1+cos == 0 {
+			result = append(result, p.Add(n1.Multiply(distance)))
+			continue
+		}
+
+		// Length of the miter for a unit offset
+		miter := 1 / math.Sqrt((1+cos)/2)
+		if miter > offsetMiterLimit {
+			result = append(result, p.Add(n1.Multiply(distance)), p.Add(n2.Multiply(distance)))
+			continue
+		}
+		result = append(result, p.Add(bisector.Normalize().Multiply(distance*miter)))
+	}
+	return result
+}
+
+// orientation returns the sign of the turn from a over b to c: positive for
+// counter-clockwise, negative for clockwise and zero for collinear points
+func orientation[T interface {
+	int64 | float64
+}](a, b, c Vector2D[T]) T {
+	return b.Subtract(a).Cross(c.Subtract(a))
+}
+
+// simplifyRange marks the vertices between first and last which have to be kept
+func simplifyRange[T interface {
+	int64 | float64
+}](points []Vector2D[T], first, last int, epsilon float64, keep []bool) {
+	if last-first < 2 {
+		return
+	}
+
+	index := -1
+	maxDistance := epsilon
+	for i := first + 1; i < last; i++ {
+		d := segmentDistance(points[i].ToFloat(), points[first].ToFloat(), points[last].ToFloat())
+		if d > maxDistance {
+			index, maxDistance = i, d
+		}
+	}
+	if index < 0 {
+		return
+	}
+
+	keep[index] = true
+	simplifyRange(points, first, index, epsilon, keep)
+	simplifyRange(points, index, last, epsilon, keep)
+}
+
+// segmentDistance returns the distance from p to the segment between a and b
+func segmentDistance(p, a, b Vector2D[float64]) float64 {
+	ab := b.Subtract(a)
+	lengthSquared := ab.Dot(ab)
+	if lengthSquared == 0 {
+		return p.Distance(a)
+	}
+
+	t := math.Max(0, math.Min(1, p.Subtract(a).Dot(ab)/lengthSquared))
+	return p.Distance(a.Add(ab.Multiply(t)))
+}
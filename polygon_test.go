@@ -0,0 +1,150 @@
+package maths
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvexHull(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		points   []Vector2D[int64]
+		expected []Vector2D[int64]
+	}{
+		{
+			name:     "empty",
+			points:   nil,
+			expected: nil,
+		},
+		{
+			name:     "collinear",
+			points:   []Vector2D[int64]{{X: 2, Y: 2}, {X: 0, Y: 0}, {X: 1, Y: 1}},
+			expected: []Vector2D[int64]{{X: 0, Y: 0}, {X: 2, Y: 2}},
+		},
+		{
+			name: "square with inner and edge points",
+			points: []Vector2D[int64]{
+				{X: 1, Y: 1}, {X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 2},
+				{X: 0, Y: 2}, {X: 1, Y: 0}, {X: 0, Y: 0}, {X: 1, Y: 2},
+			},
+			expected: []Vector2D[int64]{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 2}, {X: 0, Y: 2}},
+		},
+		{
+			name: "large coordinates stay exact",
+			points: []Vector2D[int64]{
+				{X: 1 << 30, Y: 0}, {X: 0, Y: 1 << 30}, {X: -(1 << 30), Y: 0},
+				{X: 0, Y: -(1 << 30)}, {X: 1, Y: 1},
+			},
+			expected: []Vector2D[int64]{{X: -(1 << 30), Y: 0}, {X: 0, Y: -(1 << 30)}, {X: 1 << 30, Y: 0}, {X: 0, Y: 1 << 30}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			hull := ConvexHull(tt.points)
+			assert.Equal(t, tt.expected, hull)
+			if len(hull) >= 3 {
+				assert.Equal(t, WindingCounterClockwise, PolygonWinding(hull))
+			}
+		})
+	}
+}
+
+func TestPolygonAreaAndCentroid(t *testing.T) {
+	t.Parallel()
+
+	square := []Vector2D[int64]{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}}
+	assert.Equal(t, int64(32), SignedArea2(square))
+	assert.Equal(t, 16.0, SignedArea(square))
+	assert.Equal(t, NewVector2D[float64](2, 2), Centroid(square))
+
+	clockwise := EnsureWinding(square, WindingClockwise)
+	assert.Equal(t, WindingClockwise, PolygonWinding(clockwise))
+	assert.Equal(t, -16.0, SignedArea(clockwise))
+	assert.Equal(t, 16.0, Area(clockwise))
+	assert.Equal(t, square, EnsureWinding(clockwise, WindingCounterClockwise))
+	assert.Equal(t, WindingCounterClockwise, PolygonWinding(square), "input is not modified")
+
+	lShape := []Vector2D[float64]{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 2}, {X: 0, Y: 2}}
+	centroid := Centroid(lShape)
+	assert.InDelta(t, 5.0/6.0, centroid.X, 1e-12)
+	assert.InDelta(t, 5.0/6.0, centroid.Y, 1e-12)
+
+	line := []Vector2D[float64]{{X: 0, Y: 0}, {X: 2, Y: 2}}
+	assert.Equal(t, WindingNone, PolygonWinding(line))
+	assert.Equal(t, NewVector2D[float64](1, 1), Centroid(line))
+}
+
+func TestPointInPolygon(t *testing.T) {
+	t.Parallel()
+
+	// a pentagram winds twice around its center
+	star := []Vector2D[int64]{{X: 0, Y: 10}, {X: 6, Y: -8}, {X: -9, Y: 3}, {X: 9, Y: 3}, {X: -6, Y: -8}}
+
+	tests := []struct {
+		point   Vector2D[int64]
+		evenOdd bool
+		nonZero bool
+	}{
+		{Vector2D[int64]{X: 0, Y: 0}, false, true},
+		{Vector2D[int64]{X: 0, Y: 6}, true, true},
+		{Vector2D[int64]{X: 0, Y: 8}, true, true},
+		{Vector2D[int64]{X: 20, Y: 0}, false, false},
+		{Vector2D[int64]{X: 0, Y: -8}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("point %v", tt.point), func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.evenOdd, PointInPolygonEvenOdd(tt.point, star))
+			assert.Equal(t, tt.nonZero, PointInPolygonNonZero(tt.point, star))
+		})
+	}
+
+	assert.Equal(t, -2, WindingNumber(Vector2D[int64]{X: 0, Y: 0}, star))
+	assert.Equal(t, 2, WindingNumber(Vector2D[int64]{X: 0, Y: 0}, EnsureWinding(star, WindingCounterClockwise)))
+}
+
+func TestSimplify(t *testing.T) {
+	t.Parallel()
+
+	line := []Vector2D[float64]{
+		{X: 0, Y: 0}, {X: 1, Y: 0.1}, {X: 2, Y: -0.1}, {X: 3, Y: 5},
+		{X: 4, Y: 6}, {X: 5, Y: 7}, {X: 6, Y: 8.1}, {X: 7, Y: 9},
+	}
+	assert.Equal(t, []Vector2D[float64]{{X: 0, Y: 0}, {X: 2, Y: -0.1}, {X: 3, Y: 5}, {X: 7, Y: 9}}, Simplify(line, 0.5))
+	// only exactly collinear points are removed without tolerance
+	assert.Equal(t, append(line[:4:4], line[5:]...), Simplify(line, 0))
+
+	polygon := []Vector2D[int64]{
+		{X: 0, Y: 0}, {X: 5, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 5},
+		{X: 10, Y: 10}, {X: 5, Y: 10}, {X: 0, Y: 10}, {X: 0, Y: 5},
+	}
+	assert.Equal(t, []Vector2D[int64]{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}, SimplifyPolygon(polygon, 0.1))
+}
+
+func TestOffsetPolygon(t *testing.T) {
+	t.Parallel()
+
+	square := []Vector2D[float64]{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}}
+
+	for _, polygon := range [][]Vector2D[float64]{square, EnsureWinding(square, WindingClockwise)} {
+		grown := OffsetPolygon(polygon, 1)
+		assert.InDelta(t, 36.0, Area(grown), 1e-9)
+		shrunk := OffsetPolygon(polygon, -1)
+		assert.InDelta(t, 4.0, Area(shrunk), 1e-9)
+		assert.Equal(t, PolygonWinding(polygon), PolygonWinding(shrunk))
+	}
+
+	// sharp corners are bevelled
+	spike := []Vector2D[float64]{{X: 0, Y: 0}, {X: 100, Y: 1}, {X: 0, Y: 2}}
+	grown := OffsetPolygon(spike, 1)
+	assert.Len(t, grown, 4)
+}
@@ -0,0 +1,454 @@
+package maths
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// quadEntry is a value stored with its bounds inside a quadIndex, points have empty bounds
+type quadEntry[V comparable] struct {
+	Value  V
+	Bounds Rect
+}
+
+// quadNode is a single node of a quadIndex
+type quadNode[V comparable] struct {
+	bounds   Rect
+	loose    Rect
+	depth    int
+	count    int
+	parent   *quadNode[V]
+	children *[4]*quadNode[V]
+	entries  []quadEntry[V]
+}
+
+// quadIndex is the node logic shared by QuadTree and LooseQuadTree. Every node
+// accepts entries inside its bounds grown by looseness times half its size,
+// entries which fit no child stay in the parent.
+type quadIndex[V comparable] struct {
+	capacity  int
+	maxDepth  int
+	looseness float64
+	root      *quadNode[V]
+	nodes     map[V]*quadNode[V]
+}
+
+// newQuadIndex creates an empty index covering the bounds
+func newQuadIndex[V comparable](bounds Rect, capacity, maxDepth int, looseness float64) quadIndex[V] {
+	index := quadIndex[V]{
+		capacity:  max(capacity, 1),
+		maxDepth:  max(maxDepth, 0),
+		looseness: looseness,
+		nodes:     make(map[V]*quadNode[V]),
+	}
+	index.root = index.newNode(bounds, 0, nil)
+	return index
+}
+
+// newNode creates a node with its loose bounds
+func (index *quadIndex[V]) newNode(bounds Rect, depth int, parent *quadNode[V]) *quadNode[V] {
+	loose := bounds
+	if index.looseness > 0 {
+		loose = NewRectFromCenter(bounds.Center(), bounds.Size().Multiply((1+index.looseness)/2))
+	}
+	return &quadNode[V]{bounds: bounds, loose: loose, depth: depth, parent: parent}
+}
+
+// entry returns the stored entry of a value
+func (index *quadIndex[V]) entry(value V) (quadEntry[V], bool) {
+	node, ok := index.nodes[value]
+	if !ok {
+		return quadEntry[V]{}, false
+	}
+	return node.entries[node.indexOf(value)], true
+}
+
+// add inserts the entry, false when the value is already stored or the center of its bounds is outside
+func (index *quadIndex[V]) add(entry quadEntry[V]) bool {
+	if _, ok := index.nodes[entry.Value]; ok {
+		return false
+	}
+	if !index.root.bounds.Contains(entry.Bounds.Center()) {
+		return false
+	}
+
+	index.insert(index.root, entry)
+	return true
+}
+
+// remove deletes a value and merges nodes which became small enough
+func (index *quadIndex[V]) remove(value V) bool {
+	node, ok := index.nodes[value]
+	if !ok {
+		return false
+	}
+
+	i := node.indexOf(value)
+	node.entries = append(node.entries[:i], node.entries[i+1:]...)
+	delete(index.nodes, value)
+	for n := node; n != nil; n = n.parent {
+		n.count--
+	}
+	index.merge(node)
+	return true
+}
+
+// update moves a value to new bounds, false when the value is unknown or the center of the bounds is outside
+func (index *quadIndex[V]) update(value V, bounds Rect) bool {
+	node, ok := index.nodes[value]
+	if !ok || !index.root.bounds.Contains(bounds.Center()) {
+		return false
+	}
+
+	// Stay in the same node while its loose bounds still contain the value
+	if node.loose.ContainsRect(bounds) {
+		node.entries[node.indexOf(value)].Bounds = bounds
+		return true
+	}
+
+	index.remove(value)
+	index.insert(index.root, quadEntry[V]{Value: value, Bounds: bounds})
+	return true
+}
+
+// clear removes all values
+func (index *quadIndex[V]) clear() {
+	index.root = index.newNode(index.root.bounds, 0, nil)
+	index.nodes = make(map[V]*quadNode[V])
+}
+
+// walk visits all entries of nodes accepted by visitNode. The root is always
+// visited as it keeps the entries reaching outside of the index.
+func (index *quadIndex[V]) walk(visitNode func(*quadNode[V]) bool, visitEntry func(quadEntry[V])) {
+	index.root.walk(
+		func(node *quadNode[V]) bool { return node.parent == nil || visitNode(node) },
+		visitEntry,
+	)
+}
+
+// nearest returns up to k values closest to the position by the distance of their entries
+func (index *quadIndex[V]) nearest(position Vector2D[float64], k int, distance func(quadEntry[V]) float64) []V {
+	return nearest(
+		index.root,
+		k,
+		func(node *quadNode[V]) float64 {
+			if node.parent == nil {
+				return 0
+			}
+			return node.loose.DistanceTo(position)
+		},
+		distance,
+		func(node *quadNode[V]) []quadEntry[V] { return node.entries },
+		func(node *quadNode[V]) []*quadNode[V] { return node.childList() },
+		func(entry quadEntry[V]) V { return entry.Value },
+	)
+}
+
+// insert places the entry in the deepest existing node it fits into, splitting full leaves
+func (index *quadIndex[V]) insert(node *quadNode[V], entry quadEntry[V]) {
+	for node.children != nil {
+		child := node.children[node.quadrant(entry.Bounds.Center())]
+		if !child.loose.ContainsRect(entry.Bounds) {
+			break
+		}
+		node.count++
+		node = child
+	}
+
+	node.count++
+	node.entries = append(node.entries, entry)
+	index.nodes[entry.Value] = node
+
+	if node.children == nil && len(node.entries) > index.capacity && node.depth < index.maxDepth {
+		index.split(node)
+	}
+}
+
+// split creates the four children of a leaf and moves the entries that fit down
+func (index *quadIndex[V]) split(node *quadNode[V]) {
+	node.children = new([4]*quadNode[V])
+	for i, bounds := range quadrantRects(node.bounds) {
+		node.children[i] = index.newNode(bounds, node.depth+1, node)
+	}
+
+	entries := node.entries
+	node.entries = nil
+	node.count -= len(entries)
+	for _, entry := range entries {
+		index.insert(node, entry)
+	}
+}
+
+// merge collapses nodes whose subtree fits into a single node again
+func (index *quadIndex[V]) merge(node *quadNode[V]) {
+	for ; node != nil; node = node.parent {
+		if node.children == nil || node.count > index.capacity {
+			continue
+		}
+
+		var entries []quadEntry[V]
+		node.walk(
+			func(*quadNode[V]) bool { return true },
+			func(entry quadEntry[V]) { entries = append(entries, entry) },
+		)
+		node.children = nil
+		node.entries = entries
+		for _, entry := range entries {
+			index.nodes[entry.Value] = node
+		}
+	}
+}
+
+// quadrant returns the index of the child containing the position
+func (node *quadNode[V]) quadrant(position Vector2D[float64]) int {
+	center := node.bounds.Center()
+	index := 0
+	if position.X >= center.X {
+		index |= 1
+	}
+	if position.Y >= center.Y {
+		index |= 2
+	}
+	return index
+}
+
+// indexOf returns the entry index of a value in the node
+func (node *quadNode[V]) indexOf(value V) int {
+	for i, entry := range node.entries {
+		if entry.Value == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// childList returns the children as slice, nil for a leaf
+func (node *quadNode[V]) childList() []*quadNode[V] {
+	if node.children == nil {
+		return nil
+	}
+	return node.children[:]
+}
+
+// walk visits all entries of nodes accepted by visitNode
+func (node *quadNode[V]) walk(visitNode func(*quadNode[V]) bool, visitEntry func(quadEntry[V])) {
+	if !visitNode(node) {
+		return
+	}
+	for _, entry := range node.entries {
+		visitEntry(entry)
+	}
+	for _, child := range node.childList() {
+		child.walk(visitNode, visitEntry)
+	}
+}
+
+// QuadTree is a point quadtree storing values at Vector2D positions
+type QuadTree[V comparable] struct {
+	index quadIndex[V]
+}
+
+// NewQuadTree creates a new quadtree covering the bounds.
+// A node is split once it holds more than capacity values and has not reached maxDepth.
+func NewQuadTree[V comparable](bounds Rect, capacity, maxDepth int) *QuadTree[V] {
+	return &QuadTree[V]{index: newQuadIndex[V](bounds, capacity, maxDepth, 0)}
+}
+
+// Bounds returns the area covered by the quadtree
+func (tree *QuadTree[V]) Bounds() Rect {
+	return tree.index.root.bounds
+}
+
+// Len returns the number of values in the quadtree
+func (tree *QuadTree[V]) Len() int {
+	return tree.index.root.count
+}
+
+// Position returns the position of a value
+func (tree *QuadTree[V]) Position(value V) (Vector2D[float64], bool) {
+	entry, ok := tree.index.entry(value)
+	return entry.Bounds.Min, ok
+}
+
+// Insert adds a value at the position. It returns false when the position
+// is outside of the bounds or the value is already stored.
+func (tree *QuadTree[V]) Insert(value V, position Vector2D[float64]) bool {
+	return tree.index.add(quadEntry[V]{Value: value, Bounds: NewRect(position, position)})
+}
+
+// Remove deletes a value from the quadtree
+func (tree *QuadTree[V]) Remove(value V) bool {
+	return tree.index.remove(value)
+}
+
+// Update moves a value to a new position. It returns false when the value is
+// unknown or the new position is outside of the bounds.
+func (tree *QuadTree[V]) Update(value V, position Vector2D[float64]) bool {
+	return tree.index.update(value, NewRect(position, position))
+}
+
+// Clear removes all values from the quadtree
+func (tree *QuadTree[V]) Clear() {
+	tree.index.clear()
+}
+
+// QueryRect returns all values inside the area
+func (tree *QuadTree[V]) QueryRect(area Rect) []V {
+	var results []V
+	tree.index.walk(
+		func(node *quadNode[V]) bool { return node.bounds.Intersects(area) },
+		func(entry quadEntry[V]) {
+			if area.Contains(entry.Bounds.Min) {
+				results = append(results, entry.Value)
+			}
+		},
+	)
+	return results
+}
+
+// QueryCircle returns all values within the radius around the center
+func (tree *QuadTree[V]) QueryCircle(center Vector2D[float64], radius float64) []V {
+	var results []V
+	tree.index.walk(
+		func(node *quadNode[V]) bool { return node.bounds.IntersectsCircle(center, radius) },
+		func(entry quadEntry[V]) {
+			if entry.Bounds.Min.Distance(center) <= radius {
+				results = append(results, entry.Value)
+			}
+		},
+	)
+	return results
+}
+
+// Nearest returns up to k values closest to the position, nearest first
+func (tree *QuadTree[V]) Nearest(position Vector2D[float64], k int) []V {
+	return tree.index.nearest(position, k, func(entry quadEntry[V]) float64 {
+		return entry.Bounds.Min.Distance(position)
+	})
+}
+
+// QueryRay returns all values within the radius of the ray segment from origin
+// along direction up to maxDistance, ordered by their distance along the ray
+func (tree *QuadTree[V]) QueryRay(
+	origin Vector2D[float64],
+	direction Vector2D[float64],
+	maxDistance float64,
+	radius float64,
+) []V {
+	dir := direction.Normalize()
+	var hits []rayHit[V]
+
+	tree.index.walk(
+		func(node *quadNode[V]) bool {
+			_, ok := node.bounds.Expand(radius).IntersectRay(origin, dir, maxDistance)
+			return ok
+		},
+		func(entry quadEntry[V]) {
+			position := entry.Bounds.Min
+			t := math.Max(0, math.Min(maxDistance, position.Subtract(origin).Dot(dir)))
+			closest := origin.Add(dir.Multiply(t))
+			if closest.Distance(position) <= radius {
+				hits = append(hits, rayHit[V]{value: entry.Value, distance: t})
+			}
+		},
+	)
+
+	return sortRayHits(hits)
+}
+
+// quadrantRects splits the rectangle into four equal parts in quadrant index order
+func quadrantRects(r Rect) [4]Rect {
+	center := r.Center()
+	return [4]Rect{
+		{Min: r.Min, Max: center},
+		{Min: Vector2D[float64]{X: center.X, Y: r.Min.Y}, Max: Vector2D[float64]{X: r.Max.X, Y: center.Y}},
+		{Min: Vector2D[float64]{X: r.Min.X, Y: center.Y}, Max: Vector2D[float64]{X: center.X, Y: r.Max.Y}},
+		{Min: center, Max: r.Max},
+	}
+}
+
+// rayHit is a value hit by a ray query
+type rayHit[V any] struct {
+	value    V
+	distance float64
+}
+
+// sortRayHits orders the hits by distance along the ray and returns the values
+func sortRayHits[V any](hits []rayHit[V]) []V {
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].distance < hits[j].distance
+	})
+
+	results := make([]V, len(hits))
+	for i, hit := range hits {
+		results[i] = hit.value
+	}
+	return results
+}
+
+// nearestItem is either a node or an entry waiting in the nearest search queue
+type nearestItem[N, E any] struct {
+	node     N
+	entry    E
+	isEntry  bool
+	distance float64
+	order    int
+}
+
+// nearestQueue is a min heap ordered by distance and insertion order
+type nearestQueue[N, E any] []nearestItem[N, E]
+
+func (q nearestQueue[N, E]) Len() int { return len(q) }
+func (q nearestQueue[N, E]) Less(i, j int) bool {
+	if q[i].distance != q[j].distance {
+		return q[i].distance < q[j].distance
+	}
+	return q[i].order < q[j].order
+}
+func (q nearestQueue[N, E]) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *nearestQueue[N, E]) Push(x any)   { *q = append(*q, x.(nearestItem[N, E])) }
+func (q *nearestQueue[N, E]) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// nearest runs a best first search over a tree and returns the k closest values
+func nearest[N, E, V any](
+	root N,
+	k int,
+	nodeDistance func(N) float64,
+	entryDistance func(E) float64,
+	entries func(N) []E,
+	children func(N) []N,
+	value func(E) V,
+) []V {
+	if k <= 0 {
+		return nil
+	}
+
+	results := make([]V, 0, k)
+	order := 0
+	queue := &nearestQueue[N, E]{{node: root, distance: nodeDistance(root)}}
+
+	for queue.Len() > 0 && len(results) < k {
+		item := heap.Pop(queue).(nearestItem[N, E])
+		if item.isEntry {
+			results = append(results, value(item.entry))
+			continue
+		}
+
+		for _, entry := range entries(item.node) {
+			order++
+			heap.Push(queue, nearestItem[N, E]{entry: entry, isEntry: true, distance: entryDistance(entry), order: order})
+		}
+		for _, child := range children(item.node) {
+			order++
+			heap.Push(queue, nearestItem[N, E]{node: child, distance: nodeDistance(child), order: order})
+		}
+	}
+
+	return results
+}
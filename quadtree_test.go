@@ -0,0 +1,185 @@
+package maths
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testPoints returns n evenly spread but irregular points inside the rect
+func testPoints(n int, area Rect) []Vector2D[float64] {
+	const a1, a2 = 0.7548776662466927, 0.5698402909980532
+	points := make([]Vector2D[float64], n)
+	for i := range points {
+		fx := math.Mod(0.5+a1*float64(i+1), 1)
+		fy := math.Mod(0.5+a2*float64(i+1), 1)
+		points[i] = NewVector2D(area.Min.X+fx*area.Width(), area.Min.Y+fy*area.Height())
+	}
+	return points
+}
+
+// bruteNearest returns the indices of the k points closest to p
+func bruteNearest(points []Vector2D[float64], p Vector2D[float64], k int) []int {
+	indices := make([]int, len(points))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		return points[indices[i]].Distance(p) < points[indices[j]].Distance(p)
+	})
+	return indices[:min(k, len(indices))]
+}
+
+func TestQuadTreeInsertRemoveUpdate(t *testing.T) {
+	t.Parallel()
+
+	tree := NewQuadTree[int](NewRect(NewVector2D[float64](0, 0), NewVector2D[float64](100, 100)), 2, 8)
+
+	assert.True(t, tree.Insert(1, NewVector2D[float64](10, 10)))
+	assert.True(t, tree.Insert(2, NewVector2D[float64](90, 90)))
+	assert.True(t, tree.Insert(3, NewVector2D[float64](20, 20)))
+	assert.True(t, tree.Insert(4, NewVector2D[float64](25, 25)))
+	assert.False(t, tree.Insert(4, NewVector2D[float64](30, 30)), "duplicate value")
+	assert.False(t, tree.Insert(5, NewVector2D[float64](101, 30)), "outside of bounds")
+	assert.Equal(t, 4, tree.Len())
+
+	assert.True(t, tree.Update(1, NewVector2D[float64](80, 80)))
+	position, ok := tree.Position(1)
+	assert.True(t, ok)
+	assert.Equal(t, NewVector2D[float64](80, 80), position)
+	assert.ElementsMatch(t, []int{1, 2}, tree.QueryRect(NewRect(NewVector2D[float64](50, 50), NewVector2D[float64](100, 100))))
+
+	assert.True(t, tree.Remove(2))
+	assert.False(t, tree.Remove(2))
+	assert.Equal(t, 3, tree.Len())
+	assert.ElementsMatch(t, []int{1}, tree.QueryRect(NewRect(NewVector2D[float64](50, 50), NewVector2D[float64](100, 100))))
+
+	tree.Clear()
+	assert.Equal(t, 0, tree.Len())
+	assert.Empty(t, tree.QueryRect(tree.Bounds()))
+}
+
+func TestQuadTreeQueries(t *testing.T) {
+	t.Parallel()
+
+	bounds := NewRect(NewVector2D[float64](-50, -50), NewVector2D[float64](50, 50))
+	points := testPoints(500, bounds)
+	tree := NewQuadTree[int](bounds, 4, 10)
+	for i, p := range points {
+		assert.True(t, tree.Insert(i, p))
+	}
+
+	area := NewRect(NewVector2D[float64](-10, -20), NewVector2D[float64](15, 5))
+	center := NewVector2D[float64](12, -7)
+	var expectedRect, expectedCircle []int
+	for i, p := range points {
+		if area.Contains(p) {
+			expectedRect = append(expectedRect, i)
+		}
+		if p.Distance(center) <= 9 {
+			expectedCircle = append(expectedCircle, i)
+		}
+	}
+
+	assert.ElementsMatch(t, expectedRect, tree.QueryRect(area))
+	assert.ElementsMatch(t, expectedCircle, tree.QueryCircle(center, 9))
+	assert.Equal(t, bruteNearest(points, center, 7), tree.Nearest(center, 7))
+	assert.Len(t, tree.Nearest(center, 1000), len(points))
+
+	// removing half of the points keeps the queries correct
+	for i := 0; i < len(points); i += 2 {
+		assert.True(t, tree.Remove(i))
+	}
+	var expectedOdd []int
+	for _, i := range expectedRect {
+		if i%2 == 1 {
+			expectedOdd = append(expectedOdd, i)
+		}
+	}
+	assert.ElementsMatch(t, expectedOdd, tree.QueryRect(area))
+}
+
+func TestQuadTreeQueryRay(t *testing.T) {
+	t.Parallel()
+
+	tree := NewQuadTree[string](NewRect(NewVector2D[float64](0, 0), NewVector2D[float64](100, 100)), 1, 8)
+	tree.Insert("far", NewVector2D[float64](80, 51))
+	tree.Insert("near", NewVector2D[float64](20, 49))
+	tree.Insert("off", NewVector2D[float64](50, 60))
+	tree.Insert("behind", NewVector2D[float64](5, 50))
+
+	result := tree.QueryRay(NewVector2D[float64](10, 50), NewVector2D[float64](2, 0), 100, 1.5)
+	assert.Equal(t, []string{"near", "far"}, result)
+
+	result = tree.QueryRay(NewVector2D[float64](10, 50), NewVector2D[float64](1, 0), 50, 1.5)
+	assert.Equal(t, []string{"near"}, result)
+}
+
+func BenchmarkQuadTree(b *testing.B) {
+	bounds := NewRect(NewVector2D[float64](0, 0), NewVector2D[float64](1000, 1000))
+	area := NewRect(NewVector2D[float64](400, 400), NewVector2D[float64](450, 450))
+	center := NewVector2D[float64](500, 500)
+
+	for _, n := range []int{1000, 10000} {
+		points := testPoints(n, bounds)
+
+		// Every benchmark builds its own tree as Update moves the points
+		newTree := func() *QuadTree[int] {
+			tree := NewQuadTree[int](bounds, 8, 12)
+			for i, p := range points {
+				tree.Insert(i, p)
+			}
+			return tree
+		}
+
+		b.Run(fmt.Sprintf("QueryRect/QuadTree/%d", n), func(b *testing.B) {
+			tree := newTree()
+			for b.Loop() {
+				tree.QueryRect(area)
+			}
+		})
+		b.Run(fmt.Sprintf("QueryRect/BruteForce/%d", n), func(b *testing.B) {
+			for b.Loop() {
+				var results []int
+				for i, p := range points {
+					if area.Contains(p) {
+						results = append(results, i)
+					}
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("Nearest/QuadTree/%d", n), func(b *testing.B) {
+			tree := newTree()
+			for b.Loop() {
+				tree.Nearest(center, 8)
+			}
+		})
+		b.Run(fmt.Sprintf("Nearest/BruteForce/%d", n), func(b *testing.B) {
+			for b.Loop() {
+				bruteNearest(points, center, 8)
+			}
+		})
+		b.Run(fmt.Sprintf("Update/QuadTree/%d", n), func(b *testing.B) {
+			tree := newTree()
+			i := 0
+			for b.Loop() {
+				p := points[i%n]
+				tree.Update(i%n, NewVector2D(math.Mod(p.X+float64(i), 1000), p.Y))
+				i++
+			}
+		})
+		b.Run(fmt.Sprintf("Update/BruteForce/%d", n), func(b *testing.B) {
+			moved := slices.Clone(points)
+			i := 0
+			for b.Loop() {
+				p := points[i%n]
+				moved[i%n] = NewVector2D(math.Mod(p.X+float64(i), 1000), p.Y)
+				i++
+			}
+		})
+	}
+}
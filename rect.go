@@ -0,0 +1,141 @@
+package maths
+
+import (
+	"fmt"
+	"math"
+)
+
+// Rect is an axis aligned rectangle described by its minimum and maximum corner
+type Rect struct {
+	Min, Max Vector2D[float64]
+}
+
+// NewRect creates a new Rect from two opposite corners
+func NewRect(a, b Vector2D[float64]) Rect {
+	return Rect{
+		Min: Vector2D[float64]{X: math.Min(a.X, b.X), Y: math.Min(a.Y, b.Y)},
+		Max: Vector2D[float64]{X: math.Max(a.X, b.X), Y: math.Max(a.Y, b.Y)},
+	}
+}
+
+// NewRectFromCenter creates a new Rect with the given center and half size
+func NewRectFromCenter(center, halfSize Vector2D[float64]) Rect {
+	return NewRect(center.Subtract(halfSize), center.Add(halfSize))
+}
+
+// String returns the corners as `minX:minY-maxX:maxY`
+func (r Rect) String() string {
+	return fmt.Sprintf("%v-%v", r.Min, r.Max)
+}
+
+// Width returns the width of the rectangle
+func (r Rect) Width() float64 {
+	return r.Max.X - r.Min.X
+}
+
+// Height returns the height of the rectangle
+func (r Rect) Height() float64 {
+	return r.Max.Y - r.Min.Y
+}
+
+// Size returns the width and height of the rectangle as vector
+func (r Rect) Size() Vector2D[float64] {
+	return r.Max.Subtract(r.Min)
+}
+
+// Center returns the center point of the rectangle
+func (r Rect) Center() Vector2D[float64] {
+	return Vector2D[float64]{
+		X: (r.Min.X + r.Max.X) / 2,
+		Y: (r.Min.Y + r.Max.Y) / 2,
+	}
+}
+
+// Contains reports whether the point lies inside the rectangle, edges included
+func (r Rect) Contains(p Vector2D[float64]) bool {
+	return p.X >= r.Min.X && p.X <= r.Max.X &&
+		p.Y >= r.Min.Y && p.Y <= r.Max.Y
+}
+
+// ContainsRect reports whether the other rectangle lies completely inside the rectangle
+func (r Rect) ContainsRect(other Rect) bool {
+	return other.Min.X >= r.Min.X && other.Max.X <= r.Max.X &&
+		other.Min.Y >= r.Min.Y && other.Max.Y <= r.Max.Y
+}
+
+// Intersects reports whether two rectangles overlap, touching edges included
+func (r Rect) Intersects(other Rect) bool {
+	return r.Min.X <= other.Max.X && r.Max.X >= other.Min.X &&
+		r.Min.Y <= other.Max.Y && r.Max.Y >= other.Min.Y
+}
+
+// IntersectsCircle reports whether the circle overlaps the rectangle
+func (r Rect) IntersectsCircle(center Vector2D[float64], radius float64) bool {
+	return r.DistanceTo(center) <= radius
+}
+
+// Union returns the smallest rectangle containing both rectangles
+func (r Rect) Union(other Rect) Rect {
+	return Rect{
+		Min: Vector2D[float64]{X: math.Min(r.Min.X, other.Min.X), Y: math.Min(r.Min.Y, other.Min.Y)},
+		Max: Vector2D[float64]{X: math.Max(r.Max.X, other.Max.X), Y: math.Max(r.Max.Y, other.Max.Y)},
+	}
+}
+
+// Expand returns the rectangle grown by margin on every side
+func (r Rect) Expand(margin float64) Rect {
+	return Rect{
+		Min: Vector2D[float64]{X: r.Min.X - margin, Y: r.Min.Y - margin},
+		Max: Vector2D[float64]{X: r.Max.X + margin, Y: r.Max.Y + margin},
+	}
+}
+
+// ClosestPoint returns the point of the rectangle closest to p
+func (r Rect) ClosestPoint(p Vector2D[float64]) Vector2D[float64] {
+	return Vector2D[float64]{
+		X: math.Max(r.Min.X, math.Min(p.X, r.Max.X)),
+		Y: math.Max(r.Min.Y, math.Min(p.Y, r.Max.Y)),
+	}
+}
+
+// DistanceTo returns the distance from p to the rectangle, 0 when p is inside
+func (r Rect) DistanceTo(p Vector2D[float64]) float64 {
+	return p.Distance(r.ClosestPoint(p))
+}
+
+// IntersectRay returns the distance along the ray at which it enters the rectangle.
+// The direction has to be normalized, a ray starting inside the rectangle hits at 0.
+func (r Rect) IntersectRay(
+	origin Vector2D[float64],
+	direction Vector2D[float64],
+	maxDistance float64,
+) (float64, bool) {
+	tMin, tMax := 0.0, maxDistance
+
+	// Slab test for both axes
+	for _, axis := range [2][4]float64{
+		{origin.X, direction.X, r.Min.X, r.Max.X},
+		{origin.Y, direction.Y, r.Min.Y, r.Max.Y},
+	} {
+		o, d, lo, hi := axis[0], axis[1], axis[2], axis[3]
+		if d == 0 {
+			if o < lo || o > hi {
+				return 0, false
+			}
+			continue
+		}
+
+		t1 := (lo - o) / d
+		t2 := (hi - o) / d
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		tMin = math.Max(tMin, t1)
+		tMax = math.Min(tMax, t2)
+		if tMin > tMax {
+			return 0, false
+		}
+	}
+
+	return tMin, true
+}
@@ -0,0 +1,72 @@
+package maths
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRect(t *testing.T) {
+	t.Parallel()
+
+	rect := NewRect(NewVector2D[float64](10, -5), NewVector2D[float64](-2, 3))
+	assert.Equal(t, NewVector2D[float64](-2, -5), rect.Min)
+	assert.Equal(t, NewVector2D[float64](10, 3), rect.Max)
+	assert.Equal(t, 12.0, rect.Width())
+	assert.Equal(t, 8.0, rect.Height())
+	assert.Equal(t, NewVector2D[float64](4, -1), rect.Center())
+
+	centered := NewRectFromCenter(NewVector2D[float64](1, 1), NewVector2D[float64](2, 3))
+	assert.Equal(t, NewRect(NewVector2D[float64](-1, -2), NewVector2D[float64](3, 4)), centered)
+}
+
+func TestRectContainsAndIntersects(t *testing.T) {
+	t.Parallel()
+
+	rect := NewRect(NewVector2D[float64](0, 0), NewVector2D[float64](10, 10))
+
+	assert.True(t, rect.Contains(NewVector2D[float64](0, 10)))
+	assert.False(t, rect.Contains(NewVector2D[float64](-0.1, 5)))
+	assert.True(t, rect.ContainsRect(NewRect(NewVector2D[float64](1, 1), NewVector2D[float64](10, 10))))
+	assert.False(t, rect.ContainsRect(NewRect(NewVector2D[float64](1, 1), NewVector2D[float64](11, 10))))
+	assert.True(t, rect.Intersects(NewRect(NewVector2D[float64](10, 10), NewVector2D[float64](12, 12))))
+	assert.False(t, rect.Intersects(NewRect(NewVector2D[float64](11, 0), NewVector2D[float64](12, 12))))
+	assert.True(t, rect.IntersectsCircle(NewVector2D[float64](13, 14), 5))
+	assert.False(t, rect.IntersectsCircle(NewVector2D[float64](13, 14), 4.9))
+	assert.Equal(t, 5.0, rect.DistanceTo(NewVector2D[float64](13, 14)))
+	assert.Equal(t, 0.0, rect.DistanceTo(NewVector2D[float64](3, 4)))
+}
+
+func TestRectIntersectRay(t *testing.T) {
+	t.Parallel()
+
+	rect := NewRect(NewVector2D[float64](2, 2), NewVector2D[float64](4, 4))
+
+	tests := []struct {
+		origin    Vector2D[float64]
+		direction Vector2D[float64]
+		maxDist   float64
+		distance  float64
+		hit       bool
+	}{
+		{NewVector2D[float64](0, 3), NewVector2D[float64](1, 0), 10, 2, true},
+		{NewVector2D[float64](3, 3), NewVector2D[float64](1, 0), 10, 0, true},
+		{NewVector2D[float64](0, 3), NewVector2D[float64](-1, 0), 10, 0, false},
+		{NewVector2D[float64](0, 3), NewVector2D[float64](1, 0), 1, 0, false},
+		{NewVector2D[float64](0, 5), NewVector2D[float64](1, 0), 10, 0, false},
+		{NewVector2D[float64](3, 0), NewVector2D[float64](0, 1), 10, 2, true},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test %d", i), func(t *testing.T) {
+			t.Parallel()
+
+			distance, hit := rect.IntersectRay(tt.origin, tt.direction, tt.maxDist)
+			assert.Equal(t, tt.hit, hit)
+			if tt.hit {
+				assert.InDelta(t, tt.distance, distance, 1e-9)
+			}
+		})
+	}
+}
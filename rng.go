@@ -0,0 +1,221 @@
+package maths
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/bits"
+)
+
+// ErrInvalidRNGState is returned when a serialized RNG state cannot be decoded
+var ErrInvalidRNGState = errors.New("maths: invalid rng state")
+
+// rngDefaultStream is the stream used by NewRNG
+const rngDefaultStream = 0xda3e39cb94b95bdb
+
+// rngMultiplier is the multiplier of the PCG linear congruential generator
+const rngMultiplier = 6364136223846793005
+
+// RNGState is the complete state of an RNG. It can be stored and restored to
+// replay the same sequence, e.g. for lockstep multiplayer or save games.
+type RNGState struct {
+	State     uint64 `json:"state"`
+	Increment uint64 `json:"increment"`
+}
+
+// RNG is a deterministic PCG32 random number generator. The sequence only
+// depends on seed and stream and is identical on every platform. It is not
+// safe for concurrent use and not suitable for cryptography.
+type RNG struct {
+	state RNGState
+}
+
+// NewRNG creates a new RNG with the given seed
+func NewRNG(seed uint64) *RNG {
+	return NewRNGStream(seed, rngDefaultStream)
+}
+
+// NewRNGStream creates a new RNG with the given seed on one of 2^63 independent streams
+func NewRNGStream(seed, stream uint64) *RNG {
+	r := &RNG{state: RNGState{Increment: stream<<1 | 1}}
+	r.Uint32()
+	r.state.State += seed
+	r.Uint32()
+	return r
+}
+
+// Snapshot returns the current state
+func (r *RNG) Snapshot() RNGState {
+	return r.state
+}
+
+// Restore continues the sequence from a state returned by Snapshot
+func (r *RNG) Restore(state RNGState) {
+	r.state = state
+	r.state.Increment |= 1
+}
+
+// MarshalBinary encodes the state in 16 bytes
+func (r *RNG) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 16)
+	binary.BigEndian.PutUint64(data[:8], r.state.State)
+	binary.BigEndian.PutUint64(data[8:], r.state.Increment)
+	return data, nil
+}
+
+// UnmarshalBinary decodes a state encoded by MarshalBinary
+func (r *RNG) UnmarshalBinary(data []byte) error {
+	if len(data) != 16 || data[15]&1 == 0 {
+		return ErrInvalidRNGState
+	}
+	r.state = RNGState{
+		State:     binary.BigEndian.Uint64(data[:8]),
+		Increment: binary.BigEndian.Uint64(data[8:]),
+	}
+	return nil
+}
+
+// Uint32 returns a uniformly distributed 32 bit value
+func (r *RNG) Uint32() uint32 {
+	old := r.state.State
+	r.state.State = old*rngMultiplier + r.state.Increment
+
+	xorShifted := uint32(((old >> 18) ^ old) >> 27)
+	rotation := int(old >> 59)
+	return bits.RotateLeft32(xorShifted, -rotation)
+}
+
+// Uint64 returns a uniformly distributed 64 bit value
+func (r *RNG) Uint64() uint64 {
+	return uint64(r.Uint32())<<32 | uint64(r.Uint32())
+}
+
+// IntN returns a uniformly distributed value in [0, n). It panics if n <= 0.
+func (r *RNG) IntN(n int) int {
+	if n <= 0 {
+		panic("maths: invalid argument to IntN")
+	}
+
+	// Lemire's multiply and reject method avoids the modulo bias
+	bound := uint64(n)
+	hi, lo := bits.Mul64(r.Uint64(), bound)
+	if lo < bound {
+		threshold := -bound % bound
+		for lo < threshold {
+			hi, lo = bits.Mul64(r.Uint64(), bound)
+		}
+	}
+	return int(hi)
+}
+
+// IntRange returns a uniformly distributed value in [low, high]
+func (r *RNG) IntRange(low, high int) int {
+	if high < low {
+		low, high = high, low
+	}
+	return low + r.IntN(high-low+1)
+}
+
+// Float64 returns a uniformly distributed value in [0, 1)
+func (r *RNG) Float64() float64 {
+	return float64(r.Uint64()>>11) / (1 << 53)
+}
+
+// Float64Range returns a uniformly distributed value in [low, high)
+func (r *RNG) Float64Range(low, high float64) float64 {
+	return low + (high-low)*r.Float64()
+}
+
+// Bool returns true with the given probability
+func (r *RNG) Bool(probability float64) bool {
+	return r.Float64() < probability
+}
+
+// WeightedChoice returns an index with a probability proportional to its weight.
+// Negative weights count as zero, -1 is returned when no weight is positive.
+func (r *RNG) WeightedChoice(weights []float64) int {
+	total := 0.0
+	for _, w := range weights {
+		if w > 0 {
+			total += w
+		}
+	}
+	if total <= 0 {
+		return -1
+	}
+
+	target := r.Float64() * total
+	last := -1
+	for i, w := range weights {
+		if w <= 0 {
+			continue
+		}
+		if target < w {
+			return i
+		}
+		target -= w
+		last = i
+	}
+
+	// Rounding may leave a tiny remainder
+	return last
+}
+
+// Shuffle randomizes the order of n elements with the Fisher-Yates algorithm
+func (r *RNG) Shuffle(n int, swap func(i, j int)) {
+	for i := n - 1; i > 0; i-- {
+		swap(i, r.IntN(i+1))
+	}
+}
+
+// PointInRect returns a uniformly distributed point inside the rectangle
+func (r *RNG) PointInRect(rect Rect) Vector2D[float64] {
+	return Vector2D[float64]{
+		X: r.Float64Range(rect.Min.X, rect.Max.X),
+		Y: r.Float64Range(rect.Min.Y, rect.Max.Y),
+	}
+}
+
+// PointInCircle returns a uniformly distributed point inside the circle
+func (r *RNG) PointInCircle(center Vector2D[float64], radius float64) Vector2D[float64] {
+	distance := radius * math.Sqrt(r.Float64())
+	return center.Add(r.UnitVector().Multiply(distance))
+}
+
+// UnitVector returns a vector of length one with a uniformly distributed direction
+func (r *RNG) UnitVector() Vector2D[float64] {
+	angle := 2 * math.Pi * r.Float64()
+	return Vector2D[float64]{X: math.Cos(angle), Y: math.Sin(angle)}
+}
+
+// HexInRadius returns a uniformly distributed hex within the radius around the
+// center. It picks the same hex as indexing center.Spiral(radius) randomly but
+// without building the spiral.
+func (r *RNG) HexInRadius(center Hex[int64], radius int) Hex[int64] {
+	if radius <= 0 {
+		return center
+	}
+
+	index := r.IntN(3*radius*(radius+1) + 1)
+	if index == 0 {
+		return center
+	}
+
+	// Ring k starts at index 3k(k-1)+1 and holds 6k hexes
+	ring := int((3 + math.Sqrt(float64(12*index-3))) / 6)
+	for 3*ring*(ring-1)+1 > index {
+		ring--
+	}
+	for 3*(ring+1)*ring+1 <= index {
+		ring++
+	}
+	offset := index - 3*ring*(ring-1) - 1
+
+	// Walk the ring like SpiralRing does
+	hex := center.Add(directions[0].Multiply(int64(ring)))
+	side, step := offset/ring, offset%ring+1
+	for i := 0; i < side; i++ {
+		hex = hex.Add(directions[(i+2)%6].Multiply(int64(ring)))
+	}
+	return hex.Add(directions[(side+2)%6].Multiply(int64(step)))
+}
@@ -0,0 +1,171 @@
+package maths
+
+import (
+	"encoding/json"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRNGReference(t *testing.T) {
+	t.Parallel()
+
+	// Output of the PCG32 reference implementation for seed 42 on stream 54
+	r := NewRNGStream(42, 54)
+	expected := []uint32{0xa15c02b7, 0x7b47f409, 0xba1d3330, 0x83d2f293, 0xbfa4784b, 0xcbed606e}
+	for _, value := range expected {
+		assert.Equal(t, value, r.Uint32())
+	}
+}
+
+func TestRNGDeterministic(t *testing.T) {
+	t.Parallel()
+
+	a, b, c := NewRNG(1), NewRNG(1), NewRNG(2)
+	different := false
+	for i := 0; i < 100; i++ {
+		va, vb, vc := a.Uint64(), b.Uint64(), c.Uint64()
+		assert.Equal(t, va, vb)
+		different = different || va != vc
+	}
+	assert.True(t, different)
+}
+
+func TestRNGSnapshot(t *testing.T) {
+	t.Parallel()
+
+	r := NewRNG(7)
+	r.Uint64()
+	snapshot := r.Snapshot()
+	expected := []int{r.IntN(100), r.IntN(100), r.IntN(100)}
+
+	r.Restore(snapshot)
+	assert.Equal(t, expected, []int{r.IntN(100), r.IntN(100), r.IntN(100)})
+
+	// Binary round trip
+	r.Restore(snapshot)
+	data, err := r.MarshalBinary()
+	require.NoError(t, err)
+	restored := &RNG{}
+	require.NoError(t, restored.UnmarshalBinary(data))
+	assert.Equal(t, expected, []int{restored.IntN(100), restored.IntN(100), restored.IntN(100)})
+	assert.ErrorIs(t, restored.UnmarshalBinary(data[:8]), ErrInvalidRNGState)
+
+	// JSON round trip of the state
+	encoded, err := json.Marshal(snapshot)
+	require.NoError(t, err)
+	var decoded RNGState
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, snapshot, decoded)
+}
+
+func TestRNGRanges(t *testing.T) {
+	t.Parallel()
+
+	r := NewRNG(3)
+	counts := make([]int, 6)
+	for i := 0; i < 6000; i++ {
+		v := r.IntRange(-2, 3)
+		require.GreaterOrEqual(t, v, -2)
+		require.LessOrEqual(t, v, 3)
+		counts[v+2]++
+
+		f := r.Float64Range(-1, 1)
+		require.GreaterOrEqual(t, f, -1.0)
+		require.Less(t, f, 1.0)
+	}
+	for _, count := range counts {
+		assert.InDelta(t, 1000, count, 150)
+	}
+
+	assert.Equal(t, 5, r.IntRange(5, 5))
+	assert.Panics(t, func() { r.IntN(0) })
+}
+
+func TestRNGWeightedChoice(t *testing.T) {
+	t.Parallel()
+
+	r := NewRNG(4)
+	weights := []float64{1, 0, 3, -2}
+	counts := make([]int, len(weights))
+	for i := 0; i < 4000; i++ {
+		counts[r.WeightedChoice(weights)]++
+	}
+
+	assert.InDelta(t, 1000, counts[0], 150)
+	assert.Zero(t, counts[1])
+	assert.InDelta(t, 3000, counts[2], 150)
+	assert.Zero(t, counts[3])
+	assert.Equal(t, -1, r.WeightedChoice([]float64{0, -1}))
+	assert.Equal(t, -1, r.WeightedChoice(nil))
+}
+
+func TestRNGShuffle(t *testing.T) {
+	t.Parallel()
+
+	values := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	shuffled := slices.Clone(values)
+	NewRNG(5).Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	assert.NotEqual(t, values, shuffled)
+	assert.ElementsMatch(t, values, shuffled)
+
+	again := slices.Clone(values)
+	NewRNG(5).Shuffle(len(again), func(i, j int) {
+		again[i], again[j] = again[j], again[i]
+	})
+	assert.Equal(t, shuffled, again)
+}
+
+func TestRNGGeometry(t *testing.T) {
+	t.Parallel()
+
+	r := NewRNG(6)
+	rect := NewRect(Vector2D[float64]{X: -5, Y: 2}, Vector2D[float64]{X: 5, Y: 4})
+	center := Vector2D[float64]{X: 1, Y: 1}
+	inner := 0
+	for i := 0; i < 2000; i++ {
+		assert.True(t, rect.Contains(r.PointInRect(rect)))
+		assert.InDelta(t, 1, r.UnitVector().Length(), 1e-12)
+
+		p := r.PointInCircle(center, 2)
+		assert.LessOrEqual(t, p.Distance(center), 2.0)
+		if p.Distance(center) < math.Sqrt2 {
+			inner++
+		}
+	}
+
+	// Half of the area of the circle is within radius sqrt(2)
+	assert.InDelta(t, 1000, inner, 100)
+}
+
+func TestRNGHexInRadius(t *testing.T) {
+	t.Parallel()
+
+	center := Hex[int64]{Q: 3, R: -2}
+	for radius := 0; radius <= 6; radius++ {
+		spiral := center.Spiral(radius)
+
+		// Same pick as indexing the spiral with the same random value
+		a, b := NewRNG(uint64(radius)), NewRNG(uint64(radius))
+		for i := 0; i < 200; i++ {
+			h := a.HexInRadius(center, radius)
+			assert.Equal(t, spiral[b.IntN(len(spiral))], h)
+		}
+	}
+
+	// Every hex is reachable with roughly equal frequency
+	r := NewRNG(8)
+	counts := make(map[Hex[int64]]int)
+	for i := 0; i < 19000; i++ {
+		counts[r.HexInRadius(center, 2)]++
+	}
+	assert.Len(t, counts, 19)
+	for _, count := range counts {
+		assert.InDelta(t, 1000, count, 150)
+	}
+}
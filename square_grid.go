@@ -0,0 +1,127 @@
+package maths
+
+import (
+	"math"
+)
+
+var (
+	// squareDirections4 are the edge neighbours of a cell, clockwise from the right on a Y-down screen
+	squareDirections4 = []Vector2D[int64]{{X: 1, Y: 0}, {X: 0, Y: 1}, {X: -1, Y: 0}, {X: 0, Y: -1}}
+	// squareDirections8 are the edge and corner neighbours of a cell, clockwise from the right on a Y-down screen
+	squareDirections8 = []Vector2D[int64]{
+		{X: 1, Y: 0}, {X: 1, Y: 1}, {X: 0, Y: 1}, {X: -1, Y: 1},
+		{X: -1, Y: 0}, {X: -1, Y: -1}, {X: 0, Y: -1}, {X: 1, Y: -1},
+	}
+)
+
+// SquareGrid is a grid of axis aligned rectangular cells with camera integration.
+// Cell (0, 0) covers the area from Origin to Origin + CellSize.
+type SquareGrid struct {
+	CellSize Vector2D[float64]
+	Origin   Vector2D[float64]
+}
+
+// NewSquareGrid creates a new square grid with the cell size and the origin at zero
+func NewSquareGrid(cellSize Vector2D[float64]) *SquareGrid {
+	return &SquareGrid{CellSize: cellSize}
+}
+
+// CellToWorld returns the world position of the cell center
+func (grid *SquareGrid) CellToWorld(cell Vector2D[int64]) Vector2D[float64] {
+	return Vector2D[float64]{
+		X: grid.Origin.X + (float64(cell.X)+0.5)*grid.CellSize.X,
+		Y: grid.Origin.Y + (float64(cell.Y)+0.5)*grid.CellSize.Y,
+	}
+}
+
+// WorldToCell returns the cell containing the world position
+func (grid *SquareGrid) WorldToCell(worldPos Vector2D[float64]) Vector2D[int64] {
+	return Vector2D[int64]{
+		X: int64(math.Floor((worldPos.X - grid.Origin.X) / grid.CellSize.X)),
+		Y: int64(math.Floor((worldPos.Y - grid.Origin.Y) / grid.CellSize.Y)),
+	}
+}
+
+// WorldToScreen converts world coordinates to screen coordinates using camera data
+func (grid *SquareGrid) WorldToScreen(worldPos Vector2D[float64], camera Camera) Vector2D[float64] {
+	return worldToScreen(worldPos, camera)
+}
+
+// ScreenToWorld converts screen coordinates to world coordinates using camera data
+func (grid *SquareGrid) ScreenToWorld(screenPos Vector2D[float64], camera Camera) Vector2D[float64] {
+	return screenToWorld(screenPos, camera)
+}
+
+// CellToScreen returns the screen position of the cell center
+func (grid *SquareGrid) CellToScreen(cell Vector2D[int64], camera Camera) Vector2D[float64] {
+	return worldToScreen(grid.CellToWorld(cell), camera)
+}
+
+// ScreenToCell returns the cell under the screen position
+func (grid *SquareGrid) ScreenToCell(screenPos Vector2D[float64], camera Camera) Vector2D[int64] {
+	return grid.WorldToCell(screenToWorld(screenPos, camera))
+}
+
+// GetVisibleCells returns all cells overlapping the camera view row by row
+func (grid *SquareGrid) GetVisibleCells(camera Camera) []Vector2D[int64] {
+	view := cameraView(camera)
+	start := grid.WorldToCell(view.Min)
+	end := grid.WorldToCell(view.Max)
+
+	cells := make([]Vector2D[int64], 0, (end.X-start.X+1)*(end.Y-start.Y+1))
+	for y := start.Y; y <= end.Y; y++ {
+		for x := start.X; x <= end.X; x++ {
+			cells = append(cells, Vector2D[int64]{X: x, Y: y})
+		}
+	}
+	return cells
+}
+
+// CellCorners returns the corners of the cell in world coordinates, clockwise from the top left on a Y-down screen
+func (grid *SquareGrid) CellCorners(cell Vector2D[int64]) []Vector2D[float64] {
+	minX := grid.Origin.X + float64(cell.X)*grid.CellSize.X
+	minY := grid.Origin.Y + float64(cell.Y)*grid.CellSize.Y
+	maxX, maxY := minX+grid.CellSize.X, minY+grid.CellSize.Y
+
+	return []Vector2D[float64]{{X: minX, Y: minY}, {X: maxX, Y: minY}, {X: maxX, Y: maxY}, {X: minX, Y: maxY}}
+}
+
+// CellCornersScreen returns the corners of the cell in screen coordinates
+func (grid *SquareGrid) CellCornersScreen(cell Vector2D[int64], camera Camera) []Vector2D[float64] {
+	corners := grid.CellCorners(cell)
+	for i, corner := range corners {
+		corners[i] = worldToScreen(corner, camera)
+	}
+	return corners
+}
+
+// CellImageToScreen calculates the screen position and scale factor for an image in a cell.
+// The image keeps its aspect ratio and fits into the cell.
+func (grid *SquareGrid) CellImageToScreen(
+	cell Vector2D[int64],
+	imageDefaultSize Vector2D[float64],
+	camera Camera,
+) (centerPosition Vector2D[float64], imageScaleFactor Vector2D[float64]) {
+	centerPosition = grid.CellToScreen(cell, camera)
+	cellSize := Vector2D[float64]{X: grid.CellSize.X * camera.GetZoom(), Y: grid.CellSize.Y * camera.GetZoom()}
+	return centerPosition, imageScale(cellSize, imageDefaultSize)
+}
+
+// Neighbours4 returns the cells sharing an edge with the cell
+func (grid *SquareGrid) Neighbours4(cell Vector2D[int64]) []Vector2D[int64] {
+	return offsetCells(cell, squareDirections4)
+}
+
+// Neighbours8 returns the cells sharing an edge or a corner with the cell
+func (grid *SquareGrid) Neighbours8(cell Vector2D[int64]) []Vector2D[int64] {
+	return offsetCells(cell, squareDirections8)
+}
+
+// offsetCells returns the cell moved by every offset
+func offsetCells(cell Vector2D[int64], offsets []Vector2D[int64]) []Vector2D[int64] {
+	cells := make([]Vector2D[int64], len(offsets))
+	for i, offset := range offsets {
+		cells[i] = cell.Add(offset)
+	}
+	return cells
+}
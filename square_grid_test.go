@@ -0,0 +1,101 @@
+package maths
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testCamera is a camera with fixed values
+type testCamera struct {
+	position Vector2D[float64]
+	zoom     float64
+	size     Vector2D[float64]
+}
+
+func (c testCamera) GetPosition() Vector2D[float64] { return c.position }
+func (c testCamera) GetZoom() float64               { return c.zoom }
+func (c testCamera) GetSize() Vector2D[float64]     { return c.size }
+
+func TestSquareGridWorld(t *testing.T) {
+	t.Parallel()
+
+	grid := NewSquareGrid(NewVector2D[float64](32, 16))
+	grid.Origin = NewVector2D[float64](8, 4)
+
+	tests := []struct {
+		name  string
+		world Vector2D[float64]
+		cell  Vector2D[int64]
+	}{
+		{name: "Origin", world: NewVector2D[float64](8, 4), cell: NewVector2D[int64](0, 0)},
+		{name: "Inside", world: NewVector2D[float64](50, 30), cell: NewVector2D[int64](1, 1)},
+		{name: "Negative", world: NewVector2D[float64](7, 3), cell: NewVector2D[int64](-1, -1)},
+		{name: "Far negative", world: NewVector2D[float64](-57, -13), cell: NewVector2D[int64](-3, -2)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.cell, grid.WorldToCell(tt.world))
+			assert.Equal(t, tt.cell, grid.WorldToCell(grid.CellToWorld(tt.cell)))
+		})
+	}
+
+	assert.Equal(t, NewVector2D[float64](56, 28), grid.CellToWorld(NewVector2D[int64](1, 1)))
+}
+
+func TestSquareGridCamera(t *testing.T) {
+	t.Parallel()
+
+	grid := NewSquareGrid(NewVector2D[float64](32, 32))
+	camera := testCamera{position: NewVector2D[float64](100, 50), zoom: 2, size: NewVector2D[float64](800, 600)}
+
+	cell := NewVector2D[int64](3, 1)
+	screen := grid.CellToScreen(cell, camera)
+	assert.Equal(t, NewVector2D[float64](400+(112-100)*2, 300+(48-50)*2), screen)
+	assert.Equal(t, cell, grid.ScreenToCell(screen, camera))
+	assert.InDelta(t, 10.0, grid.ScreenToWorld(grid.WorldToScreen(NewVector2D[float64](10, 20), camera), camera).X, 1e-9)
+
+	corners := grid.CellCornersScreen(cell, camera)
+	assert.Equal(t, []Vector2D[float64]{
+		grid.WorldToScreen(NewVector2D[float64](96, 32), camera),
+		grid.WorldToScreen(NewVector2D[float64](128, 32), camera),
+		grid.WorldToScreen(NewVector2D[float64](128, 64), camera),
+		grid.WorldToScreen(NewVector2D[float64](96, 64), camera),
+	}, corners)
+
+	center, scale := grid.CellImageToScreen(cell, NewVector2D[float64](128, 64), camera)
+	assert.Equal(t, screen, center)
+	assert.Equal(t, NewVector2D[float64](0.5, 0.5), scale)
+}
+
+func TestSquareGridVisibleCells(t *testing.T) {
+	t.Parallel()
+
+	grid := NewSquareGrid(NewVector2D[float64](32, 32))
+	camera := testCamera{position: NewVector2D[float64](0, 0), zoom: 1, size: NewVector2D[float64](128, 64)}
+
+	// The view from (-64, -32) to (64, 32) touches 5 columns and 3 rows
+	cells := grid.GetVisibleCells(camera)
+	assert.Len(t, cells, 15)
+	assert.Equal(t, NewVector2D[int64](-2, -1), cells[0])
+	assert.Equal(t, NewVector2D[int64](2, 1), cells[len(cells)-1])
+}
+
+func TestSquareGridNeighbours(t *testing.T) {
+	t.Parallel()
+
+	grid := NewSquareGrid(NewVector2D[float64](1, 1))
+	cell := NewVector2D[int64](2, -1)
+
+	assert.Equal(t, []Vector2D[int64]{{X: 3, Y: -1}, {X: 2, Y: 0}, {X: 1, Y: -1}, {X: 2, Y: -2}}, grid.Neighbours4(cell))
+
+	neighbours := grid.Neighbours8(cell)
+	assert.Len(t, neighbours, 8)
+	for _, n := range neighbours {
+		assert.LessOrEqual(t, n.Distance(cell), 1.5)
+		assert.NotEqual(t, cell, n)
+	}
+}
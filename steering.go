@@ -0,0 +1,253 @@
+package maths
+
+import (
+	"math"
+)
+
+// steeringFeelerAngle is the angle between the center feeler and the side feelers of wall avoidance
+const steeringFeelerAngle = math.Pi / 6
+
+// SteeringAgent is the moving state of an agent using steering behaviours.
+// Behaviours return a steering force which is applied with Apply.
+type SteeringAgent struct {
+	Position Vector2D[float64]
+	Velocity Vector2D[float64]
+	MaxSpeed float64
+	MaxForce float64
+}
+
+// SteeringObstacle is a circular obstacle to avoid
+type SteeringObstacle struct {
+	Center Vector2D[float64]
+	Radius float64
+}
+
+// WeightedForce is the result of a behaviour with its weight for blending
+type WeightedForce struct {
+	Force  Vector2D[float64]
+	Weight float64
+}
+
+// Apply accelerates the agent by the force limited to MaxForce and moves it for dt
+func (agent *SteeringAgent) Apply(force Vector2D[float64], dt float64) {
+	force = limitLength(force, agent.MaxForce)
+	agent.Velocity = limitLength(agent.Velocity.Add(force.Multiply(dt)), agent.MaxSpeed)
+	agent.Position = agent.Position.Add(agent.Velocity.Multiply(dt))
+}
+
+// Seek steers towards the target at full speed
+func (agent *SteeringAgent) Seek(target Vector2D[float64]) Vector2D[float64] {
+	desired := target.Subtract(agent.Position).Normalize().Multiply(agent.MaxSpeed)
+	return desired.Subtract(agent.Velocity)
+}
+
+// Flee steers away from the threat at full speed
+func (agent *SteeringAgent) Flee(threat Vector2D[float64]) Vector2D[float64] {
+	desired := agent.Position.Subtract(threat).Normalize().Multiply(agent.MaxSpeed)
+	return desired.Subtract(agent.Velocity)
+}
+
+// Arrive steers towards the target and slows down within the slowing radius to stop on it
+func (agent *SteeringAgent) Arrive(target Vector2D[float64], slowingRadius float64) Vector2D[float64] {
+	offset := target.Subtract(agent.Position)
+	distance := offset.Length()
+	speed := agent.MaxSpeed
+	if distance < slowingRadius {
+		speed *= distance / slowingRadius
+	}
+	return offset.Normalize().Multiply(speed).Subtract(agent.Velocity)
+}
+
+// Pursue seeks the position where a moving target will be when the agent reaches it
+func (agent *SteeringAgent) Pursue(target, targetVelocity Vector2D[float64]) Vector2D[float64] {
+	return agent.Seek(agent.predict(target, targetVelocity))
+}
+
+// Evade flees from the position where a moving threat will be when it reaches the agent
+func (agent *SteeringAgent) Evade(threat, threatVelocity Vector2D[float64]) Vector2D[float64] {
+	return agent.Flee(agent.predict(threat, threatVelocity))
+}
+
+// Separation steers away from neighbours within the radius, stronger for closer ones
+func (agent *SteeringAgent) Separation(neighbours []SteeringAgent, radius float64) Vector2D[float64] {
+	var force Vector2D[float64]
+	for _, other := range neighbours {
+		offset := agent.Position.Subtract(other.Position)
+		distance := offset.Length()
+		if distance == 0 || distance >= radius {
+			continue
+		}
+		force = force.Add(offset.Normalize().Multiply(agent.MaxSpeed * (1 - distance/radius)))
+	}
+	return force
+}
+
+// Alignment steers towards the average heading of the neighbours within the radius
+func (agent *SteeringAgent) Alignment(neighbours []SteeringAgent, radius float64) Vector2D[float64] {
+	var sum Vector2D[float64]
+	count := 0
+	for _, other := range neighbours {
+		if other.Position.Distance(agent.Position) < radius {
+			sum = sum.Add(other.Velocity)
+			count++
+		}
+	}
+	if count == 0 {
+		return Vector2D[float64]{}
+	}
+	return sum.Normalize().Multiply(agent.MaxSpeed).Subtract(agent.Velocity)
+}
+
+// Cohesion steers towards the center of the neighbours within the radius
+func (agent *SteeringAgent) Cohesion(neighbours []SteeringAgent, radius float64) Vector2D[float64] {
+	var sum Vector2D[float64]
+	count := 0
+	for _, other := range neighbours {
+		if other.Position.Distance(agent.Position) < radius {
+			sum = sum.Add(other.Position)
+			count++
+		}
+	}
+	if count == 0 {
+		return Vector2D[float64]{}
+	}
+	return agent.Seek(sum.Divide(float64(count)))
+}
+
+// AvoidObstacles steers sideways away from the closest obstacle in the corridor
+// of the agent radius in front of the agent up to lookAhead
+func (agent *SteeringAgent) AvoidObstacles(obstacles []SteeringObstacle, radius, lookAhead float64) Vector2D[float64] {
+	heading := agent.Velocity.Normalize()
+	if heading == (Vector2D[float64]{}) {
+		return Vector2D[float64]{}
+	}
+
+	closest := -1
+	closestAhead, closestSide := math.Inf(1), 0.0
+	for i, obstacle := range obstacles {
+		offset := obstacle.Center.Subtract(agent.Position)
+		ahead := offset.Dot(heading)
+		side := heading.Cross(offset)
+		if ahead <= 0 || ahead-obstacle.Radius > lookAhead || math.Abs(side) >= obstacle.Radius+radius {
+			continue
+		}
+		if ahead < closestAhead {
+			closest, closestAhead, closestSide = i, ahead, side
+		}
+	}
+	if closest < 0 {
+		return Vector2D[float64]{}
+	}
+
+	// Push to the side away from the obstacle, stronger when it is close
+	away := Vector2D[float64]{X: heading.Y, Y: -heading.X}
+	if closestSide < 0 {
+		away = away.Multiply(-1)
+	}
+	strength := 1 + (lookAhead-closestAhead)/lookAhead
+	return away.Multiply(agent.MaxForce * strength)
+}
+
+// AvoidHexWalls steers away from blocked hexes of the layout detected by three
+// feelers of length lookAhead in front of the agent. The force pushes back over
+// the edge of the first blocked hex a feeler enters, proportional to the depth.
+// Layouts with a zero size or zoom give no force.
+func (agent *SteeringAgent) AvoidHexWalls(layout HexLayout, blocked func(h Hex[int64]) bool, lookAhead float64) Vector2D[float64] {
+	heading := agent.Velocity.Normalize()
+	step := math.Min(layout.Size.X, layout.Size.Y) * layout.Zoom / 4
+	if heading == (Vector2D[float64]{}) || lookAhead <= 0 || !(step > 0) {
+		return Vector2D[float64]{}
+	}
+
+	var force Vector2D[float64]
+	for _, angle := range []float64{0, -steeringFeelerAngle, steeringFeelerAngle} {
+		feeler := rotateVector(heading, angle)
+		length := lookAhead
+		if angle != 0 {
+			length /= 2
+		}
+
+		previous := hexRound(layout.Vector2DToHex(agent.Position))
+		for distance := step; distance <= length; distance += step {
+			current := hexRound(layout.Vector2DToHex(agent.Position.Add(feeler.Multiply(distance))))
+			if current == previous {
+				continue
+			}
+			if blocked(current) {
+				normal := layout.HexToVector2D(previous.ToFloat()).Subtract(layout.HexToVector2D(current.ToFloat())).Normalize()
+				force = force.Add(normal.Multiply(agent.MaxForce * (length - distance + step) / length))
+				break
+			}
+			previous = current
+		}
+	}
+	return force
+}
+
+// Wander produces a smoothly changing random steering force
+// by moving a target on a circle in front of the agent
+type Wander struct {
+	Distance float64
+	Radius   float64
+	Jitter   float64
+	angle    float64
+}
+
+// Force moves the wander target by a random amount and seeks it
+func (wander *Wander) Force(agent *SteeringAgent, rng *RNG) Vector2D[float64] {
+	wander.angle += rng.Float64Range(-wander.Jitter, wander.Jitter)
+
+	heading := agent.Velocity.Normalize()
+	if heading == (Vector2D[float64]{}) {
+		heading = Vector2D[float64]{X: 1}
+	}
+	center := agent.Position.Add(heading.Multiply(wander.Distance))
+	offset := Vector2D[float64]{X: math.Cos(wander.angle), Y: math.Sin(wander.angle)}.Multiply(wander.Radius)
+	return agent.Seek(center.Add(offset))
+}
+
+// BlendWeighted sums the weighted forces and limits the result to maxForce
+func BlendWeighted(maxForce float64, forces ...WeightedForce) Vector2D[float64] {
+	var sum Vector2D[float64]
+	for _, f := range forces {
+		sum = sum.Add(f.Force.Multiply(f.Weight))
+	}
+	return limitLength(sum, maxForce)
+}
+
+// BlendPrioritized adds the weighted forces in order until maxForce is used up,
+// so forces listed first like obstacle avoidance win over later ones
+func BlendPrioritized(maxForce float64, forces ...WeightedForce) Vector2D[float64] {
+	var sum Vector2D[float64]
+	for _, f := range forces {
+		left := maxForce - sum.Length()
+		if left <= 0 {
+			break
+		}
+		sum = sum.Add(limitLength(f.Force.Multiply(f.Weight), left))
+	}
+	return sum
+}
+
+// predict returns the position of a moving target when the agent could reach it
+func (agent *SteeringAgent) predict(target, targetVelocity Vector2D[float64]) Vector2D[float64] {
+	if agent.MaxSpeed <= 0 {
+		return target
+	}
+	time := target.Distance(agent.Position) / agent.MaxSpeed
+	return target.Add(targetVelocity.Multiply(time))
+}
+
+// limitLength shortens the vector to at most the maximum length
+func limitLength(v Vector2D[float64], maxLength float64) Vector2D[float64] {
+	if v.Length() <= maxLength {
+		return v
+	}
+	return v.Normalize().Multiply(maxLength)
+}
+
+// rotateVector rotates the vector counter-clockwise by the angle in radians
+func rotateVector(v Vector2D[float64], angle float64) Vector2D[float64] {
+	sin, cos := math.Sincos(angle)
+	return Vector2D[float64]{X: v.X*cos - v.Y*sin, Y: v.X*sin + v.Y*cos}
+}
@@ -0,0 +1,155 @@
+package maths
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestAgent creates an agent at the position moving with the velocity
+func newTestAgent(position, velocity Vector2D[float64]) *SteeringAgent {
+	return &SteeringAgent{Position: position, Velocity: velocity, MaxSpeed: 10, MaxForce: 20}
+}
+
+func TestSteeringSeekAndFlee(t *testing.T) {
+	t.Parallel()
+
+	agent := newTestAgent(Vector2D[float64]{}, Vector2D[float64]{X: 0, Y: 5})
+	assert.Equal(t, Vector2D[float64]{X: 10, Y: -5}, agent.Seek(Vector2D[float64]{X: 50, Y: 0}))
+	assert.Equal(t, Vector2D[float64]{X: -10, Y: -5}, agent.Flee(Vector2D[float64]{X: 50, Y: 0}))
+
+	// Pursuit aims ahead of a moving target, evasion away from that point
+	pursue := agent.Pursue(Vector2D[float64]{X: 50, Y: 0}, Vector2D[float64]{X: 0, Y: 10})
+	assert.Greater(t, pursue.Y, agent.Seek(Vector2D[float64]{X: 50, Y: 0}).Y)
+	evade := agent.Evade(Vector2D[float64]{X: 50, Y: 0}, Vector2D[float64]{X: 0, Y: 10})
+	assert.Less(t, evade.Y, agent.Flee(Vector2D[float64]{X: 50, Y: 0}).Y)
+}
+
+func TestSteeringArrive(t *testing.T) {
+	t.Parallel()
+
+	agent := newTestAgent(Vector2D[float64]{}, Vector2D[float64]{})
+	target := Vector2D[float64]{X: 30, Y: 40}
+	for i := 0; i < 2000; i++ {
+		agent.Apply(agent.Arrive(target, 20), 0.01)
+		assert.LessOrEqual(t, agent.Velocity.Length(), agent.MaxSpeed+1e-9)
+	}
+	assert.InDelta(t, 0, agent.Position.Distance(target), 0.1)
+	assert.InDelta(t, 0, agent.Velocity.Length(), 0.1)
+}
+
+func TestSteeringFlocking(t *testing.T) {
+	t.Parallel()
+
+	agent := newTestAgent(Vector2D[float64]{}, Vector2D[float64]{X: 1, Y: 0})
+	neighbours := []SteeringAgent{
+		{Position: Vector2D[float64]{X: 2, Y: 0}, Velocity: Vector2D[float64]{X: 0, Y: 4}},
+		{Position: Vector2D[float64]{X: 0, Y: 4}, Velocity: Vector2D[float64]{X: 0, Y: 6}},
+		{Position: Vector2D[float64]{X: 100, Y: 0}, Velocity: Vector2D[float64]{X: -9, Y: 0}},
+	}
+
+	separation := agent.Separation(neighbours, 5)
+	assert.Less(t, separation.X, 0.0)
+	assert.Less(t, separation.Y, 0.0)
+	assert.Less(t, separation.X, separation.Y, "closer neighbours push stronger")
+
+	alignment := agent.Alignment(neighbours, 5)
+	assert.Equal(t, Vector2D[float64]{X: -1, Y: 10}, alignment)
+
+	cohesion := agent.Cohesion(neighbours, 5)
+	assert.Equal(t, agent.Seek(Vector2D[float64]{X: 1, Y: 2}), cohesion)
+
+	assert.Equal(t, Vector2D[float64]{}, agent.Cohesion(nil, 5))
+	assert.Equal(t, Vector2D[float64]{}, agent.Alignment(nil, 5))
+}
+
+func TestSteeringAvoidObstacles(t *testing.T) {
+	t.Parallel()
+
+	agent := newTestAgent(Vector2D[float64]{}, Vector2D[float64]{X: 5, Y: 0})
+	obstacles := []SteeringObstacle{
+		{Center: Vector2D[float64]{X: 20, Y: 1}, Radius: 2},
+		{Center: Vector2D[float64]{X: 10, Y: -1}, Radius: 1},
+		{Center: Vector2D[float64]{X: -5, Y: 0}, Radius: 3},
+	}
+
+	// The closest obstacle in front is below, push upwards
+	force := agent.AvoidObstacles(obstacles, 1, 30)
+	assert.Zero(t, force.X)
+	assert.Greater(t, force.Y, 0.0)
+
+	assert.Equal(t, Vector2D[float64]{}, agent.AvoidObstacles(obstacles[:1], 1, 10))
+	assert.Equal(t, Vector2D[float64]{}, agent.AvoidObstacles(obstacles[2:], 1, 30))
+}
+
+func TestSteeringAvoidHexWalls(t *testing.T) {
+	t.Parallel()
+
+	layout := NewHexLayout(LayoutFlat, Vector2D[float64]{X: 10, Y: 10}, Vector2D[float64]{}, 1)
+	wall := Hex[int64]{Q: 2, R: -1}
+	blocked := func(h Hex[int64]) bool {
+		return h == wall
+	}
+
+	// Heading straight towards the blocked hex
+	target := layout.HexToVector2D(wall.ToFloat())
+	agent := newTestAgent(Vector2D[float64]{}, target.Normalize().Multiply(5))
+	force := agent.AvoidHexWalls(layout, blocked, 40)
+	assert.Less(t, force.Dot(agent.Velocity), 0.0, "pushes back from the wall")
+	assert.LessOrEqual(t, force.Length(), 3*agent.MaxForce)
+
+	// Closer walls push stronger
+	closer := newTestAgent(target.Multiply(0.4), agent.Velocity)
+	assert.Greater(t, closer.AvoidHexWalls(layout, blocked, 40).Length(), force.Length())
+
+	// Heading away from the wall
+	away := newTestAgent(Vector2D[float64]{}, agent.Velocity.Multiply(-1))
+	assert.Equal(t, Vector2D[float64]{}, away.AvoidHexWalls(layout, blocked, 40))
+
+	// Layouts without a size have no hexes to step through
+	for _, empty := range []HexLayout{
+		NewHexLayout(LayoutFlat, Vector2D[float64]{X: 10, Y: 10}, Vector2D[float64]{}, 0),
+		NewHexLayout(LayoutFlat, Vector2D[float64]{}, Vector2D[float64]{}, 1),
+		NewHexLayout(LayoutFlat, Vector2D[float64]{X: 10, Y: math.NaN()}, Vector2D[float64]{}, 1),
+	} {
+		assert.Equal(t, Vector2D[float64]{}, agent.AvoidHexWalls(empty, blocked, 40))
+	}
+}
+
+func TestSteeringBlend(t *testing.T) {
+	t.Parallel()
+
+	forces := []WeightedForce{
+		{Force: Vector2D[float64]{X: 0, Y: 8}, Weight: 1},
+		{Force: Vector2D[float64]{X: 6, Y: 0}, Weight: 0.5},
+		{Force: Vector2D[float64]{X: 100, Y: 0}, Weight: 1},
+	}
+
+	assert.Equal(t, Vector2D[float64]{X: 3, Y: 8}, BlendWeighted(100, forces[:2]...))
+	assert.InDelta(t, 10, BlendWeighted(10, forces...).Length(), 1e-9)
+
+	// The first force gets its full share, the rest fills up what is left
+	prioritized := BlendPrioritized(10, forces...)
+	assert.InDelta(t, 8, prioritized.Y, 1e-9)
+	assert.InDelta(t, 2+10-math.Sqrt(68), prioritized.X, 1e-9)
+	assert.InDelta(t, 0, BlendPrioritized(8, forces...).X, 1e-9)
+}
+
+func TestSteeringWander(t *testing.T) {
+	t.Parallel()
+
+	a := newTestAgent(Vector2D[float64]{}, Vector2D[float64]{X: 1, Y: 0})
+	b := newTestAgent(Vector2D[float64]{}, Vector2D[float64]{X: 1, Y: 0})
+	wanderA := &Wander{Distance: 5, Radius: 2, Jitter: 0.5}
+	wanderB := &Wander{Distance: 5, Radius: 2, Jitter: 0.5}
+	rngA, rngB := NewRNG(1), NewRNG(1)
+
+	for i := 0; i < 100; i++ {
+		a.Apply(wanderA.Force(a, rngA), 0.1)
+		b.Apply(wanderB.Force(b, rngB), 0.1)
+		assert.LessOrEqual(t, a.Velocity.Length(), a.MaxSpeed+1e-9)
+	}
+	assert.Equal(t, a.Position, b.Position)
+	assert.Greater(t, a.Position.Length(), 0.0)
+}
@@ -0,0 +1,204 @@
+package maths
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SVGStyle is the fill and stroke of an SVG element, empty fields use the default of the element
+type SVGStyle struct {
+	Fill        string
+	Stroke      string
+	StrokeWidth float64
+}
+
+// HexSVG draws hexes and overlays like paths, points and polygons into a standalone
+// SVG, e.g. to debug pathfinding or to write golden files in tests. Hexes are drawn
+// first with their labels on top, then the overlays in the order they were added.
+type HexSVG struct {
+	Layout HexLayout
+	// Padding is the space around the drawing in world units
+	Padding float64
+
+	hexes    HexMap[svgHex]
+	overlays []svgOverlay
+}
+
+// svgHex is the style and label of a hex
+type svgHex struct {
+	style SVGStyle
+	label string
+}
+
+// svgOverlay is a shape drawn on top of the hexes
+type svgOverlay struct {
+	element string
+	points  []Vector2D[float64]
+	radius  float64
+	style   SVGStyle
+}
+
+// NewHexSVG creates a new empty drawing with a padding of half a hex
+func NewHexSVG(layout HexLayout) *HexSVG {
+	size := layout.Size.Multiply(layout.Zoom)
+	return &HexSVG{Layout: layout, Padding: math.Max(size.X, size.Y) / 2, hexes: make(HexMap[svgHex])}
+}
+
+// AddHex draws the hex with the style and a label in its center, adding a hex again replaces it
+func (s *HexSVG) AddHex(h Hex[int64], style SVGStyle, label string) {
+	s.hexes[h] = svgHex{style: style, label: label}
+}
+
+// AddHexes draws the hexes with the same style and without labels
+func (s *HexSVG) AddHexes(hexes []Hex[int64], style SVGStyle) {
+	for _, h := range hexes {
+		s.AddHex(h, style, "")
+	}
+}
+
+// AddPath draws a line through the centers of the hexes
+func (s *HexSVG) AddPath(path []Hex[int64], style SVGStyle) {
+	points := make([]Vector2D[float64], len(path))
+	for i, h := range path {
+		points[i] = s.Layout.HexToVector2D(h.ToFloat())
+	}
+	s.overlays = append(s.overlays, svgOverlay{element: "polyline", points: points, style: style})
+}
+
+// AddPoint draws a circle at the world position
+func (s *HexSVG) AddPoint(p Vector2D[float64], radius float64, style SVGStyle) {
+	s.overlays = append(s.overlays, svgOverlay{element: "circle", points: []Vector2D[float64]{p}, radius: radius, style: style})
+}
+
+// AddPolygon draws a closed polygon through the world positions
+func (s *HexSVG) AddPolygon(points []Vector2D[float64], style SVGStyle) {
+	s.overlays = append(s.overlays, svgOverlay{element: "polygon", points: points, style: style})
+}
+
+// WriteTo writes the drawing as a standalone SVG document. The output only
+// depends on the content, so it can be compared with golden files.
+func (s *HexSVG) WriteTo(w io.Writer) (int64, error) {
+	view := s.bounds().Expand(s.Padding)
+	size := s.Layout.Size.Multiply(s.Layout.Zoom)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="%s %s %s %s" width="%s" height="%s">`+"\n",
+		svgNumber(view.Min.X), svgNumber(view.Min.Y), svgNumber(view.Width()), svgNumber(view.Height()),
+		svgNumber(view.Width()), svgNumber(view.Height()))
+
+	hexes := s.hexes.Hexes()
+	b.WriteString(`<g class="hexes">` + "\n")
+	for _, h := range hexes {
+		style := withDefaults(s.hexes[h].style, SVGStyle{Fill: "#ffffff", Stroke: "#444444", StrokeWidth: 1})
+		fmt.Fprintf(&b, `<polygon points="%s"%s/>`+"\n", svgPoints(s.Layout.HexCorners(h.ToFloat())), svgAttributes(style))
+	}
+	b.WriteString("</g>\n")
+
+	fmt.Fprintf(&b, `<g class="labels" font-family="sans-serif" font-size="%s" text-anchor="middle" dominant-baseline="central">`+"\n",
+		svgNumber(math.Min(size.X, size.Y)/2))
+	for _, h := range hexes {
+		if label := s.hexes[h].label; label != "" {
+			center := s.Layout.HexToVector2D(h.ToFloat())
+			fmt.Fprintf(&b, `<text x="%s" y="%s">`, svgNumber(center.X), svgNumber(center.Y))
+			_ = xml.EscapeText(&b, []byte(label))
+			b.WriteString("</text>\n")
+		}
+	}
+	b.WriteString("</g>\n")
+
+	b.WriteString(`<g class="overlays">` + "\n")
+	for _, overlay := range s.overlays {
+		switch overlay.element {
+		case "circle":
+			style := withDefaults(overlay.style, SVGStyle{Fill: "#3366cc"})
+			fmt.Fprintf(&b, `<circle cx="%s" cy="%s" r="%s"%s/>`+"\n",
+				svgNumber(overlay.points[0].X), svgNumber(overlay.points[0].Y), svgNumber(overlay.radius), svgAttributes(style))
+		case "polyline":
+			style := withDefaults(overlay.style, SVGStyle{Fill: "none", Stroke: "#cc3333", StrokeWidth: 2})
+			fmt.Fprintf(&b, `<polyline points="%s"%s stroke-linejoin="round" stroke-linecap="round"/>`+"\n",
+				svgPoints(overlay.points), svgAttributes(style))
+		default:
+			style := withDefaults(overlay.style, SVGStyle{Fill: "none", Stroke: "#33aa33", StrokeWidth: 2})
+			fmt.Fprintf(&b, `<polygon points="%s"%s/>`+"\n", svgPoints(overlay.points), svgAttributes(style))
+		}
+	}
+	b.WriteString("</g>\n</svg>\n")
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// bounds returns the world rect covering all hexes and overlays
+func (s *HexSVG) bounds() Rect {
+	var points []Vector2D[float64]
+	for h := range s.hexes {
+		points = append(points, s.Layout.HexCorners(h.ToFloat())...)
+	}
+	for _, overlay := range s.overlays {
+		for _, p := range overlay.points {
+			points = append(points, p.Add(NewVector2D(-overlay.radius, -overlay.radius)), p.Add(NewVector2D(overlay.radius, overlay.radius)))
+		}
+	}
+	if len(points) == 0 {
+		return Rect{}
+	}
+
+	bounds := NewRect(points[0], points[0])
+	for _, p := range points[1:] {
+		bounds = bounds.Union(NewRect(p, p))
+	}
+	return bounds
+}
+
+// withDefaults fills the empty fields of the style
+func withDefaults(style, defaults SVGStyle) SVGStyle {
+	if style.Fill == "" {
+		style.Fill = defaults.Fill
+	}
+	if style.Stroke == "" {
+		style.Stroke = defaults.Stroke
+	}
+	if style.StrokeWidth == 0 {
+		style.StrokeWidth = defaults.StrokeWidth
+	}
+	return style
+}
+
+// svgAttributes returns the style as SVG attributes, leaving out empty fields
+func svgAttributes(style SVGStyle) string {
+	var b strings.Builder
+	for _, attribute := range []struct{ name, value string }{{"fill", style.Fill}, {"stroke", style.Stroke}} {
+		if attribute.value != "" {
+			fmt.Fprintf(&b, ` %s="`, attribute.name)
+			_ = xml.EscapeText(&b, []byte(attribute.value))
+			b.WriteString(`"`)
+		}
+	}
+	if style.Stroke != "" && style.StrokeWidth != 0 {
+		fmt.Fprintf(&b, ` stroke-width="%s"`, svgNumber(style.StrokeWidth))
+	}
+	return b.String()
+}
+
+// svgPoints formats the positions as the points attribute of polygons and polylines
+func svgPoints(points []Vector2D[float64]) string {
+	formatted := make([]string, len(points))
+	for i, p := range points {
+		formatted[i] = svgNumber(p.X) + "," + svgNumber(p.Y)
+	}
+	return strings.Join(formatted, " ")
+}
+
+// svgNumber formats the number with at most two decimals, so the output is stable across platforms
+func svgNumber(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 2, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
@@ -0,0 +1,137 @@
+package maths
+
+import (
+	"bytes"
+	"encoding/xml"
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata")
+
+// assertGolden compares the output with a golden file, run `go test -update` to rewrite it
+func assertGolden(t *testing.T, name string, output []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", "svg", name)
+	if *updateGolden {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, output, 0o644))
+	}
+	golden, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(golden), string(output))
+}
+
+func TestHexSVGGolden(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Pathfinding", func(t *testing.T) {
+		t.Parallel()
+
+		layout := NewHexLayout(LayoutPointy, NewVector2D[float64](20, 20), NewVector2D[float64](0, 0), 1)
+		blocked := NewHexMap([]Hex[int64]{{Q: 0, R: -1}, {Q: 0, R: 0}, {Q: 0, R: 1}, {Q: 1, R: 1}}, true)
+		start, goal := Hex[int64]{Q: -2, R: 0}, Hex[int64]{Q: 2, R: 0}
+
+		svg := NewHexSVG(layout)
+		cost := func(h Hex[int64]) float64 {
+			if blocked.Has(h) || h.Distance(Hex[int64]{}) > 3 {
+				return -1
+			}
+			return 1
+		}
+		path, _, ok := GridAStar[Hex[int64]](NewHexTopology(layout), start, goal, cost, 1)
+		require.True(t, ok)
+
+		for _, h := range (Hex[int64]{}).Spiral(3) {
+			style := SVGStyle{}
+			if blocked.Has(h) {
+				style.Fill = "#555555"
+			}
+			svg.AddHex(h, style, strconv.Itoa(int(h.Distance(start))))
+		}
+		svg.AddPath(path, SVGStyle{})
+		svg.AddPoint(layout.HexToVector2D(start.ToFloat()), 5, SVGStyle{Fill: "#22aa22"})
+		svg.AddPoint(layout.HexToVector2D(goal.ToFloat()), 5, SVGStyle{})
+
+		var b bytes.Buffer
+		n, err := svg.WriteTo(&b)
+		require.NoError(t, err)
+		assert.Equal(t, int64(b.Len()), n)
+		assertGolden(t, "pathfinding.svg", b.Bytes())
+	})
+
+	t.Run("Overlays", func(t *testing.T) {
+		t.Parallel()
+
+		layout := NewHexLayout(LayoutFlat, NewVector2D[float64](10, 10), NewVector2D[float64](50, 50), 2)
+		svg := NewHexSVG(layout)
+		svg.AddHexes(NewHexChunking(HexChunkParallelogram, 3).ChunkHexes(Hex[int64]{}), SVGStyle{Fill: "#eeeeff", Stroke: "#8888cc", StrokeWidth: 0.5})
+		svg.AddHex(Hex[int64]{Q: 1, R: 1}, SVGStyle{Fill: "#ffcc00"}, `<q & "r">`)
+		svg.AddPolygon(layout.HexCorners(Hex[float64]{Q: 2, R: 0}), SVGStyle{Stroke: "#000000"})
+		svg.AddPolygon([]Vector2D[float64]{{X: 0, Y: 0}, {X: 200, Y: 0}, {X: 100, Y: 150}}, SVGStyle{})
+
+		var b bytes.Buffer
+		_, err := svg.WriteTo(&b)
+		require.NoError(t, err)
+		assertGolden(t, "overlays.svg", b.Bytes())
+	})
+}
+
+func TestHexSVG(t *testing.T) {
+	t.Parallel()
+
+	layout := NewHexLayout(LayoutPointy, NewVector2D[float64](10, 10), NewVector2D[float64](0, 0), 1)
+	svg := NewHexSVG(layout)
+	svg.AddHex(Hex[int64]{}, SVGStyle{}, "a")
+	svg.AddHex(Hex[int64]{}, SVGStyle{Fill: "red"}, "b")
+	svg.AddPoint(NewVector2D[float64](100, 0), 3, SVGStyle{})
+
+	var b bytes.Buffer
+	_, err := svg.WriteTo(&b)
+	require.NoError(t, err)
+
+	// The output is well formed XML with the hex replaced
+	var doc struct {
+		ViewBox string `xml:"viewBox,attr"`
+		Groups  []struct {
+			Polygons []struct {
+				Fill string `xml:"fill,attr"`
+			} `xml:"polygon"`
+			Texts   []string `xml:"text"`
+			Circles []struct {
+				R string `xml:"r,attr"`
+			} `xml:"circle"`
+		} `xml:"g"`
+	}
+	require.NoError(t, xml.Unmarshal(b.Bytes(), &doc))
+	require.Len(t, doc.Groups, 3)
+	assert.Len(t, doc.Groups[0].Polygons, 1)
+	assert.Equal(t, "red", doc.Groups[0].Polygons[0].Fill)
+	assert.Equal(t, []string{"b"}, doc.Groups[1].Texts)
+	assert.Len(t, doc.Groups[2].Circles, 1)
+
+	// The view covers the hex, the point and the padding of half a hex
+	assert.Equal(t, "-13.66 -15 121.66 30", doc.ViewBox)
+
+	// An empty drawing is still a valid document
+	b.Reset()
+	_, err = NewHexSVG(layout).WriteTo(&b)
+	require.NoError(t, err)
+	require.NoError(t, xml.Unmarshal(b.Bytes(), &doc))
+}
+
+func TestSVGNumber(t *testing.T) {
+	t.Parallel()
+
+	tests := map[float64]string{0: "0", -0.001: "0", 1.5: "1.5", 10: "10", -3.14159: "-3.14", 8.660254: "8.66", 100.005: "100"}
+	for value, expected := range tests {
+		assert.Equal(t, expected, svgNumber(value), value)
+	}
+}
@@ -0,0 +1,621 @@
+package maths
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidTiledMap is returned when a Tiled map is no finite hexagonal map or cannot be decoded
+var ErrInvalidTiledMap = errors.New("maths: invalid tiled map")
+
+// Stagger axes and indices of hexagonal Tiled maps
+const (
+	// TiledStaggerX staggers columns, the hexes are flat
+	TiledStaggerX = "x"
+	// TiledStaggerY staggers rows, the hexes are pointy
+	TiledStaggerY = "y"
+	// TiledStaggerOdd shifts the odd columns down or the odd rows right
+	TiledStaggerOdd = "odd"
+	// TiledStaggerEven shifts the even columns down or the even rows right
+	TiledStaggerEven = "even"
+)
+
+// TiledMap is a hexagonal map of the Tiled editor. Tiled addresses tiles by the
+// column and row of a staggered offset grid, the layers store them as hexes in
+// axial coordinates instead.
+type TiledMap struct {
+	Width, Height         int
+	TileWidth, TileHeight int
+	HexSideLength         int
+	StaggerAxis           string
+	StaggerIndex          string
+	Tilesets              []TiledTileset
+	Layers                []TiledLayer
+}
+
+// TiledTileset is a tileset of a Tiled map, either the Source of an external
+// tileset or a tileset embedded in the map with a single image
+type TiledTileset struct {
+	FirstGID                int
+	Source                  string
+	Name                    string
+	TileWidth, TileHeight   int
+	TileCount, Columns      int
+	Image                   string
+	ImageWidth, ImageHeight int
+}
+
+// TiledLayer is a tile layer of a Tiled map. Tiles holds the global tile ID of
+// every hex which is not empty, including the flip flags of Tiled.
+type TiledLayer struct {
+	ID    int
+	Name  string
+	Tiles HexMap[uint32]
+}
+
+// NewTiledMap creates an empty map of width columns and height rows with odd
+// stagger. Pointy orientations stagger rows and flat orientations stagger columns.
+func NewTiledMap(orientation HexOrientation, width, height, tileWidth, tileHeight, hexSideLength int) *TiledMap {
+	axis := TiledStaggerY
+	if orientation.isFlat() {
+		axis = TiledStaggerX
+	}
+	return &TiledMap{
+		Width:         width,
+		Height:        height,
+		TileWidth:     tileWidth,
+		TileHeight:    tileHeight,
+		HexSideLength: hexSideLength,
+		StaggerAxis:   axis,
+		StaggerIndex:  TiledStaggerOdd,
+	}
+}
+
+// Orientation returns the orientation of the hexes
+func (m *TiledMap) Orientation() HexOrientation {
+	if m.StaggerAxis == TiledStaggerX {
+		return LayoutFlat
+	}
+	return LayoutPointy
+}
+
+// Layout returns the layout placing every hex at the center of its tile in the
+// pixel coordinates of Tiled. The corners of the layout match the tiles for a
+// hexside length of half the tile height of pointy or half the tile width of flat hexes.
+func (m *TiledMap) Layout() HexLayout {
+	w, h, side := float64(m.TileWidth), float64(m.TileHeight), float64(m.HexSideLength)
+	even := m.StaggerIndex == TiledStaggerEven
+
+	if m.StaggerAxis == TiledStaggerX {
+		origin := NewVector2D(w/2, h/2)
+		if even {
+			origin.Y += h / 2
+		}
+		return NewHexLayout(LayoutFlat, NewVector2D((w+side)/3, h/math.Sqrt(3)), origin, 1)
+	}
+
+	origin := NewVector2D(w/2, h/2)
+	if even {
+		origin.X += w / 2
+	}
+	return NewHexLayout(LayoutPointy, NewVector2D(w/math.Sqrt(3), (h+side)/3), origin, 1)
+}
+
+// OffsetToHex converts the column and row of a tile to a hex
+func (m *TiledMap) OffsetToHex(col, row int64) Hex[int64] {
+	shift := int64(0)
+	if m.StaggerIndex == TiledStaggerEven {
+		shift = 1
+	}
+	if m.StaggerAxis == TiledStaggerX {
+		return Hex[int64]{Q: col, R: row - floorDiv(col+shift, 2)}
+	}
+	return Hex[int64]{Q: col - floorDiv(row+shift, 2), R: row}
+}
+
+// HexToOffset converts the hex to the column and row of its tile
+func (m *TiledMap) HexToOffset(h Hex[int64]) (int64, int64) {
+	shift := int64(0)
+	if m.StaggerIndex == TiledStaggerEven {
+		shift = 1
+	}
+	if m.StaggerAxis == TiledStaggerX {
+		return h.Q, h.R + floorDiv(h.Q+shift, 2)
+	}
+	return h.Q + floorDiv(h.R+shift, 2), h.R
+}
+
+// Contains reports whether the hex lies on the map
+func (m *TiledMap) Contains(h Hex[int64]) bool {
+	col, row := m.HexToOffset(h)
+	return col >= 0 && col < int64(m.Width) && row >= 0 && row < int64(m.Height)
+}
+
+// Hexes returns all hexes of the map row by row
+func (m *TiledMap) Hexes() []Hex[int64] {
+	hexes := make([]Hex[int64], 0, m.Width*m.Height)
+	for row := range int64(m.Height) {
+		for col := range int64(m.Width) {
+			hexes = append(hexes, m.OffsetToHex(col, row))
+		}
+	}
+	return hexes
+}
+
+// ReadTiledTMX reads a hexagonal map in the XML format of Tiled
+func ReadTiledTMX(r io.Reader) (*TiledMap, error) {
+	var raw tmxMap
+	if err := xml.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, errors.Join(ErrInvalidTiledMap, err)
+	}
+
+	m := &TiledMap{
+		Width:         raw.Width,
+		Height:        raw.Height,
+		TileWidth:     raw.TileWidth,
+		TileHeight:    raw.TileHeight,
+		HexSideLength: raw.HexSideLength,
+		StaggerAxis:   raw.StaggerAxis,
+		StaggerIndex:  raw.StaggerIndex,
+	}
+	if err := m.validate(raw.Orientation, raw.Infinite != 0); err != nil {
+		return nil, err
+	}
+
+	for _, tileset := range raw.Tilesets {
+		m.Tilesets = append(m.Tilesets, TiledTileset{
+			FirstGID:   tileset.FirstGID,
+			Source:     tileset.Source,
+			Name:       tileset.Name,
+			TileWidth:  tileset.TileWidth,
+			TileHeight: tileset.TileHeight,
+			TileCount:  tileset.TileCount,
+			Columns:    tileset.Columns,
+		})
+		if image := tileset.Image; image != nil {
+			last := &m.Tilesets[len(m.Tilesets)-1]
+			last.Image, last.ImageWidth, last.ImageHeight = image.Source, image.Width, image.Height
+		}
+	}
+
+	for _, layer := range raw.Layers {
+		var gids []uint32
+		var err error
+		switch layer.Data.Encoding {
+		case "csv":
+			gids, err = parseTiledCSV(layer.Data.Text)
+		case "base64":
+			gids, err = decodeTiledBase64(layer.Data.Text, layer.Data.Compression)
+		case "":
+			for _, tile := range layer.Data.Tiles {
+				gids = append(gids, tile.GID)
+			}
+		default:
+			err = fmt.Errorf("%w: unsupported encoding %q", ErrInvalidTiledMap, layer.Data.Encoding)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := m.addLayer(layer.ID, layer.Name, gids); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// ReadTiledTMJ reads a hexagonal map in the JSON format of Tiled
+func ReadTiledTMJ(r io.Reader) (*TiledMap, error) {
+	var raw tmjMap
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, errors.Join(ErrInvalidTiledMap, err)
+	}
+
+	m := &TiledMap{
+		Width:         raw.Width,
+		Height:        raw.Height,
+		TileWidth:     raw.TileWidth,
+		TileHeight:    raw.TileHeight,
+		HexSideLength: raw.HexSideLength,
+		StaggerAxis:   raw.StaggerAxis,
+		StaggerIndex:  raw.StaggerIndex,
+	}
+	if err := m.validate(raw.Orientation, raw.Infinite); err != nil {
+		return nil, err
+	}
+
+	for _, tileset := range raw.Tilesets {
+		m.Tilesets = append(m.Tilesets, TiledTileset(tileset))
+	}
+
+	for _, layer := range raw.Layers {
+		if layer.Type != "tilelayer" {
+			continue
+		}
+
+		var gids []uint32
+		var err error
+		switch layer.Encoding {
+		case "", "csv":
+			if err = json.Unmarshal(layer.Data, &gids); err != nil {
+				err = errors.Join(ErrInvalidTiledMap, err)
+			}
+		case "base64":
+			var text string
+			if err = json.Unmarshal(layer.Data, &text); err != nil {
+				err = errors.Join(ErrInvalidTiledMap, err)
+			} else {
+				gids, err = decodeTiledBase64(text, layer.Compression)
+			}
+		default:
+			err = fmt.Errorf("%w: unsupported encoding %q", ErrInvalidTiledMap, layer.Encoding)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := m.addLayer(layer.ID, layer.Name, gids); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// WriteTMX writes the map in the XML format of Tiled with CSV encoded layers
+func (m *TiledMap) WriteTMX(w io.Writer) error {
+	raw := tmxMap{
+		Version:       "1.10",
+		Orientation:   "hexagonal",
+		RenderOrder:   "right-down",
+		Width:         m.Width,
+		Height:        m.Height,
+		TileWidth:     m.TileWidth,
+		TileHeight:    m.TileHeight,
+		HexSideLength: m.HexSideLength,
+		StaggerAxis:   m.StaggerAxis,
+		StaggerIndex:  m.StaggerIndex,
+		NextLayerID:   m.nextLayerID(),
+		NextObjectID:  1,
+	}
+	for _, tileset := range m.Tilesets {
+		embedded := tmxTileset{
+			FirstGID:   tileset.FirstGID,
+			Source:     tileset.Source,
+			Name:       tileset.Name,
+			TileWidth:  tileset.TileWidth,
+			TileHeight: tileset.TileHeight,
+			TileCount:  tileset.TileCount,
+			Columns:    tileset.Columns,
+		}
+		if tileset.Image != "" {
+			embedded.Image = &tmxImage{Source: tileset.Image, Width: tileset.ImageWidth, Height: tileset.ImageHeight}
+		}
+		raw.Tilesets = append(raw.Tilesets, embedded)
+	}
+	for i, layer := range m.Layers {
+		gids, err := m.layerGIDs(layer)
+		if err != nil {
+			return err
+		}
+		raw.Layers = append(raw.Layers, tmxLayer{
+			ID:     m.layerID(i),
+			Name:   layer.Name,
+			Width:  m.Width,
+			Height: m.Height,
+			Data:   tmxData{Encoding: "csv", CSV: m.formatCSV(gids)},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", " ")
+	if err := encoder.Encode(raw); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// WriteTMJ writes the map in the JSON format of Tiled
+func (m *TiledMap) WriteTMJ(w io.Writer) error {
+	raw := tmjMap{
+		Type:          "map",
+		Version:       "1.10",
+		Orientation:   "hexagonal",
+		RenderOrder:   "right-down",
+		Width:         m.Width,
+		Height:        m.Height,
+		TileWidth:     m.TileWidth,
+		TileHeight:    m.TileHeight,
+		HexSideLength: m.HexSideLength,
+		StaggerAxis:   m.StaggerAxis,
+		StaggerIndex:  m.StaggerIndex,
+		NextLayerID:   m.nextLayerID(),
+		NextObjectID:  1,
+		Tilesets:      []tmjTileset{},
+		Layers:        []tmjLayer{},
+	}
+	for _, tileset := range m.Tilesets {
+		raw.Tilesets = append(raw.Tilesets, tmjTileset(tileset))
+	}
+	for i, layer := range m.Layers {
+		gids, err := m.layerGIDs(layer)
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(gids)
+		if err != nil {
+			return err
+		}
+		raw.Layers = append(raw.Layers, tmjLayer{
+			ID:      m.layerID(i),
+			Name:    layer.Name,
+			Type:    "tilelayer",
+			Width:   m.Width,
+			Height:  m.Height,
+			Opacity: 1,
+			Visible: true,
+			Data:    data,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", " ")
+	return encoder.Encode(raw)
+}
+
+// validate checks that the map is a finite hexagonal map
+func (m *TiledMap) validate(orientation string, infinite bool) error {
+	switch {
+	case orientation != "hexagonal":
+		return fmt.Errorf("%w: orientation %q is not hexagonal", ErrInvalidTiledMap, orientation)
+	case infinite:
+		return fmt.Errorf("%w: infinite maps are not supported", ErrInvalidTiledMap)
+	case m.StaggerAxis != TiledStaggerX && m.StaggerAxis != TiledStaggerY:
+		return fmt.Errorf("%w: stagger axis %q", ErrInvalidTiledMap, m.StaggerAxis)
+	case m.StaggerIndex != TiledStaggerOdd && m.StaggerIndex != TiledStaggerEven:
+		return fmt.Errorf("%w: stagger index %q", ErrInvalidTiledMap, m.StaggerIndex)
+	case m.Width <= 0 || m.Height <= 0 || m.TileWidth <= 0 || m.TileHeight <= 0 || m.HexSideLength < 0:
+		return fmt.Errorf("%w: invalid size", ErrInvalidTiledMap)
+	}
+	return nil
+}
+
+// addLayer adds a layer with the global tile IDs of all tiles row by row
+func (m *TiledMap) addLayer(id int, name string, gids []uint32) error {
+	if len(gids) != m.Width*m.Height {
+		return fmt.Errorf("%w: layer %q has %d tiles instead of %d", ErrInvalidTiledMap, name, len(gids), m.Width*m.Height)
+	}
+
+	tiles := make(HexMap[uint32])
+	for i, h := range m.Hexes() {
+		if gids[i] != 0 {
+			tiles[h] = gids[i]
+		}
+	}
+	m.Layers = append(m.Layers, TiledLayer{ID: id, Name: name, Tiles: tiles})
+	return nil
+}
+
+// layerGIDs returns the global tile IDs of the layer row by row
+func (m *TiledMap) layerGIDs(layer TiledLayer) ([]uint32, error) {
+	gids := make([]uint32, m.Width*m.Height)
+	for h, gid := range layer.Tiles {
+		if !m.Contains(h) {
+			return nil, fmt.Errorf("%w: hex %v of layer %q is outside the map", ErrInvalidTiledMap, h, layer.Name)
+		}
+		col, row := m.HexToOffset(h)
+		gids[row*int64(m.Width)+col] = gid
+	}
+	return gids, nil
+}
+
+// layerID returns the ID of the layer at the index, numbering layers without ID
+func (m *TiledMap) layerID(index int) int {
+	if m.Layers[index].ID > 0 {
+		return m.Layers[index].ID
+	}
+	return index + 1
+}
+
+// nextLayerID returns the next free layer ID
+func (m *TiledMap) nextLayerID() int {
+	next := 1
+	for i := range m.Layers {
+		next = max(next, m.layerID(i)+1)
+	}
+	return next
+}
+
+// formatCSV formats the global tile IDs with a line per row like Tiled
+func (m *TiledMap) formatCSV(gids []uint32) string {
+	var b strings.Builder
+	b.WriteString("\n")
+	for i, gid := range gids {
+		b.WriteString(strconv.FormatUint(uint64(gid), 10))
+		if i < len(gids)-1 {
+			b.WriteString(",")
+		}
+		if (i+1)%m.Width == 0 {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// parseTiledCSV parses comma separated global tile IDs
+func parseTiledCSV(text string) ([]uint32, error) {
+	var gids []uint32
+	for field := range strings.SplitSeq(text, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		gid, err := strconv.ParseUint(field, 10, 32)
+		if err != nil {
+			return nil, errors.Join(ErrInvalidTiledMap, err)
+		}
+		gids = append(gids, uint32(gid))
+	}
+	return gids, nil
+}
+
+// decodeTiledBase64 decodes base64 encoded little endian global tile IDs, optionally compressed
+func decodeTiledBase64(text, compression string) ([]uint32, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(text))
+	if err != nil {
+		return nil, errors.Join(ErrInvalidTiledMap, err)
+	}
+
+	var reader io.ReadCloser
+	switch compression {
+	case "":
+	case "zlib":
+		reader, err = zlib.NewReader(bytes.NewReader(data))
+	case "gzip":
+		reader, err = gzip.NewReader(bytes.NewReader(data))
+	default:
+		return nil, fmt.Errorf("%w: unsupported compression %q", ErrInvalidTiledMap, compression)
+	}
+	if reader != nil {
+		defer reader.Close()
+		data, err = io.ReadAll(reader)
+	}
+	if err != nil {
+		return nil, errors.Join(ErrInvalidTiledMap, err)
+	}
+
+	if len(data)%4 != 0 {
+		return nil, fmt.Errorf("%w: truncated tile data", ErrInvalidTiledMap)
+	}
+	gids := make([]uint32, len(data)/4)
+	for i := range gids {
+		gids[i] = binary.LittleEndian.Uint32(data[i*4:])
+	}
+	return gids, nil
+}
+
+// tmxMap is the XML representation of a Tiled map
+type tmxMap struct {
+	XMLName       xml.Name     `xml:"map"`
+	Version       string       `xml:"version,attr"`
+	Orientation   string       `xml:"orientation,attr"`
+	RenderOrder   string       `xml:"renderorder,attr"`
+	Width         int          `xml:"width,attr"`
+	Height        int          `xml:"height,attr"`
+	TileWidth     int          `xml:"tilewidth,attr"`
+	TileHeight    int          `xml:"tileheight,attr"`
+	Infinite      int          `xml:"infinite,attr"`
+	HexSideLength int          `xml:"hexsidelength,attr"`
+	StaggerAxis   string       `xml:"staggeraxis,attr"`
+	StaggerIndex  string       `xml:"staggerindex,attr"`
+	NextLayerID   int          `xml:"nextlayerid,attr"`
+	NextObjectID  int          `xml:"nextobjectid,attr"`
+	Tilesets      []tmxTileset `xml:"tileset"`
+	Layers        []tmxLayer   `xml:"layer"`
+}
+
+// tmxTileset is the XML representation of a tileset reference or an embedded tileset
+type tmxTileset struct {
+	FirstGID   int       `xml:"firstgid,attr"`
+	Source     string    `xml:"source,attr,omitempty"`
+	Name       string    `xml:"name,attr,omitempty"`
+	TileWidth  int       `xml:"tilewidth,attr,omitempty"`
+	TileHeight int       `xml:"tileheight,attr,omitempty"`
+	TileCount  int       `xml:"tilecount,attr,omitempty"`
+	Columns    int       `xml:"columns,attr,omitempty"`
+	Image      *tmxImage `xml:"image"`
+}
+
+// tmxImage is the XML representation of a tileset image
+type tmxImage struct {
+	Source string `xml:"source,attr"`
+	Width  int    `xml:"width,attr"`
+	Height int    `xml:"height,attr"`
+}
+
+// tmxLayer is the XML representation of a tile layer
+type tmxLayer struct {
+	ID     int     `xml:"id,attr"`
+	Name   string  `xml:"name,attr"`
+	Width  int     `xml:"width,attr"`
+	Height int     `xml:"height,attr"`
+	Data   tmxData `xml:"data"`
+}
+
+// tmxData is the XML representation of the tiles of a layer
+type tmxData struct {
+	Encoding    string    `xml:"encoding,attr,omitempty"`
+	Compression string    `xml:"compression,attr,omitempty"`
+	Text        string    `xml:",chardata"`
+	Tiles       []tmxTile `xml:"tile"`
+
+	// CSV is written verbatim, so the line breaks between rows are not escaped
+	CSV string `xml:",innerxml"`
+}
+
+// tmxTile is the XML representation of a single tile of an unencoded layer
+type tmxTile struct {
+	GID uint32 `xml:"gid,attr"`
+}
+
+// tmjMap is the JSON representation of a Tiled map
+type tmjMap struct {
+	Type          string       `json:"type"`
+	Version       string       `json:"version"`
+	Orientation   string       `json:"orientation"`
+	RenderOrder   string       `json:"renderorder"`
+	Width         int          `json:"width"`
+	Height        int          `json:"height"`
+	TileWidth     int          `json:"tilewidth"`
+	TileHeight    int          `json:"tileheight"`
+	Infinite      bool         `json:"infinite"`
+	HexSideLength int          `json:"hexsidelength"`
+	StaggerAxis   string       `json:"staggeraxis"`
+	StaggerIndex  string       `json:"staggerindex"`
+	NextLayerID   int          `json:"nextlayerid"`
+	NextObjectID  int          `json:"nextobjectid"`
+	Tilesets      []tmjTileset `json:"tilesets"`
+	Layers        []tmjLayer   `json:"layers"`
+}
+
+// tmjTileset is the JSON representation of a tileset reference or an embedded tileset
+type tmjTileset struct {
+	FirstGID    int    `json:"firstgid"`
+	Source      string `json:"source,omitempty"`
+	Name        string `json:"name,omitempty"`
+	TileWidth   int    `json:"tilewidth,omitempty"`
+	TileHeight  int    `json:"tileheight,omitempty"`
+	TileCount   int    `json:"tilecount,omitempty"`
+	Columns     int    `json:"columns,omitempty"`
+	Image       string `json:"image,omitempty"`
+	ImageWidth  int    `json:"imagewidth,omitempty"`
+	ImageHeight int    `json:"imageheight,omitempty"`
+}
+
+// tmjLayer is the JSON representation of a layer, only tile layers are read
+type tmjLayer struct {
+	ID          int             `json:"id"`
+	Name        string          `json:"name"`
+	Type        string          `json:"type"`
+	Width       int             `json:"width"`
+	Height      int             `json:"height"`
+	X           int             `json:"x"`
+	Y           int             `json:"y"`
+	Opacity     float64         `json:"opacity"`
+	Visible     bool            `json:"visible"`
+	Encoding    string          `json:"encoding,omitempty"`
+	Compression string          `json:"compression,omitempty"`
+	Data        json.RawMessage `json:"data"`
+}
@@ -0,0 +1,237 @@
+package maths
+
+import (
+	"bytes"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tiledCenter returns the center of the tile in the pixel coordinates of Tiled
+func tiledCenter(m *TiledMap, col, row int64) Vector2D[float64] {
+	w, h, side := float64(m.TileWidth), float64(m.TileHeight), float64(m.HexSideLength)
+	staggered := func(i int64) bool { return (i%2 == 1) == (m.StaggerIndex == TiledStaggerOdd) }
+
+	if m.StaggerAxis == TiledStaggerX {
+		y := float64(row)*h + h/2
+		if staggered(col) {
+			y += h / 2
+		}
+		return NewVector2D(float64(col)*(w+side)/2+w/2, y)
+	}
+	x := float64(col)*w + w/2
+	if staggered(row) {
+		x += w / 2
+	}
+	return NewVector2D(x, float64(row)*(h+side)/2+h/2)
+}
+
+func TestTiledMapLayout(t *testing.T) {
+	t.Parallel()
+
+	for _, axis := range []string{TiledStaggerX, TiledStaggerY} {
+		for _, index := range []string{TiledStaggerOdd, TiledStaggerEven} {
+			t.Run(axis+" "+index, func(t *testing.T) {
+				t.Parallel()
+
+				m := &TiledMap{Width: 6, Height: 5, TileWidth: 56, TileHeight: 64, HexSideLength: 32, StaggerAxis: axis, StaggerIndex: index}
+				if axis == TiledStaggerX {
+					m.TileWidth, m.TileHeight = 64, 56
+				}
+				layout := m.Layout()
+				assert.Equal(t, m.Orientation(), layout.Orientation)
+
+				hexes := m.Hexes()
+				assert.Len(t, hexes, 30)
+				for i, h := range hexes {
+					col, row := int64(i%m.Width), int64(i/m.Width)
+					assert.Equal(t, h, m.OffsetToHex(col, row))
+					gotCol, gotRow := m.HexToOffset(h)
+					assert.Equal(t, []int64{col, row}, []int64{gotCol, gotRow})
+					assert.True(t, m.Contains(h))
+
+					// Hexes sit at the center of their tile
+					center := layout.HexToVector2D(h.ToFloat())
+					expected := tiledCenter(m, col, row)
+					assert.InDelta(t, expected.X, center.X, 1e-9, h)
+					assert.InDelta(t, expected.Y, center.Y, 1e-9, h)
+					assert.Equal(t, h, hexRound(layout.Vector2DToHex(expected)))
+				}
+
+				for _, h := range []Hex[int64]{m.OffsetToHex(-1, 0), m.OffsetToHex(0, 5), m.OffsetToHex(6, 2)} {
+					assert.False(t, m.Contains(h))
+				}
+			})
+		}
+	}
+}
+
+func TestTiledMapRegularCorners(t *testing.T) {
+	t.Parallel()
+
+	// A hexside length of half the tile height makes the layout corners the tile corners
+	m := NewTiledMap(LayoutPointy, 3, 3, 52, 60, 30)
+	corners := m.Layout().HexCorners(Hex[float64]{})
+	xs, ys := []float64{}, []float64{}
+	for _, corner := range corners {
+		xs, ys = append(xs, corner.X), append(ys, corner.Y)
+	}
+	assert.InDelta(t, 0, slices.Min(ys), 1e-9)
+	assert.InDelta(t, 60, slices.Max(ys), 1e-9)
+	assert.InDelta(t, 52, slices.Max(xs)-slices.Min(xs), 0.1)
+}
+
+func TestNewTiledMapOrientation(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, TiledStaggerY, NewTiledMap(LayoutPointy, 3, 3, 52, 60, 30).StaggerAxis)
+	assert.Equal(t, TiledStaggerX, NewTiledMap(LayoutFlat, 3, 3, 60, 52, 30).StaggerAxis)
+
+	// Flat orientations with rounding errors stagger columns as well
+	computed := LayoutFlat
+	computed.F0, computed.F2 = 1.5000001, 0.8660254
+	m := NewTiledMap(computed, 3, 3, 60, 52, 30)
+	assert.Equal(t, TiledStaggerX, m.StaggerAxis)
+	assert.Equal(t, LayoutFlat, m.Orientation())
+}
+
+func TestReadTiledTMX(t *testing.T) {
+	t.Parallel()
+
+	file, err := os.Open("testdata/tiled/pointy_odd.tmx")
+	require.NoError(t, err)
+	defer file.Close()
+
+	m, err := ReadTiledTMX(file)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, m.Width)
+	assert.Equal(t, 3, m.Height)
+	assert.Equal(t, 32, m.HexSideLength)
+	assert.Equal(t, LayoutPointy, m.Orientation())
+	assert.Equal(t, []TiledTileset{
+		{FirstGID: 1, Source: "terrain.tsx"},
+		{FirstGID: 7, Name: "units", TileWidth: 56, TileHeight: 64, TileCount: 4, Columns: 2, Image: "units.png", ImageWidth: 112, ImageHeight: 128},
+	}, m.Tilesets)
+
+	require.Len(t, m.Layers, 3)
+	ground := HexMap[uint32]{
+		{Q: 0, R: 0}: 1, {Q: 1, R: 0}: 2, {Q: 3, R: 0}: 3,
+		{Q: 1, R: 1}: 4, {Q: 2, R: 1}: 5,
+		{Q: -1, R: 2}: 6, {Q: 2, R: 2}: 0x80000007,
+	}
+	assert.Equal(t, TiledLayer{ID: 1, Name: "Ground", Tiles: ground}, m.Layers[0])
+	assert.Equal(t, ground, m.Layers[1].Tiles)
+	assert.Equal(t, HexMap[uint32]{{Q: 1, R: 0}: 8, {Q: 2, R: 2}: 9}, m.Layers[2].Tiles)
+}
+
+func TestReadTiledTMJ(t *testing.T) {
+	t.Parallel()
+
+	file, err := os.Open("testdata/tiled/flat_even.tmj")
+	require.NoError(t, err)
+	defer file.Close()
+
+	m, err := ReadTiledTMJ(file)
+	require.NoError(t, err)
+
+	assert.Equal(t, LayoutFlat, m.Orientation())
+	assert.Equal(t, TiledStaggerEven, m.StaggerIndex)
+	assert.Equal(t, []TiledTileset{{FirstGID: 1, Source: "terrain.tsj"}}, m.Tilesets)
+
+	// The object group is skipped
+	require.Len(t, m.Layers, 2)
+	ground := HexMap[uint32]{
+		{Q: 0, R: 0}: 1, {Q: 1, R: -1}: 2, {Q: 3, R: -2}: 3,
+		{Q: 1, R: 0}: 4, {Q: 2, R: 0}: 5,
+		{Q: 0, R: 2}: 6, {Q: 3, R: 0}: 0x80000007,
+	}
+	assert.Equal(t, TiledLayer{ID: 1, Name: "Ground", Tiles: ground}, m.Layers[0])
+	assert.Equal(t, TiledLayer{ID: 3, Name: "Units", Tiles: ground}, m.Layers[1])
+}
+
+func TestTiledMapRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	m := NewTiledMap(LayoutFlat, 5, 4, 64, 56, 32)
+	m.Tilesets = []TiledTileset{{FirstGID: 1, Name: "terrain", TileWidth: 64, TileHeight: 56, TileCount: 8, Columns: 4, Image: "terrain.png", ImageWidth: 256, ImageHeight: 112}}
+	tiles := make(HexMap[uint32])
+	for i, h := range m.Hexes() {
+		if i%3 != 0 {
+			tiles[h] = uint32(i%8 + 1)
+		}
+	}
+	m.Layers = []TiledLayer{{Name: "Ground", Tiles: tiles}, {ID: 5, Name: "Empty", Tiles: HexMap[uint32]{}}}
+
+	expected := *m
+	expected.Layers = []TiledLayer{{ID: 1, Name: "Ground", Tiles: tiles}, {ID: 5, Name: "Empty", Tiles: HexMap[uint32]{}}}
+
+	t.Run("TMX", func(t *testing.T) {
+		t.Parallel()
+
+		var b bytes.Buffer
+		require.NoError(t, m.WriteTMX(&b))
+		assert.Contains(t, b.String(), `staggeraxis="x" staggerindex="odd" nextlayerid="6"`)
+		assert.Contains(t, b.String(), "<data encoding=\"csv\">\n0,2,3,0,5,\n")
+
+		read, err := ReadTiledTMX(&b)
+		require.NoError(t, err)
+		assert.Equal(t, &expected, read)
+	})
+
+	t.Run("TMJ", func(t *testing.T) {
+		t.Parallel()
+
+		var b bytes.Buffer
+		require.NoError(t, m.WriteTMJ(&b))
+		assert.Contains(t, b.String(), `"orientation": "hexagonal"`)
+
+		read, err := ReadTiledTMJ(&b)
+		require.NoError(t, err)
+		assert.Equal(t, &expected, read)
+	})
+
+	t.Run("Outside", func(t *testing.T) {
+		t.Parallel()
+
+		outside := *m
+		outside.Layers = []TiledLayer{{Name: "Ground", Tiles: HexMap[uint32]{{Q: 5, R: 0}: 1}}}
+		assert.ErrorIs(t, outside.WriteTMX(&bytes.Buffer{}), ErrInvalidTiledMap)
+		assert.ErrorIs(t, outside.WriteTMJ(&bytes.Buffer{}), ErrInvalidTiledMap)
+	})
+}
+
+func TestReadTiledInvalid(t *testing.T) {
+	t.Parallel()
+
+	tmx := map[string]string{
+		"Syntax":      `<map`,
+		"Orthogonal":  `<map orientation="orthogonal" width="1" height="1" tilewidth="8" tileheight="8" staggeraxis="y" staggerindex="odd"/>`,
+		"Infinite":    `<map orientation="hexagonal" infinite="1" width="1" height="1" tilewidth="8" tileheight="8" staggeraxis="y" staggerindex="odd"/>`,
+		"Axis":        `<map orientation="hexagonal" width="1" height="1" tilewidth="8" tileheight="8" staggeraxis="z" staggerindex="odd"/>`,
+		"Size":        `<map orientation="hexagonal" width="0" height="1" tilewidth="8" tileheight="8" staggeraxis="y" staggerindex="odd"/>`,
+		"Length":      `<map orientation="hexagonal" width="2" height="1" tilewidth="8" tileheight="8" staggeraxis="y" staggerindex="odd"><layer><data encoding="csv">1</data></layer></map>`,
+		"CSV":         `<map orientation="hexagonal" width="1" height="1" tilewidth="8" tileheight="8" staggeraxis="y" staggerindex="odd"><layer><data encoding="csv">x</data></layer></map>`,
+		"Base64":      `<map orientation="hexagonal" width="1" height="1" tilewidth="8" tileheight="8" staggeraxis="y" staggerindex="odd"><layer><data encoding="base64">AQA=</data></layer></map>`,
+		"Compression": `<map orientation="hexagonal" width="1" height="1" tilewidth="8" tileheight="8" staggeraxis="y" staggerindex="odd"><layer><data encoding="base64" compression="zstd">AQAAAA==</data></layer></map>`,
+	}
+	for name, data := range tmx {
+		_, err := ReadTiledTMX(strings.NewReader(data))
+		assert.ErrorIs(t, err, ErrInvalidTiledMap, name)
+	}
+
+	tmj := map[string]string{
+		"Syntax":   `{`,
+		"Index":    `{"orientation":"hexagonal","width":1,"height":1,"tilewidth":8,"tileheight":8,"staggeraxis":"x","staggerindex":"none"}`,
+		"Infinite": `{"orientation":"hexagonal","infinite":true,"width":1,"height":1,"tilewidth":8,"tileheight":8,"staggeraxis":"x","staggerindex":"odd"}`,
+		"Data":     `{"orientation":"hexagonal","width":1,"height":1,"tilewidth":8,"tileheight":8,"staggeraxis":"x","staggerindex":"odd","layers":[{"type":"tilelayer","data":"1"}]}`,
+	}
+	for name, data := range tmj {
+		_, err := ReadTiledTMJ(strings.NewReader(data))
+		assert.ErrorIs(t, err, ErrInvalidTiledMap, name)
+	}
+}
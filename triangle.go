@@ -0,0 +1,140 @@
+package maths
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Triangle is a cell of a triangle grid in three coordinates. Triangles pointing up
+// have a coordinate sum of 2, triangles pointing down a sum of 1. Moving over an edge
+// changes a single coordinate by one.
+type Triangle struct {
+	A, B, C int64
+}
+
+// NewTriangle creates a new Triangle with the given coordinates
+func NewTriangle(a, b, c int64) Triangle {
+	return Triangle{A: a, B: b, C: c}
+}
+
+// String returns the coordinates as `a:b:c`
+func (t Triangle) String() string {
+	return fmt.Sprintf("%v:%v:%v", t.A, t.B, t.C)
+}
+
+// Valid reports whether the coordinates describe a triangle of the grid
+func (t Triangle) Valid() bool {
+	sum := t.A + t.B + t.C
+	return sum == 1 || sum == 2
+}
+
+// PointsUp reports whether the triangle points up with its base at the bottom
+func (t Triangle) PointsUp() bool {
+	return t.A+t.B+t.C == 2
+}
+
+// Distance returns the number of edges to cross to get from one triangle to the other
+func (t Triangle) Distance(other Triangle) float64 {
+	return math.Abs(float64(t.A-other.A)) + math.Abs(float64(t.B-other.B)) + math.Abs(float64(t.C-other.C))
+}
+
+// Neighbours returns the three triangles sharing an edge, across the A, B and C edge
+func (t Triangle) Neighbours() []Triangle {
+	s := t.step()
+	return []Triangle{
+		{A: t.A + s, B: t.B, C: t.C},
+		{A: t.A, B: t.B + s, C: t.C},
+		{A: t.A, B: t.B, C: t.C + s},
+	}
+}
+
+// VertexNeighbours returns the nine triangles sharing only a corner. The first six
+// point in the same direction, the last three are opposite across a corner.
+func (t Triangle) VertexNeighbours() []Triangle {
+	s := t.step()
+	return []Triangle{
+		{A: t.A + s, B: t.B - s, C: t.C},
+		{A: t.A + s, B: t.B, C: t.C - s},
+		{A: t.A, B: t.B + s, C: t.C - s},
+		{A: t.A - s, B: t.B + s, C: t.C},
+		{A: t.A - s, B: t.B, C: t.C + s},
+		{A: t.A, B: t.B - s, C: t.C + s},
+		{A: t.A - s, B: t.B + s, C: t.C + s},
+		{A: t.A + s, B: t.B - s, C: t.C + s},
+		{A: t.A + s, B: t.B + s, C: t.C - s},
+	}
+}
+
+// LineTo returns the triangles crossed by the line between the centers of the
+// triangles, each sharing an edge with the previous one
+func (t Triangle) LineTo(other Triangle) []Triangle {
+	// Nudge the line so it never passes exactly through a corner
+	nudge := Vector2D[float64]{X: 1e-6, Y: 2e-6}
+	from := unitTriangleLayout.TriangleToVector2D(t).Add(nudge)
+	to := unitTriangleLayout.TriangleToVector2D(other).Add(nudge)
+
+	// Parameters where the line crosses the grid lines of every axis
+	crossings := []float64{0, 1}
+	axes0, axes1 := unitTriangleLayout.axes(from), unitTriangleLayout.axes(to)
+	for axis := range 3 {
+		f0, f1 := axes0[axis], axes1[axis]
+		for k := math.Ceil(math.Min(f0, f1)); k <= math.Max(f0, f1); k++ {
+			crossings = append(crossings, (k-f0)/(f1-f0))
+		}
+	}
+	slices.Sort(crossings)
+
+	results := []Triangle{t}
+	for i := 1; i < len(crossings); i++ {
+		mid := (crossings[i-1] + crossings[i]) / 2
+		triangle := unitTriangleLayout.Vector2DToTriangle(from.Add(to.Subtract(from).Multiply(mid)))
+		if triangle != results[len(results)-1] {
+			results = append(results, triangle)
+		}
+	}
+	return results
+}
+
+// Ring returns all triangles at the given distance ordered counter-clockwise on a Y-down
+// screen, starting from the right
+func (t Triangle) Ring(radius int) []Triangle {
+	if radius < 0 {
+		return nil
+	}
+	if radius == 0 {
+		return []Triangle{t}
+	}
+
+	r := int64(radius)
+	var results []Triangle
+	for a := t.A - r; a <= t.A+r; a++ {
+		for b := t.B - r; b <= t.B+r; b++ {
+			for _, sum := range []int64{1, 2} {
+				candidate := Triangle{A: a, B: b, C: sum - a - b}
+				if candidate.Distance(t) == float64(radius) {
+					results = append(results, candidate)
+				}
+			}
+		}
+	}
+
+	center := unitTriangleLayout.TriangleToVector2D(t)
+	angle := func(other Triangle) float64 {
+		d := unitTriangleLayout.TriangleToVector2D(other).Subtract(center)
+		return math.Mod(math.Atan2(-d.Y, d.X)+2*math.Pi, 2*math.Pi)
+	}
+	slices.SortFunc(results, func(a, b Triangle) int {
+		return cmp.Or(cmp.Compare(angle(a), angle(b)), cmp.Compare(a.A, b.A), cmp.Compare(a.B, b.B))
+	})
+	return results
+}
+
+// step is the coordinate change when moving over an edge
+func (t Triangle) step() int64 {
+	if t.PointsUp() {
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,159 @@
+package maths
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// unitTriangleLayout is a layout of equilateral triangles with edges of length one
+var unitTriangleLayout = NewTriangleLayout(NewVector2D(1, math.Sqrt(3)/2), NewVector2D[float64](0, 0))
+
+// TriangleLayout represents the size and position of the triangles of a grid. Size
+// is the width and height of a single triangle, equilateral triangles with an edge
+// length of e have the size (e, e * sqrt(3) / 2). Origin is a corner of triangle (1, 1, 0).
+type TriangleLayout struct {
+	Size   Vector2D[float64]
+	Origin Vector2D[float64]
+}
+
+// NewTriangleLayout creates a new layout with specified parameters
+func NewTriangleLayout(size Vector2D[float64], origin Vector2D[float64]) TriangleLayout {
+	return TriangleLayout{Size: size, Origin: origin}
+}
+
+// TriangleToVector2D returns the world position of the center of the triangle
+func (layout TriangleLayout) TriangleToVector2D(t Triangle) Vector2D[float64] {
+	return Vector2D[float64]{
+		X: layout.Origin.X + float64(t.A-t.C)*layout.Size.X/2,
+		Y: layout.Origin.Y + float64(t.A-2*t.B+t.C)*layout.Size.Y/3,
+	}
+}
+
+// Vector2DToTriangle returns the triangle containing the world position
+func (layout TriangleLayout) Vector2DToTriangle(p Vector2D[float64]) Triangle {
+	axes := layout.axes(p)
+	return Triangle{
+		A: int64(math.Ceil(axes[0])),
+		B: int64(math.Floor(axes[1])) + 1,
+		C: int64(math.Ceil(axes[2])),
+	}
+}
+
+// TriangleCorners returns the corners of the triangle in world coordinates, clockwise
+// on a Y-down screen starting with the tip
+func (layout TriangleLayout) TriangleCorners(t Triangle) []Vector2D[float64] {
+	center := layout.TriangleToVector2D(t)
+	halfWidth, height := layout.Size.X/2, layout.Size.Y
+
+	if t.PointsUp() {
+		return []Vector2D[float64]{
+			{X: center.X, Y: center.Y - 2*height/3},
+			{X: center.X + halfWidth, Y: center.Y + height/3},
+			{X: center.X - halfWidth, Y: center.Y + height/3},
+		}
+	}
+	return []Vector2D[float64]{
+		{X: center.X, Y: center.Y + 2*height/3},
+		{X: center.X - halfWidth, Y: center.Y - height/3},
+		{X: center.X + halfWidth, Y: center.Y - height/3},
+	}
+}
+
+// axes returns the position along the three axes of the grid, grid lines are at integer values
+func (layout TriangleLayout) axes(p Vector2D[float64]) [3]float64 {
+	x := (p.X - layout.Origin.X) / layout.Size.X
+	y := -(p.Y - layout.Origin.Y) / layout.Size.Y
+	return [3]float64{x - y/2, y, -x - y/2}
+}
+
+// TriangleGrid represents a triangle grid system with camera integration
+type TriangleGrid struct {
+	Layout TriangleLayout
+}
+
+// NewTriangleGrid creates a new triangle grid with the triangle size and the origin at zero
+func NewTriangleGrid(triangleSize Vector2D[float64]) *TriangleGrid {
+	return &TriangleGrid{Layout: NewTriangleLayout(triangleSize, NewVector2D[float64](0, 0))}
+}
+
+// WorldToScreen converts world coordinates to screen coordinates using camera data
+func (grid *TriangleGrid) WorldToScreen(worldPos Vector2D[float64], camera Camera) Vector2D[float64] {
+	return worldToScreen(worldPos, camera)
+}
+
+// ScreenToWorld converts screen coordinates to world coordinates using camera data
+func (grid *TriangleGrid) ScreenToWorld(screenPos Vector2D[float64], camera Camera) Vector2D[float64] {
+	return screenToWorld(screenPos, camera)
+}
+
+// TriangleToScreen returns the screen position of the triangle center
+func (grid *TriangleGrid) TriangleToScreen(t Triangle, camera Camera) Vector2D[float64] {
+	return worldToScreen(grid.Layout.TriangleToVector2D(t), camera)
+}
+
+// ScreenToTriangle returns the triangle under the screen position
+func (grid *TriangleGrid) ScreenToTriangle(screenPos Vector2D[float64], camera Camera) Triangle {
+	return grid.Layout.Vector2DToTriangle(screenToWorld(screenPos, camera))
+}
+
+// GetVisibleTriangles returns all triangles overlapping the camera view row by row
+func (grid *TriangleGrid) GetVisibleTriangles(camera Camera) []Triangle {
+	view := cameraView(camera)
+
+	// Range of the axes covering the view
+	low := [3]float64{math.Inf(1), math.Inf(1), math.Inf(1)}
+	high := [3]float64{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+	for _, corner := range []Vector2D[float64]{view.Min, view.Max, {X: view.Min.X, Y: view.Max.Y}, {X: view.Max.X, Y: view.Min.Y}} {
+		axes := grid.Layout.axes(corner)
+		for i, value := range axes {
+			low[i], high[i] = math.Min(low[i], value), math.Max(high[i], value)
+		}
+	}
+
+	var triangles []Triangle
+	for a := int64(math.Floor(low[0])); a <= int64(math.Ceil(high[0])); a++ {
+		for b := int64(math.Floor(low[1])); b <= int64(math.Ceil(high[1]))+1; b++ {
+			for _, sum := range []int64{1, 2} {
+				t := Triangle{A: a, B: b, C: sum - a - b}
+				if grid.triangleBounds(t).Intersects(view) {
+					triangles = append(triangles, t)
+				}
+			}
+		}
+	}
+
+	slices.SortFunc(triangles, func(a, b Triangle) int {
+		pa, pb := grid.Layout.TriangleToVector2D(a), grid.Layout.TriangleToVector2D(b)
+		return cmp.Or(cmp.Compare(b.B, a.B), cmp.Compare(pa.X, pb.X))
+	})
+	return triangles
+}
+
+// TriangleCornersScreen returns the corners of the triangle in screen coordinates
+func (grid *TriangleGrid) TriangleCornersScreen(t Triangle, camera Camera) []Vector2D[float64] {
+	corners := grid.Layout.TriangleCorners(t)
+	for i, corner := range corners {
+		corners[i] = worldToScreen(corner, camera)
+	}
+	return corners
+}
+
+// TriangleImageToScreen calculates the screen position and scale factor for an image of
+// a triangle. The position is the center of the bounding box of the triangle and the
+// image keeps its aspect ratio and fits into the bounding box.
+func (grid *TriangleGrid) TriangleImageToScreen(
+	t Triangle,
+	imageDefaultSize Vector2D[float64],
+	camera Camera,
+) (centerPosition Vector2D[float64], imageScaleFactor Vector2D[float64]) {
+	centerPosition = worldToScreen(grid.triangleBounds(t).Center(), camera)
+	triangleSize := Vector2D[float64]{X: grid.Layout.Size.X * camera.GetZoom(), Y: grid.Layout.Size.Y * camera.GetZoom()}
+	return centerPosition, imageScale(triangleSize, imageDefaultSize)
+}
+
+// triangleBounds returns the bounding box of the triangle in world coordinates
+func (grid *TriangleGrid) triangleBounds(t Triangle) Rect {
+	corners := grid.Layout.TriangleCorners(t)
+	return NewRect(corners[0], corners[1]).Union(NewRect(corners[2], corners[2]))
+}
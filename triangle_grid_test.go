@@ -0,0 +1,81 @@
+package maths
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTriangleLayout(t *testing.T) {
+	t.Parallel()
+
+	layout := NewTriangleLayout(NewVector2D(32, 16*math.Sqrt(3)), NewVector2D[float64](5, 7))
+
+	// Origin is the bottom left corner of triangle (1, 1, 0)
+	corners := layout.TriangleCorners(NewTriangle(1, 1, 0))
+	assert.InDelta(t, 5.0, corners[2].X, 1e-9)
+	assert.InDelta(t, 7.0, corners[2].Y, 1e-9)
+	assert.Less(t, corners[0].Y, corners[1].Y)
+
+	for a := int64(-4); a <= 4; a++ {
+		for b := int64(-4); b <= 4; b++ {
+			for _, sum := range []int64{1, 2} {
+				triangle := NewTriangle(a, b, sum-a-b)
+				center := layout.TriangleToVector2D(triangle)
+				assert.Equal(t, triangle, layout.Vector2DToTriangle(center))
+
+				// Points between the center and the corners belong to the triangle
+				for _, corner := range layout.TriangleCorners(triangle) {
+					assert.Equal(t, triangle, layout.Vector2DToTriangle(center.Add(corner.Subtract(center).Multiply(0.9))))
+				}
+			}
+		}
+	}
+}
+
+func TestTriangleGridCamera(t *testing.T) {
+	t.Parallel()
+
+	grid := NewTriangleGrid(NewVector2D[float64](40, 30))
+	camera := testCamera{position: NewVector2D[float64](-20, 10), zoom: 2, size: NewVector2D[float64](400, 300)}
+
+	triangle := NewTriangle(2, 0, -1)
+	screen := grid.TriangleToScreen(triangle, camera)
+	assert.Equal(t, grid.WorldToScreen(grid.Layout.TriangleToVector2D(triangle), camera), screen)
+	assert.Equal(t, triangle, grid.ScreenToTriangle(screen, camera))
+
+	corners := grid.TriangleCornersScreen(triangle, camera)
+	assert.Len(t, corners, 3)
+	assert.InDelta(t, 80.0, corners[2].X-corners[1].X, 1e-9)
+
+	center, scale := grid.TriangleImageToScreen(triangle, NewVector2D[float64](160, 60), camera)
+	assert.InDelta(t, screen.X, center.X, 1e-9)
+	assert.InDelta(t, screen.Y+10, center.Y, 1e-9) // the bounding box center of a down triangle is below its centroid
+	assert.Equal(t, NewVector2D[float64](0.5, 0.5), scale)
+}
+
+func TestTriangleGridVisibleTriangles(t *testing.T) {
+	t.Parallel()
+
+	grid := NewTriangleGrid(NewVector2D[float64](40, 30))
+	camera := testCamera{position: NewVector2D[float64](13, -29), zoom: 1, size: NewVector2D[float64](200, 120)}
+	view := NewRect(grid.ScreenToWorld(NewVector2D[float64](0, 0), camera), grid.ScreenToWorld(camera.size, camera))
+
+	triangles := grid.GetVisibleTriangles(camera)
+	visible := make(map[Triangle]bool)
+	for i, triangle := range triangles {
+		visible[triangle] = true
+		assert.True(t, grid.triangleBounds(triangle).Intersects(view))
+		if i > 0 {
+			assert.GreaterOrEqual(t, triangles[i-1].B, triangle.B)
+		}
+	}
+
+	// Every triangle under a point of the view is included
+	for x := view.Min.X; x <= view.Max.X; x += 3 {
+		for y := view.Min.Y; y <= view.Max.Y; y += 3 {
+			assert.True(t, visible[grid.Layout.Vector2DToTriangle(NewVector2D(x, y))])
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package maths
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTriangleOrientation(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, NewTriangle(1, 1, 0).PointsUp())
+	assert.False(t, NewTriangle(0, 1, 0).PointsUp())
+	assert.True(t, NewTriangle(0, 1, 0).Valid())
+	assert.False(t, NewTriangle(0, 0, 0).Valid())
+	assert.Equal(t, "1:-2:3", NewTriangle(1, -2, 3).String())
+}
+
+func TestTriangleNeighbours(t *testing.T) {
+	t.Parallel()
+
+	for _, triangle := range []Triangle{NewTriangle(1, 1, 0), NewTriangle(2, -1, 0), NewTriangle(-3, 5, -1)} {
+		corners := unitTriangleLayout.TriangleCorners(triangle)
+
+		// Edge neighbours share two corners, vertex neighbours a single one
+		shared := func(other Triangle) int {
+			count := 0
+			for _, a := range unitTriangleLayout.TriangleCorners(other) {
+				for _, b := range corners {
+					if a.Distance(b) < 1e-9 {
+						count++
+					}
+				}
+			}
+			return count
+		}
+
+		neighbours := triangle.Neighbours()
+		assert.Len(t, neighbours, 3)
+		for _, n := range neighbours {
+			assert.True(t, n.Valid())
+			assert.NotEqual(t, triangle.PointsUp(), n.PointsUp())
+			assert.Equal(t, 1.0, triangle.Distance(n))
+			assert.Equal(t, 2, shared(n))
+		}
+
+		vertexNeighbours := triangle.VertexNeighbours()
+		assert.Len(t, vertexNeighbours, 9)
+		for i, n := range vertexNeighbours {
+			assert.True(t, n.Valid())
+			assert.Equal(t, i < 6, triangle.PointsUp() == n.PointsUp())
+			assert.Equal(t, 1, shared(n))
+		}
+	}
+}
+
+func TestTriangleDistance(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		a, b     Triangle
+		expected float64
+	}{
+		{name: "Same", a: NewTriangle(1, 1, 0), b: NewTriangle(1, 1, 0), expected: 0},
+		{name: "Neighbour", a: NewTriangle(1, 1, 0), b: NewTriangle(0, 1, 0), expected: 1},
+		{name: "Row", a: NewTriangle(1, 1, 0), b: NewTriangle(3, 1, -2), expected: 4},
+		{name: "Far", a: NewTriangle(1, 1, 0), b: NewTriangle(-2, 4, -1), expected: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, tt.a.Distance(tt.b))
+			assert.Equal(t, tt.expected, tt.b.Distance(tt.a))
+		})
+	}
+}
+
+func TestTriangleLineTo(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a, b Triangle
+	}{
+		{name: "Same", a: NewTriangle(1, 1, 0), b: NewTriangle(1, 1, 0)},
+		{name: "Row", a: NewTriangle(1, 1, 0), b: NewTriangle(4, 1, -3)},
+		{name: "Diagonal", a: NewTriangle(1, 1, 0), b: NewTriangle(-2, 5, -2)},
+		{name: "Down to up", a: NewTriangle(0, 1, 0), b: NewTriangle(3, -3, 2)},
+		{name: "Through corners", a: NewTriangle(1, 1, 0), b: NewTriangle(-1, 1, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			line := tt.a.LineTo(tt.b)
+			assert.Equal(t, tt.a, line[0])
+			assert.Equal(t, tt.b, line[len(line)-1])
+			assert.GreaterOrEqual(t, float64(len(line)-1), tt.a.Distance(tt.b))
+			for i := 1; i < len(line); i++ {
+				assert.Equal(t, 1.0, line[i].Distance(line[i-1]))
+			}
+		})
+	}
+
+	// Straight rows cross every triangle once
+	assert.Len(t, NewTriangle(1, 1, 0).LineTo(NewTriangle(4, 1, -3)), 7)
+}
+
+func TestTriangleRing(t *testing.T) {
+	t.Parallel()
+
+	center := NewTriangle(1, 1, 0)
+	assert.Equal(t, []Triangle{center}, center.Ring(0))
+	assert.Nil(t, center.Ring(-1))
+	assert.ElementsMatch(t, center.Neighbours(), center.Ring(1))
+
+	for radius := 1; radius <= 5; radius++ {
+		ring := center.Ring(radius)
+		assert.NotEmpty(t, ring)
+		seen := make(map[Triangle]bool)
+		for _, triangle := range ring {
+			assert.True(t, triangle.Valid())
+			assert.Equal(t, float64(radius), center.Distance(triangle))
+			assert.False(t, seen[triangle])
+			seen[triangle] = true
+		}
+	}
+}
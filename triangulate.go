@@ -0,0 +1,248 @@
+package maths
+
+import (
+	"cmp"
+	"errors"
+	"math"
+	"slices"
+)
+
+// ErrInvalidPolygon is returned when a polygon cannot be triangulated
+var ErrInvalidPolygon = errors.New("maths: invalid polygon")
+
+// EarClip triangulates a simple polygon with optional holes by ear clipping.
+// The result holds three indices per counter-clockwise triangle. Indices refer
+// to the outer vertices followed by the vertices of every hole in order, so
+// they can be used together with the concatenated vertex slice.
+func EarClip[T interface {
+	int64 | float64
+}](outer []Vector2D[T], holes ...[]Vector2D[T]) ([]int, error) {
+	if len(outer) < 3 {
+		return nil, ErrInvalidPolygon
+	}
+
+	points := slices.Clone(outer)
+	ring := orientedRing(outer, 0, WindingCounterClockwise)
+
+	holeRings := make([][]int, 0, len(holes))
+	for _, hole := range holes {
+		if len(hole) < 3 {
+			return nil, ErrInvalidPolygon
+		}
+		holeRings = append(holeRings, orientedRing(hole, len(points), WindingClockwise))
+		points = append(points, hole...)
+	}
+
+	// Merge the holes from right to left into the outer ring
+	maxX := func(hole []int) int {
+		return slices.MaxFunc(hole, func(a, b int) int {
+			return compareVector(points[a], points[b])
+		})
+	}
+	slices.SortStableFunc(holeRings, func(a, b []int) int {
+		return -compareVector(points[maxX(a)], points[maxX(b)])
+	})
+	for _, hole := range holeRings {
+		var err error
+		ring, err = bridgeHole(points, ring, hole, maxX(hole))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return clipEars(points, ring)
+}
+
+// orientedRing returns the indices of the polygon starting at offset in the requested winding
+func orientedRing[T interface {
+	int64 | float64
+}](polygon []Vector2D[T], offset int, winding Winding) []int {
+	ring := make([]int, len(polygon))
+	for i := range ring {
+		ring[i] = offset + i
+	}
+	if PolygonWinding(polygon) != winding {
+		slices.Reverse(ring)
+	}
+	return ring
+}
+
+// bridgeHole connects the hole to a visible vertex of the ring and returns the merged ring.
+// The hole vertex m with the largest X coordinate is connected using Eberly's method.
+func bridgeHole[T interface {
+	int64 | float64
+}](points []Vector2D[T], ring, hole []int, m int) ([]int, error) {
+	mp := points[m]
+	mx, my := float64(mp.X), float64(mp.Y)
+
+	// Find the closest edge hit by a ray from m to the right
+	edge := -1
+	hitX := 0.0
+	for k, a := range ring {
+		b := ring[(k+1)%len(ring)]
+		pa, pb := points[a].ToFloat(), points[b].ToFloat()
+		if pa.Y == pb.Y || math.Min(pa.Y, pb.Y) > my || math.Max(pa.Y, pb.Y) < my {
+			continue
+		}
+
+		x := pa.X + (my-pa.Y)*(pb.X-pa.X)/(pb.Y-pa.Y)
+		if x >= mx && (edge < 0 || x < hitX) {
+			edge, hitX = k, x
+		}
+	}
+	if edge < 0 {
+		return nil, ErrInvalidPolygon
+	}
+
+	// Candidate is the edge end point with the larger X coordinate
+	a, b := edge, (edge+1)%len(ring)
+	candidate := a
+	if points[ring[b]].X > points[ring[a]].X {
+		candidate = b
+	}
+	hit := Vector2D[float64]{X: hitX, Y: my}
+	target := points[ring[candidate]].ToFloat()
+
+	// Reflex vertices inside the triangle m, hit, candidate may block the view
+	if target != hit {
+		a, b, c := mp.ToFloat(), hit, target
+		if orientation(a, b, c) < 0 {
+			b, c = c, b
+		}
+
+		bestTangent, bestDistance := math.Inf(1), math.Inf(1)
+		for k, index := range ring {
+			p := points[index].ToFloat()
+			d := p.Subtract(a)
+			if p == target || d.X <= 0 || !isReflex(points, ring, k) || !pointInTriangle(p, a, b, c) {
+				continue
+			}
+
+			// Prefer the smallest angle to the ray, then the closest vertex
+			tangent := math.Abs(d.Y) / d.X
+			if tangent < bestTangent || tangent == bestTangent && d.Length() < bestDistance {
+				candidate, bestTangent, bestDistance = k, tangent, d.Length()
+			}
+		}
+	}
+
+	// A vertex may appear several times after earlier bridges, use the
+	// occurrence whose interior angle contains the bridge
+	for k, index := range ring {
+		if points[index] == points[ring[candidate]] && locallyInside(points, ring, k, mp) {
+			candidate = k
+			break
+		}
+	}
+
+	// Rotate the hole so it starts at m
+	start := slices.Index(hole, m)
+	rotated := append(slices.Clone(hole[start:]), hole[:start]...)
+
+	merged := make([]int, 0, len(ring)+len(hole)+2)
+	merged = append(merged, ring[:candidate+1]...)
+	merged = append(merged, rotated...)
+	merged = append(merged, m, ring[candidate])
+	merged = append(merged, ring[candidate+1:]...)
+	return merged, nil
+}
+
+// clipEars triangulates a counter-clockwise ring which may touch itself at bridges
+func clipEars[T interface {
+	int64 | float64
+}](points []Vector2D[T], ring []int) ([]int, error) {
+	triangles := make([]int, 0, 3*(len(ring)-2))
+	ring = slices.Clone(ring)
+
+	for len(ring) > 3 {
+		clipped := false
+		for k := range ring {
+			if isEar(points, ring, k) {
+				prev, next := ring[(k+len(ring)-1)%len(ring)], ring[(k+1)%len(ring)]
+				triangles = append(triangles, prev, ring[k], next)
+				ring = slices.Delete(ring, k, k+1)
+				clipped = true
+				break
+			}
+		}
+		if clipped {
+			continue
+		}
+
+		// Drop a collinear vertex to resolve degenerated rings
+		for k := range ring {
+			prev, next := ring[(k+len(ring)-1)%len(ring)], ring[(k+1)%len(ring)]
+			if orientation(points[prev], points[ring[k]], points[next]) == 0 {
+				ring = slices.Delete(ring, k, k+1)
+				clipped = true
+				break
+			}
+		}
+		if !clipped {
+			return nil, ErrInvalidPolygon
+		}
+	}
+
+	if orientation(points[ring[0]], points[ring[1]], points[ring[2]]) > 0 {
+		triangles = append(triangles, ring...)
+	}
+	return triangles, nil
+}
+
+// isEar reports whether the vertex at position k of the ring can be clipped
+func isEar[T interface {
+	int64 | float64
+}](points []Vector2D[T], ring []int, k int) bool {
+	a := points[ring[(k+len(ring)-1)%len(ring)]]
+	b := points[ring[k]]
+	c := points[ring[(k+1)%len(ring)]]
+	if orientation(a, b, c) <= 0 {
+		return false
+	}
+
+	for _, index := range ring {
+		p := points[index]
+		if p == a || p == b || p == c {
+			continue
+		}
+		if orientation(a, b, p) >= 0 && orientation(b, c, p) >= 0 && orientation(c, a, p) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// isReflex reports whether the vertex at position k of a counter-clockwise ring is concave
+func isReflex[T interface {
+	int64 | float64
+}](points []Vector2D[T], ring []int, k int) bool {
+	prev, next := ring[(k+len(ring)-1)%len(ring)], ring[(k+1)%len(ring)]
+	return orientation(points[prev], points[ring[k]], points[next]) < 0
+}
+
+// locallyInside reports whether the direction to p lies inside the interior angle
+// of the counter-clockwise ring at position k
+func locallyInside[T interface {
+	int64 | float64
+}](points []Vector2D[T], ring []int, k int, p Vector2D[T]) bool {
+	prev := points[ring[(k+len(ring)-1)%len(ring)]]
+	v := points[ring[k]]
+	next := points[ring[(k+1)%len(ring)]]
+
+	if orientation(prev, v, next) >= 0 {
+		return orientation(v, next, p) >= 0 && orientation(prev, v, p) >= 0
+	}
+	return orientation(v, next, p) >= 0 || orientation(prev, v, p) >= 0
+}
+
+// pointInTriangle reports whether p lies inside the counter-clockwise triangle, edges included
+func pointInTriangle(p, a, b, c Vector2D[float64]) bool {
+	return orientation(a, b, p) >= 0 && orientation(b, c, p) >= 0 && orientation(c, a, p) >= 0
+}
+
+// compareVector orders vectors by X and then by Y
+func compareVector[T interface {
+	int64 | float64
+}](a, b Vector2D[T]) int {
+	return cmp.Or(cmp.Compare(a.X, b.X), cmp.Compare(a.Y, b.Y))
+}
@@ -0,0 +1,112 @@
+package maths
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// trianglesArea sums the signed areas of the triangles
+func trianglesArea[T interface {
+	int64 | float64
+}](t *testing.T, points []Vector2D[T], indices []int) float64 {
+	t.Helper()
+
+	assert.Zero(t, len(indices)%3)
+	area := 0.0
+	for i := 0; i < len(indices); i += 3 {
+		triangle := []Vector2D[T]{points[indices[i]], points[indices[i+1]], points[indices[i+2]]}
+		assert.Equal(t, WindingCounterClockwise, PolygonWinding(triangle))
+		area += SignedArea(triangle)
+	}
+	return area
+}
+
+func TestEarClip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		outer     []Vector2D[int64]
+		holes     [][]Vector2D[int64]
+		triangles int
+	}{
+		{
+			name:      "triangle",
+			outer:     []Vector2D[int64]{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}},
+			triangles: 1,
+		},
+		{
+			name:      "clockwise square",
+			outer:     []Vector2D[int64]{{X: 0, Y: 0}, {X: 0, Y: 2}, {X: 2, Y: 2}, {X: 2, Y: 0}},
+			triangles: 2,
+		},
+		{
+			name: "concave comb",
+			outer: []Vector2D[int64]{
+				{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 5}, {X: 8, Y: 5}, {X: 8, Y: 1},
+				{X: 6, Y: 1}, {X: 6, Y: 5}, {X: 4, Y: 5}, {X: 4, Y: 1}, {X: 2, Y: 1}, {X: 2, Y: 5}, {X: 0, Y: 5},
+			},
+			triangles: 10,
+		},
+		{
+			name:  "square with hole",
+			outer: []Vector2D[int64]{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}},
+			holes: [][]Vector2D[int64]{
+				{{X: 3, Y: 3}, {X: 7, Y: 3}, {X: 7, Y: 7}, {X: 3, Y: 7}},
+			},
+			triangles: 8,
+		},
+		{
+			name:  "square with two holes",
+			outer: []Vector2D[int64]{{X: 0, Y: 0}, {X: 20, Y: 0}, {X: 20, Y: 10}, {X: 0, Y: 10}},
+			holes: [][]Vector2D[int64]{
+				{{X: 2, Y: 2}, {X: 6, Y: 2}, {X: 6, Y: 8}, {X: 2, Y: 8}},
+				{{X: 12, Y: 4}, {X: 14, Y: 2}, {X: 16, Y: 4}, {X: 14, Y: 6}},
+			},
+			triangles: 14,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			indices, err := EarClip(tt.outer, tt.holes...)
+			assert.NoError(t, err)
+			assert.Len(t, indices, 3*tt.triangles)
+
+			points := append([]Vector2D[int64]{}, tt.outer...)
+			expected := Area(tt.outer)
+			for _, hole := range tt.holes {
+				points = append(points, hole...)
+				expected -= Area(hole)
+			}
+			assert.InDelta(t, expected, trianglesArea(t, points, indices), 1e-9)
+		})
+	}
+}
+
+func TestEarClipHexOutline(t *testing.T) {
+	t.Parallel()
+
+	layout := NewHexLayout(LayoutFlat, NewVector2D[float64](10, 10), NewVector2D[float64](0, 0), 1)
+	corners := layout.HexCorners(NewHex[float64](0, 0))
+	indices, err := EarClip(corners)
+	assert.NoError(t, err)
+	assert.Len(t, indices, 12)
+	assert.InDelta(t, Area(corners), trianglesArea(t, corners, indices), 1e-9)
+}
+
+func TestEarClipInvalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := EarClip([]Vector2D[float64]{{X: 0, Y: 0}, {X: 1, Y: 1}})
+	assert.ErrorIs(t, err, ErrInvalidPolygon)
+
+	_, err = EarClip(
+		[]Vector2D[float64]{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}},
+		[]Vector2D[float64]{{X: 0, Y: 0}},
+	)
+	assert.ErrorIs(t, err, ErrInvalidPolygon)
+}
@@ -0,0 +1,341 @@
+package maths
+
+import (
+	"math"
+	"slices"
+)
+
+// Animation is anything which progresses over time
+type Animation interface {
+	// Update advances the animation by dt. Once the animation is finished it
+	// returns true and the part of dt which was not used.
+	Update(dt float64) (remaining float64, finished bool)
+	// Reset rewinds the animation to the start
+	Reset()
+}
+
+// Tween interpolates a value from From to To over Duration and passes every
+// new value to a setter. Loops is the number of extra repetitions, negative
+// values repeat forever. With Yoyo every second repetition runs backwards.
+type Tween[T any] struct {
+	From, To   T
+	Duration   float64
+	Delay      float64
+	Easing     EasingFunc
+	Loops      int
+	Yoyo       bool
+	OnComplete func()
+
+	lerp      func(a, b T, t float64) T
+	set       func(value T)
+	elapsed   float64
+	iteration int
+	finished  bool
+}
+
+// NewTween creates a new tween using lerp to interpolate between the values
+func NewTween[T any](from, to T, duration float64, lerp func(a, b T, t float64) T, set func(value T)) *Tween[T] {
+	return &Tween[T]{
+		From:     from,
+		To:       to,
+		Duration: duration,
+		Easing:   EaseLinear,
+		lerp:     lerp,
+		set:      set,
+	}
+}
+
+// NewFloatTween creates a new tween between two numbers
+func NewFloatTween(from, to, duration float64, set func(value float64)) *Tween[float64] {
+	return NewTween(from, to, duration, lerp, set)
+}
+
+// NewVectorTween creates a new tween between two positions
+func NewVectorTween(from, to Vector2D[float64], duration float64, set func(value Vector2D[float64])) *Tween[Vector2D[float64]] {
+	return NewTween(from, to, duration, lerpVector, set)
+}
+
+// NewHexTween creates a new tween between two fractional hexes, e.g. to slide a unit between hex centers
+func NewHexTween(from, to Hex[float64], duration float64, set func(value Hex[float64])) *Tween[Hex[float64]] {
+	return NewTween(from, to, duration, func(a, b Hex[float64], t float64) Hex[float64] {
+		return Hex[float64]{Q: lerp(a.Q, b.Q, t), R: lerp(a.R, b.R, t)}
+	}, set)
+}
+
+// NewZoomTween creates a new tween for a camera zoom. The zoom changes
+// exponentially so zooming in and out feel equally fast.
+func NewZoomTween(from, to, duration float64, set func(zoom float64)) *Tween[float64] {
+	return NewTween(from, to, duration, func(a, b, t float64) float64 {
+		if a <= 0 || b <= 0 {
+			return lerp(a, b, t)
+		}
+		return a * math.Pow(b/a, t)
+	}, set)
+}
+
+// Finished reports whether the tween has completed all repetitions
+func (tween *Tween[T]) Finished() bool {
+	return tween.finished
+}
+
+// Update advances the tween by dt and sets the new value
+func (tween *Tween[T]) Update(dt float64) (float64, bool) {
+	if tween.finished {
+		return dt, true
+	}
+
+	tween.elapsed += dt
+	t := tween.elapsed - tween.Delay
+	if t < 0 {
+		return 0, false
+	}
+
+	for tween.Duration > 0 && t >= tween.Duration && (tween.Loops < 0 || tween.iteration < tween.Loops) {
+		t -= tween.Duration
+		tween.elapsed -= tween.Duration
+		tween.iteration++
+	}
+
+	if tween.Duration <= 0 || t >= tween.Duration {
+		tween.apply(1)
+		tween.finished = true
+		if tween.OnComplete != nil {
+			tween.OnComplete()
+		}
+		return t - math.Max(tween.Duration, 0), true
+	}
+
+	tween.apply(t / tween.Duration)
+	return 0, false
+}
+
+// Reset rewinds the tween including its delay
+func (tween *Tween[T]) Reset() {
+	tween.elapsed = 0
+	tween.iteration = 0
+	tween.finished = false
+}
+
+// apply sets the value for the linear progress of the current repetition
+func (tween *Tween[T]) apply(progress float64) {
+	if tween.Yoyo && tween.iteration%2 == 1 {
+		progress = 1 - progress
+	}
+	if tween.Easing != nil {
+		progress = tween.Easing(progress)
+	}
+	tween.set(tween.lerp(tween.From, tween.To, progress))
+}
+
+// Wait is an animation doing nothing for a duration, e.g. to delay the next step of a sequence
+type Wait struct {
+	Duration float64
+	elapsed  float64
+}
+
+// NewWait creates a new wait of the given duration
+func NewWait(duration float64) *Wait {
+	return &Wait{Duration: duration}
+}
+
+// Update advances the wait by dt
+func (wait *Wait) Update(dt float64) (float64, bool) {
+	wait.elapsed += dt
+	if wait.elapsed < wait.Duration {
+		return 0, false
+	}
+	remaining := math.Min(dt, wait.elapsed-wait.Duration)
+	wait.elapsed = wait.Duration
+	return remaining, true
+}
+
+// Reset rewinds the wait
+func (wait *Wait) Reset() {
+	wait.elapsed = 0
+}
+
+// Sequence plays animations one after another. Time left over by a finished
+// animation is passed on to the next one. Loops is the number of extra
+// repetitions of the whole sequence, negative values repeat forever.
+type Sequence struct {
+	Loops      int
+	OnComplete func()
+
+	animations []Animation
+	current    int
+	iteration  int
+	finished   bool
+}
+
+// NewSequence creates a new sequence of the animations
+func NewSequence(animations ...Animation) *Sequence {
+	return &Sequence{animations: animations}
+}
+
+// Update advances the current animation by dt
+func (sequence *Sequence) Update(dt float64) (float64, bool) {
+	if sequence.finished {
+		return dt, true
+	}
+
+	passStart := math.Inf(1)
+	for {
+		if sequence.current >= len(sequence.animations) {
+			// Repeat unless a whole pass did not consume any time, which would never end
+			if (sequence.Loops < 0 || sequence.iteration < sequence.Loops) && dt < passStart {
+				sequence.iteration++
+				sequence.restart()
+				passStart = dt
+				continue
+			}
+
+			sequence.finished = true
+			if sequence.OnComplete != nil {
+				sequence.OnComplete()
+			}
+			return dt, true
+		}
+
+		var finished bool
+		dt, finished = sequence.animations[sequence.current].Update(dt)
+		if !finished {
+			return 0, false
+		}
+		sequence.current++
+	}
+}
+
+// Reset rewinds the sequence and all its animations
+func (sequence *Sequence) Reset() {
+	sequence.iteration = 0
+	sequence.finished = false
+	sequence.restart()
+}
+
+// restart rewinds all animations for the next repetition
+func (sequence *Sequence) restart() {
+	sequence.current = 0
+	for _, animation := range sequence.animations {
+		animation.Reset()
+	}
+}
+
+// Group plays animations at the same time and finishes with the last of them
+type Group struct {
+	OnComplete func()
+
+	animations []Animation
+	finished   []bool
+	done       bool
+}
+
+// NewGroup creates a new group of the animations
+func NewGroup(animations ...Animation) *Group {
+	return &Group{animations: animations, finished: make([]bool, len(animations))}
+}
+
+// Update advances all running animations by dt
+func (group *Group) Update(dt float64) (float64, bool) {
+	if group.done {
+		return dt, true
+	}
+
+	remaining := dt
+	done := true
+	for i, animation := range group.animations {
+		if group.finished[i] {
+			continue
+		}
+		left, finished := animation.Update(dt)
+		group.finished[i] = finished
+		done = done && finished
+		remaining = math.Min(remaining, left)
+	}
+	if !done {
+		return 0, false
+	}
+
+	group.done = true
+	if group.OnComplete != nil {
+		group.OnComplete()
+	}
+	return remaining, true
+}
+
+// Reset rewinds the group and all its animations
+func (group *Group) Reset() {
+	group.done = false
+	for i, animation := range group.animations {
+		group.finished[i] = false
+		animation.Reset()
+	}
+}
+
+// Tweener advances a set of running animations and drops them once they are finished
+type Tweener struct {
+	animations []*tweenerEntry
+}
+
+// tweenerEntry is a running animation, removed marks entries dropped while an update runs
+type tweenerEntry struct {
+	animation Animation
+	removed   bool
+}
+
+// NewTweener creates a new empty tweener
+func NewTweener() *Tweener {
+	return &Tweener{}
+}
+
+// Add starts running the animation
+func (tweener *Tweener) Add(animation Animation) {
+	tweener.animations = append(tweener.animations, &tweenerEntry{animation: animation})
+}
+
+// Remove stops running the animation without completing it
+func (tweener *Tweener) Remove(animation Animation) {
+	tweener.animations = slices.DeleteFunc(tweener.animations, func(entry *tweenerEntry) bool {
+		entry.removed = entry.removed || entry.animation == animation
+		return entry.removed
+	})
+}
+
+// Len returns the number of running animations
+func (tweener *Tweener) Len() int {
+	return len(tweener.animations)
+}
+
+// Clear stops all animations
+func (tweener *Tweener) Clear() {
+	for _, entry := range tweener.animations {
+		entry.removed = true
+	}
+	tweener.animations = nil
+}
+
+// Update advances all animations by dt. Animations added by callbacks during
+// the update start with the next update, animations removed by callbacks are
+// not updated anymore.
+func (tweener *Tweener) Update(dt float64) {
+	finished := false
+	for _, entry := range slices.Clone(tweener.animations) {
+		if entry.removed {
+			continue
+		}
+		if _, done := entry.animation.Update(dt); done {
+			entry.removed = true
+			finished = true
+		}
+	}
+
+	if finished {
+		tweener.animations = slices.DeleteFunc(tweener.animations, func(entry *tweenerEntry) bool {
+			return entry.removed
+		})
+	}
+}
+
+// lerpVector interpolates linearly between two vectors
+func lerpVector(a, b Vector2D[float64], t float64) Vector2D[float64] {
+	return a.Add(b.Subtract(a).Multiply(t))
+}
@@ -0,0 +1,212 @@
+package maths
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTween(t *testing.T) {
+	t.Parallel()
+
+	var value float64
+	completed := 0
+	tween := NewFloatTween(10, 20, 2, func(v float64) { value = v })
+	tween.Delay = 1
+	tween.OnComplete = func() { completed++ }
+
+	_, finished := tween.Update(0.5)
+	assert.False(t, finished)
+	assert.Zero(t, value, "nothing is set during the delay")
+
+	tween.Update(1.5)
+	assert.InDelta(t, 15, value, 1e-12)
+
+	remaining, finished := tween.Update(1.5)
+	assert.True(t, finished)
+	assert.InDelta(t, 0.5, remaining, 1e-12)
+	assert.InDelta(t, 20, value, 1e-12)
+	assert.Equal(t, 1, completed)
+	assert.True(t, tween.Finished())
+
+	tween.Update(1)
+	assert.Equal(t, 1, completed, "callbacks fire once")
+
+	tween.Reset()
+	tween.Easing = EaseInQuad
+	tween.Update(2)
+	assert.InDelta(t, 12.5, value, 1e-12)
+}
+
+func TestTweenLoops(t *testing.T) {
+	t.Parallel()
+
+	var value Vector2D[float64]
+	tween := NewVectorTween(Vector2D[float64]{}, Vector2D[float64]{X: 10, Y: -10}, 1, func(v Vector2D[float64]) { value = v })
+	tween.Loops = 2
+	tween.Yoyo = true
+
+	tween.Update(0.25)
+	assert.InDelta(t, 2.5, value.X, 1e-12)
+	tween.Update(1)
+	assert.InDelta(t, 7.5, value.X, 1e-12, "second repetition runs backwards")
+	tween.Update(1)
+	assert.InDelta(t, 2.5, value.X, 1e-12)
+	assert.InDelta(t, -2.5, value.Y, 1e-12)
+
+	remaining, finished := tween.Update(1)
+	assert.True(t, finished)
+	assert.InDelta(t, 0.25, remaining, 1e-12)
+	assert.Equal(t, Vector2D[float64]{X: 10, Y: -10}, value)
+
+	// Repeating forever never finishes
+	tween.Reset()
+	tween.Loops = -1
+	_, finished = tween.Update(100.5)
+	assert.False(t, finished)
+}
+
+func TestHexAndZoomTween(t *testing.T) {
+	t.Parallel()
+
+	var hex Hex[float64]
+	NewHexTween(Hex[float64]{Q: 0, R: 0}, Hex[float64]{Q: 2, R: -1}, 1, func(h Hex[float64]) { hex = h }).Update(0.5)
+	assert.Equal(t, Hex[float64]{Q: 1, R: -0.5}, hex)
+
+	var zoom float64
+	tween := NewZoomTween(1, 4, 1, func(z float64) { zoom = z })
+	tween.Update(0.5)
+	assert.InDelta(t, 2, zoom, 1e-12, "zoom changes exponentially")
+
+	// Zero duration finishes immediately
+	_, finished := NewZoomTween(1, 3, 0, func(z float64) { zoom = z }).Update(0)
+	assert.True(t, finished)
+	assert.InDelta(t, 3, zoom, 1e-12)
+}
+
+func TestSequence(t *testing.T) {
+	t.Parallel()
+
+	var position, scale float64
+	var events []string
+	move := NewFloatTween(0, 10, 1, func(v float64) { position = v })
+	move.OnComplete = func() { events = append(events, "move") }
+	grow := NewFloatTween(1, 2, 1, func(v float64) { scale = v })
+	grow.OnComplete = func() { events = append(events, "grow") }
+
+	sequence := NewSequence(move, NewWait(0.5), grow)
+	sequence.OnComplete = func() { events = append(events, "sequence") }
+
+	sequence.Update(1.25)
+	assert.InDelta(t, 10, position, 1e-12)
+	assert.Zero(t, scale)
+
+	sequence.Update(0.5)
+	assert.InDelta(t, 1.25, scale, 1e-12, "time left by the wait is passed on")
+
+	remaining, finished := sequence.Update(1)
+	assert.True(t, finished)
+	assert.InDelta(t, 0.25, remaining, 1e-12)
+	assert.Equal(t, []string{"move", "grow", "sequence"}, events)
+
+	// Looping sequence restarts the animations
+	sequence.Reset()
+	sequence.Loops = 1
+	sequence.Update(2.5 + 0.5)
+	assert.InDelta(t, 5, position, 1e-12)
+	assert.InDelta(t, 2, scale, 1e-12)
+
+	// Empty sequences repeating forever still finish
+	_, finished = (&Sequence{Loops: -1}).Update(1)
+	assert.True(t, finished)
+}
+
+func TestGroupAndTweener(t *testing.T) {
+	t.Parallel()
+
+	var a, b float64
+	group := NewGroup(
+		NewFloatTween(0, 1, 1, func(v float64) { a = v }),
+		NewFloatTween(0, 1, 2, func(v float64) { b = v }),
+	)
+
+	tweener := NewTweener()
+	tweener.Add(group)
+	completed := false
+	group.OnComplete = func() {
+		completed = true
+		tweener.Add(NewWait(1))
+	}
+
+	tweener.Update(1.5)
+	assert.InDelta(t, 1, a, 1e-12)
+	assert.InDelta(t, 0.75, b, 1e-12)
+	assert.Equal(t, 1, tweener.Len())
+
+	tweener.Update(1)
+	assert.True(t, completed)
+	assert.InDelta(t, 1, b, 1e-12)
+	assert.Equal(t, 1, tweener.Len(), "group is dropped, the wait added by the callback runs")
+
+	wait := NewWait(5)
+	tweener.Add(wait)
+	tweener.Remove(wait)
+	assert.Equal(t, 1, tweener.Len())
+
+	tweener.Clear()
+	assert.Zero(t, tweener.Len())
+}
+
+func TestTweenerCallbacks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Remove", func(t *testing.T) {
+		t.Parallel()
+
+		tweener := NewTweener()
+		updated := 0
+		first := NewFloatTween(0, 1, 1, func(float64) {})
+		second := NewWait(5)
+		third := NewFloatTween(0, 1, 5, func(float64) { updated++ })
+
+		lenInCallback := 0
+		first.OnComplete = func() {
+			tweener.Remove(second)
+			tweener.Remove(third)
+			lenInCallback = tweener.Len()
+		}
+		tweener.Add(first)
+		tweener.Add(second)
+		tweener.Add(third)
+
+		tweener.Update(1)
+		assert.Equal(t, 1, lenInCallback, "the finishing animation counts until the update ends")
+		assert.Zero(t, updated, "removed animations are not updated")
+		assert.Zero(t, tweener.Len())
+	})
+
+	t.Run("Clear", func(t *testing.T) {
+		t.Parallel()
+
+		tweener := NewTweener()
+		updated := 0
+		first := NewFloatTween(0, 1, 1, func(float64) {})
+		second := NewFloatTween(0, 1, 5, func(float64) { updated++ })
+		restarted := NewWait(1)
+
+		lenInCallback := -1
+		first.OnComplete = func() {
+			tweener.Clear()
+			lenInCallback = tweener.Len()
+			tweener.Add(restarted)
+			tweener.Add(first)
+		}
+		tweener.Add(first)
+		tweener.Add(second)
+
+		tweener.Update(1)
+		assert.Equal(t, 0, lenInCallback)
+		assert.Zero(t, updated, "cleared animations are not updated")
+		assert.Equal(t, 2, tweener.Len(), "the animations added after clearing run, even the finished one")
+	})
+}
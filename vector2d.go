@@ -71,6 +71,34 @@ func (v Vector2D[T]) Distance(other Vector2D[T]) float64 {
 	return math.Sqrt(dx*dx + dy*dy)
 }
 
+// Dot returns the dot product of two vectors
+func (v Vector2D[T]) Dot(other Vector2D[T]) T {
+	return v.X*other.X + v.Y*other.Y
+}
+
+// Cross returns the z component of the cross product of two vectors
+func (v Vector2D[T]) Cross(other Vector2D[T]) T {
+	return v.X*other.Y - v.Y*other.X
+}
+
+// Length returns the Euclidean length of the vector
+func (v Vector2D[T]) Length() float64 {
+	x, y := float64(v.X), float64(v.Y)
+	return math.Sqrt(x*x + y*y)
+}
+
+// Normalize returns the vector scaled to length 1, or the zero vector for a zero length vector
+func (v Vector2D[T]) Normalize() Vector2D[float64] {
+	length := v.Length()
+	if length == 0 {
+		return Vector2D[float64]{}
+	}
+	return Vector2D[float64]{
+		X: float64(v.X) / length,
+		Y: float64(v.Y) / length,
+	}
+}
+
 // ToInt converts the vector to a Vector2D with int64 components
 func (v Vector2D[T]) ToInt() Vector2D[int64] {
 	return Vector2D[int64]{
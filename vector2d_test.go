@@ -208,3 +208,59 @@ func TestToFloat(t *testing.T) {
 		})
 	}
 }
+
+func TestDotAndCross(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		v1    Vector2D[int64]
+		v2    Vector2D[int64]
+		dot   int64
+		cross int64
+	}{
+		{Vector2D[int64]{X: 0, Y: 0}, Vector2D[int64]{X: 1, Y: 1}, 0, 0},
+		{Vector2D[int64]{X: 1, Y: 0}, Vector2D[int64]{X: 0, Y: 1}, 0, 1},
+		{Vector2D[int64]{X: 0, Y: 1}, Vector2D[int64]{X: 1, Y: 0}, 0, -1},
+		{Vector2D[int64]{X: 2, Y: 3}, Vector2D[int64]{X: 4, Y: -5}, -7, -22},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test %d", i), func(t *testing.T) {
+			t.Parallel()
+
+			if result := tt.v1.Dot(tt.v2); result != tt.dot {
+				t.Errorf("Dot(%v, %v) = %v; expected %v", tt.v1, tt.v2, result, tt.dot)
+			}
+			if result := tt.v1.Cross(tt.v2); result != tt.cross {
+				t.Errorf("Cross(%v, %v) = %v; expected %v", tt.v1, tt.v2, result, tt.cross)
+			}
+		})
+	}
+}
+
+func TestLengthAndNormalize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		v          Vector2D[float64]
+		length     float64
+		normalized Vector2D[float64]
+	}{
+		{Vector2D[float64]{X: 0, Y: 0}, 0, Vector2D[float64]{X: 0, Y: 0}},
+		{Vector2D[float64]{X: 3, Y: 4}, 5, Vector2D[float64]{X: 0.6, Y: 0.8}},
+		{Vector2D[float64]{X: -2, Y: 0}, 2, Vector2D[float64]{X: -1, Y: 0}},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test %d", i), func(t *testing.T) {
+			t.Parallel()
+
+			if result := tt.v.Length(); result != tt.length {
+				t.Errorf("Length(%v) = %v; expected %v", tt.v, result, tt.length)
+			}
+			if result := tt.v.Normalize(); result != tt.normalized {
+				t.Errorf("Normalize(%v) = %v; expected %v", tt.v, result, tt.normalized)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package maths
+
+// VoronoiCells returns the Voronoi cell of every seed clipped to the bounds.
+// Cells are counter-clockwise polygons in the order of the seeds and are
+// computed from the Delaunay neighbours of each seed. Duplicated seeds leave
+// the cell to their first occurrence and get an empty cell.
+func VoronoiCells(seeds []Vector2D[float64], bounds Rect) [][]Vector2D[float64] {
+	cells := make([][]Vector2D[float64], len(seeds))
+	neighbours := voronoiNeighbours(seeds)
+
+	first := make(map[Vector2D[float64]]int, len(seeds))
+	for i, seed := range seeds {
+		if _, ok := first[seed]; ok {
+			continue
+		}
+		first[seed] = i
+
+		cell := []Vector2D[float64]{
+			bounds.Min,
+			{X: bounds.Max.X, Y: bounds.Min.Y},
+			bounds.Max,
+			{X: bounds.Min.X, Y: bounds.Max.Y},
+		}
+		for _, j := range neighbours[i] {
+			if seeds[j] == seed {
+				continue
+			}
+			cell = clipHalfPlane(cell, seed, seeds[j])
+		}
+		cells[i] = cell
+	}
+	return cells
+}
+
+// HexVoronoi assigns every hex of the region to the index of the seed with the
+// smallest hex distance. Ties go to the seed with the lower index, so the result
+// does not depend on map iteration order.
+func HexVoronoi[V any](region HexMap[V], seeds []Hex[int64]) HexMap[int] {
+	result := make(HexMap[int], len(region))
+	if len(seeds) == 0 {
+		return result
+	}
+
+	for h := range region {
+		best, bestDistance := 0, h.Distance(seeds[0])
+		for i, seed := range seeds[1:] {
+			if distance := h.Distance(seed); distance < bestDistance {
+				best, bestDistance = i+1, distance
+			}
+		}
+		result[h] = best
+	}
+	return result
+}
+
+// voronoiNeighbours returns the indices of the seeds sharing a Delaunay edge with each seed.
+// Collinear seeds have no triangulation, then every seed is a neighbour of every other.
+func voronoiNeighbours(seeds []Vector2D[float64]) [][]int {
+	neighbours := make([][]int, len(seeds))
+
+	// Delaunay without constraints never fails
+	indices, _ := Delaunay(seeds)
+	if len(indices) == 0 {
+		for i := range seeds {
+			for j := range seeds {
+				if i != j {
+					neighbours[i] = append(neighbours[i], j)
+				}
+			}
+		}
+		return neighbours
+	}
+
+	seen := make(map[[2]int]bool, len(indices))
+	for t := 0; t < len(indices); t += 3 {
+		for k := 0; k < 3; k++ {
+			a, b := indices[t+k], indices[t+(k+1)%3]
+			if a > b {
+				a, b = b, a
+			}
+			if seen[[2]int{a, b}] {
+				continue
+			}
+			seen[[2]int{a, b}] = true
+			neighbours[a] = append(neighbours[a], b)
+			neighbours[b] = append(neighbours[b], a)
+		}
+	}
+	return neighbours
+}
+
+// clipHalfPlane keeps the part of the convex polygon which is closer to seed than to other
+func clipHalfPlane(polygon []Vector2D[float64], seed, other Vector2D[float64]) []Vector2D[float64] {
+	normal := other.Subtract(seed)
+	middle := seed.Add(other).Divide(2)
+	side := func(p Vector2D[float64]) float64 {
+		return p.Subtract(middle).Dot(normal)
+	}
+
+	var result []Vector2D[float64]
+	for i, a := range polygon {
+		b := polygon[(i+1)%len(polygon)]
+		sa, sb := side(a), side(b)
+		if sa <= 0 {
+			result = append(result, a)
+		}
+		if sa < 0 && sb > 0 || sa > 0 && sb < 0 {
+			t := sa / (sa - sb)
+			result = append(result, a.Add(b.Subtract(a).Multiply(t)))
+		}
+	}
+	return result
+}
@@ -0,0 +1,101 @@
+package maths
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVoronoiCells(t *testing.T) {
+	t.Parallel()
+
+	bounds := NewRect(Vector2D[float64]{X: -10, Y: -10}, Vector2D[float64]{X: 110, Y: 60})
+	grid := make([]Vector2D[float64], 0, 16)
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			grid = append(grid, Vector2D[float64]{X: float64(x * 10), Y: float64(y * 10)})
+		}
+	}
+	var ring []Vector2D[float64]
+	for i := range 12 {
+		angle := 2 * math.Pi * float64(i) / 12
+		ring = append(ring, Vector2D[float64]{X: 50 + 20*math.Cos(angle), Y: 25 + 20*math.Sin(angle)})
+	}
+
+	tests := []struct {
+		name  string
+		seeds []Vector2D[float64]
+	}{
+		{name: "Scattered", seeds: testPoints(200, NewRect(Vector2D[float64]{}, Vector2D[float64]{X: 100, Y: 50}))},
+		{name: "Cocircular grid", seeds: grid},
+		{name: "Regular polygon", seeds: ring},
+		{name: "Collinear", seeds: []Vector2D[float64]{{X: 0, Y: 0}, {X: 30, Y: 30}, {X: 10, Y: 10}}},
+		{name: "Single", seeds: []Vector2D[float64]{{X: 5, Y: 5}}},
+		{name: "Outside bounds", seeds: []Vector2D[float64]{{X: -50, Y: 0}, {X: 50, Y: 20}, {X: 200, Y: 30}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cells := VoronoiCells(tt.seeds, bounds)
+			assert.Len(t, cells, len(tt.seeds))
+
+			total := 0.0
+			for i, cell := range cells {
+				if len(cell) == 0 {
+					continue
+				}
+				assert.GreaterOrEqual(t, SignedArea(cell), 0.0)
+				total += Area(cell)
+
+				// Every cell vertex is at least as close to its seed as to any other
+				for _, p := range cell {
+					assert.True(t, bounds.Expand(1e-9).Contains(p))
+					for _, other := range tt.seeds {
+						assert.LessOrEqual(t, p.Distance(tt.seeds[i]), p.Distance(other)+1e-9)
+					}
+				}
+			}
+			assert.InDelta(t, bounds.Width()*bounds.Height(), total, 1e-6)
+		})
+	}
+}
+
+func TestVoronoiCellsDuplicates(t *testing.T) {
+	t.Parallel()
+
+	bounds := NewRect(Vector2D[float64]{}, Vector2D[float64]{X: 10, Y: 10})
+	seeds := []Vector2D[float64]{{X: 2, Y: 5}, {X: 8, Y: 5}, {X: 2, Y: 5}}
+	cells := VoronoiCells(seeds, bounds)
+
+	assert.InDelta(t, 50, Area(cells[0]), 1e-9)
+	assert.InDelta(t, 50, Area(cells[1]), 1e-9)
+	assert.Empty(t, cells[2])
+}
+
+func TestHexVoronoi(t *testing.T) {
+	t.Parallel()
+
+	region := NewHexMap(Hex[int64]{}.Spiral(8), struct{}{})
+	seeds := []Hex[int64]{{Q: -4, R: 0}, {Q: 4, R: 0}, {Q: 0, R: 4}, {Q: 2, R: -6}}
+	result := HexVoronoi(region, seeds)
+
+	assert.Len(t, result, len(region))
+	for h, index := range result {
+		for i, seed := range seeds {
+			distance := h.Distance(seed)
+			best := h.Distance(seeds[index])
+			assert.LessOrEqual(t, best, distance)
+			if distance == best {
+				assert.LessOrEqual(t, index, i, "ties go to the lower index")
+			}
+		}
+	}
+
+	// The hex between the first two seeds is a tie
+	assert.Equal(t, 0, result[Hex[int64]{Q: 0, R: 0}])
+	assert.Equal(t, 1, result[Hex[int64]{Q: 4, R: 0}])
+	assert.Empty(t, HexVoronoi(region, nil))
+}